@@ -12,19 +12,70 @@ import (
 
 type ServerOptions struct {
 	MYSQLOptions *genericoptions.MySQLOptions `json:"mysql" mapstructure:"mysql"`
-	Addr         string                       `json:"addr" mapstructure:"addr"`
+	// ViewCounterOptions 定义博文浏览计数相关配置.
+	ViewCounterOptions *genericoptions.ViewCounterOptions `json:"view-counter" mapstructure:"view-counter"`
+	// ModerationOptions 定义内容审核相关配置.
+	ModerationOptions *genericoptions.ModerationOptions `json:"moderation" mapstructure:"moderation"`
+	// AuthzOptions 定义基于角色的授权相关配置.
+	AuthzOptions *genericoptions.AuthzOptions `json:"authz" mapstructure:"authz"`
+	// MailOptions 定义验证邮箱和重置密码邮件相关配置.
+	MailOptions *genericoptions.MailOptions `json:"mail" mapstructure:"mail"`
+	// LockoutOptions 定义登录暴力破解防护相关配置.
+	LockoutOptions *genericoptions.LockoutOptions `json:"lockout" mapstructure:"lockout"`
+	// PasswordOptions 定义密码规则及密码哈希相关配置.
+	PasswordOptions *genericoptions.PasswordOptions `json:"password" mapstructure:"password"`
+	// OIDCOptions 定义外部 OpenID Connect 登录相关配置.
+	OIDCOptions *genericoptions.OIDCOptions `json:"oidc" mapstructure:"oidc"`
+	// MTLSOptions 定义内部服务使用 TLS 客户端证书访问的相关配置.
+	MTLSOptions *genericoptions.MTLSOptions `json:"mtls" mapstructure:"mtls"`
+	// RateLimitOptions 定义请求限流相关配置.
+	RateLimitOptions *genericoptions.RateLimitOptions `json:"rate-limit" mapstructure:"rate-limit"`
+	// MetricsOptions 定义 Prometheus 指标相关配置.
+	MetricsOptions *genericoptions.MetricsOptions `json:"metrics" mapstructure:"metrics"`
+	// TracingOptions 定义 OpenTelemetry 链路追踪相关配置.
+	TracingOptions *genericoptions.TracingOptions `json:"tracing" mapstructure:"tracing"`
+	// IdempotencyOptions 定义创建资源接口的 Idempotency-Key 相关配置.
+	IdempotencyOptions *genericoptions.IdempotencyOptions `json:"idempotency" mapstructure:"idempotency"`
+	// CompressionOptions 定义响应压缩相关配置.
+	CompressionOptions *genericoptions.CompressionOptions `json:"compression" mapstructure:"compression"`
+	// RequestLimitOptions 定义请求体大小和请求处理时限相关配置.
+	RequestLimitOptions *genericoptions.RequestLimitOptions `json:"request-limit" mapstructure:"request-limit"`
+	Addr                string                              `json:"addr" mapstructure:"addr"`
+	// TrustedProxies 定义可信的反向代理 IP 或网段，只有来自这些地址的请求才会使用 X-Forwarded-For 等请求头确定客户端 IP.
+	// 为空时不信任任何代理，客户端 IP 取连接的对端地址.
+	TrustedProxies []string `json:"trusted-proxies" mapstructure:"trusted-proxies"`
 	// JWTKey 定义 JWT 密钥.
 	JWTKey string `json:"jwt-key" mapstructure:"jwt-key"`
+	// JWTKeyOptions 定义 JWT 非对称签名密钥配置，配置后使用 RS256 或 EdDSA 签发 token.
+	JWTKeyOptions *genericoptions.JWTKeyOptions `json:"jwt-keys" mapstructure:"jwt-keys"`
 	// Expiration 定义 JWT Token 的过期时间.
 	Expiration time.Duration `json:"expiration" mapstructure:"expiration"`
+	// RefreshExpiration 定义 Refresh Token 的过期时间.
+	RefreshExpiration time.Duration `json:"refresh-expiration" mapstructure:"refresh-expiration"`
 }
 
 // NewServerOptions 创建带有默认值的 ServerOptions 实例
 func NewServerOptions() *ServerOptions {
 	return &ServerOptions{
-		MYSQLOptions: genericoptions.NewMySQLOptions(),
-		Addr:         "0.0.0.0:6666",
-		Expiration:   2 * time.Hour,
+		MYSQLOptions:        genericoptions.NewMySQLOptions(),
+		ViewCounterOptions:  genericoptions.NewViewCounterOptions(),
+		ModerationOptions:   genericoptions.NewModerationOptions(),
+		AuthzOptions:        genericoptions.NewAuthzOptions(),
+		MailOptions:         genericoptions.NewMailOptions(),
+		LockoutOptions:      genericoptions.NewLockoutOptions(),
+		PasswordOptions:     genericoptions.NewPasswordOptions(),
+		OIDCOptions:         genericoptions.NewOIDCOptions(),
+		MTLSOptions:         genericoptions.NewMTLSOptions(),
+		RateLimitOptions:    genericoptions.NewRateLimitOptions(),
+		MetricsOptions:      genericoptions.NewMetricsOptions(),
+		TracingOptions:      genericoptions.NewTracingOptions(),
+		IdempotencyOptions:  genericoptions.NewIdempotencyOptions(),
+		CompressionOptions:  genericoptions.NewCompressionOptions(),
+		RequestLimitOptions: genericoptions.NewRequestLimitOptions(),
+		JWTKeyOptions:       genericoptions.NewJWTKeyOptions(),
+		Addr:                "0.0.0.0:6666",
+		Expiration:          15 * time.Minute,
+		RefreshExpiration:   30 * 24 * time.Hour,
 	}
 }
 
@@ -100,20 +151,130 @@ func (o *ServerOptions) Validate() error {
 		return fmt.Errorf("invalid server port: %s", portStr)
 	}
 
+	// 校验可信代理，每一项必须是 IP 或 CIDR 网段
+	for _, proxy := range o.TrustedProxies {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			return fmt.Errorf("invalid trusted proxy '%s': must be an IP address or CIDR", proxy)
+		}
+	}
+
 	// 校验 JWTKey 长度
 	if len(o.JWTKey) < 6 {
 		return fmt.Errorf("JWTKey must be at least 6 characters long")
 	}
 
+	// 校验 JWT 非对称签名密钥配置
+	if err := o.JWTKeyOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验 Token 过期时间，Refresh Token 的有效期应长于 JWT Token
+	if o.Expiration <= 0 {
+		return fmt.Errorf("JWT expiration must be greater than 0")
+	}
+	if o.RefreshExpiration <= o.Expiration {
+		return fmt.Errorf("refresh expiration must be greater than JWT expiration")
+	}
+
+	// 校验博文浏览计数配置
+	if err := o.ViewCounterOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验内容审核配置
+	if err := o.ModerationOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验授权配置
+	if err := o.AuthzOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验邮件配置
+	if err := o.MailOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验登录暴力破解防护配置
+	if err := o.LockoutOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验密码规则及密码哈希配置
+	if err := o.PasswordOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验外部 OIDC 登录配置
+	if err := o.OIDCOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验 mTLS 配置
+	if err := o.MTLSOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验限流配置
+	if err := o.RateLimitOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验指标配置
+	if err := o.MetricsOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验链路追踪配置
+	if err := o.TracingOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验幂等配置
+	if err := o.IdempotencyOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验响应压缩配置
+	if err := o.CompressionOptions.Validate(); err != nil {
+		return err
+	}
+
+	// 校验请求限制配置
+	if err := o.RequestLimitOptions.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // Config 基于 ServerOptions 构建 apiserver.Config
 func (o *ServerOptions) Config() (*apiserver.Config, error) {
 	return &apiserver.Config{
-		MySQLOptions: o.MYSQLOptions,
-		Addr:         o.Addr,
-		JWTKey:       o.JWTKey,
-		Expiration:   o.Expiration,
+		MySQLOptions:        o.MYSQLOptions,
+		ViewCounterOptions:  o.ViewCounterOptions,
+		ModerationOptions:   o.ModerationOptions,
+		AuthzOptions:        o.AuthzOptions,
+		MailOptions:         o.MailOptions,
+		LockoutOptions:      o.LockoutOptions,
+		PasswordOptions:     o.PasswordOptions,
+		OIDCOptions:         o.OIDCOptions,
+		MTLSOptions:         o.MTLSOptions,
+		RateLimitOptions:    o.RateLimitOptions,
+		MetricsOptions:      o.MetricsOptions,
+		TracingOptions:      o.TracingOptions,
+		IdempotencyOptions:  o.IdempotencyOptions,
+		CompressionOptions:  o.CompressionOptions,
+		RequestLimitOptions: o.RequestLimitOptions,
+		Addr:                o.Addr,
+		TrustedProxies:      o.TrustedProxies,
+		JWTKey:              o.JWTKey,
+		JWTKeyOptions:       o.JWTKeyOptions,
+		Expiration:          o.Expiration,
+		RefreshExpiration:   o.RefreshExpiration,
 	}, nil
 }
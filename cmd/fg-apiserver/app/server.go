@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/TobyIcetea/fastgo/cmd/fg-apiserver/app/options"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
 	"github.com/TobyIcetea/fastgo/pkg/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -136,6 +137,6 @@ func initLog() {
 		handler = slog.NewJSONHandler(w, opts)
 	}
 
-	// 设置全局的日志实例为自定义的日志实例
-	slog.SetDefault(slog.New(handler))
+	// 设置全局的日志实例为自定义的日志实例，使用带 context 的函数输出日志时自动追加请求 ID、用户 ID 及已认证的调用方
+	slog.SetDefault(slog.New(contextx.NewLogHandler(handler)))
 }
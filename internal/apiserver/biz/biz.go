@@ -1,9 +1,20 @@
 package biz
 
 import (
+	accesstokenv1 "github.com/TobyIcetea/fastgo/internal/apiserver/biz/v1/accesstoken"
+	impersonationv1 "github.com/TobyIcetea/fastgo/internal/apiserver/biz/v1/impersonation"
 	postv1 "github.com/TobyIcetea/fastgo/internal/apiserver/biz/v1/post"
+	reportv1 "github.com/TobyIcetea/fastgo/internal/apiserver/biz/v1/report"
+	sessionv1 "github.com/TobyIcetea/fastgo/internal/apiserver/biz/v1/session"
 	userv1 "github.com/TobyIcetea/fastgo/internal/apiserver/biz/v1/user"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/contentfilter"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/lockout"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/mailer"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/oidc"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/revoker"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/viewcounter"
 	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
 )
 
 // IBiz 定义了业务层需要实现的方法
@@ -12,29 +23,67 @@ type IBiz interface {
 	UserV1() userv1.UserBiz
 	// 获取帖子业务接口
 	PostV1() postv1.PostBiz
+	// 获取举报和内容审核业务接口
+	ReportV1() reportv1.ReportBiz
+	// 获取个人访问令牌业务接口
+	AccessTokenV1() accesstokenv1.AccessTokenBiz
+	// 获取登录会话业务接口
+	SessionV1() sessionv1.SessionBiz
+	// 获取管理员模拟登录业务接口
+	ImpersonationV1() impersonationv1.ImpersonationBiz
 	// 获取帖子业务接口（v2版本）
 	// PostV2() post.PostBiz
 }
 
 // biz 是 IBiz 的一个具体实现
 type biz struct {
-	store store.IStore
+	store   store.IStore
+	views   *viewcounter.Counter
+	filter  *contentfilter.Filter
+	revoker *revoker.Revoker
+	// notifier 用于发送验证邮箱和重置密码的邮件
+	notifier *mailer.Notifier
+	mailOpts *genericoptions.MailOptions
+	// lockout 用于登录的暴力破解防护
+	lockout *lockout.Lockout
+	// providers 保存可用于登录的外部 OIDC 提供方
+	providers *oidc.Providers
 }
 
 // 确保 biz 实现了 IBiz 接口
 var _ IBiz = (*biz)(nil)
 
 // NewBiz 创建了一个 IBiz 类型的实例
-func NewBiz(store store.IStore) *biz {
-	return &biz{store: store}
+func NewBiz(store store.IStore, views *viewcounter.Counter, filter *contentfilter.Filter, revoker *revoker.Revoker, notifier *mailer.Notifier, mailOpts *genericoptions.MailOptions, lockout *lockout.Lockout, providers *oidc.Providers) *biz {
+	return &biz{store: store, views: views, filter: filter, revoker: revoker, notifier: notifier, mailOpts: mailOpts, lockout: lockout, providers: providers}
 }
 
 // UserV1 返回一个实现了 UserBiz 接口的实例
 func (b *biz) UserV1() userv1.UserBiz {
-	return userv1.New(b.store)
+	return userv1.New(b.store, b.revoker, b.notifier, b.mailOpts, b.lockout, b.providers)
 }
 
 // PostV1 返回一个实现了 PostBiz 接口的实例
 func (b *biz) PostV1() postv1.PostBiz {
-	return postv1.New(b.store)
+	return postv1.New(b.store, b.views, b.filter, b.mailOpts.RequireVerifiedEmail)
+}
+
+// ReportV1 返回一个实现了 ReportBiz 接口的实例
+func (b *biz) ReportV1() reportv1.ReportBiz {
+	return reportv1.New(b.store, b.revoker)
+}
+
+// AccessTokenV1 返回一个实现了 AccessTokenBiz 接口的实例
+func (b *biz) AccessTokenV1() accesstokenv1.AccessTokenBiz {
+	return accesstokenv1.New(b.store)
+}
+
+// SessionV1 返回一个实现了 SessionBiz 接口的实例
+func (b *biz) SessionV1() sessionv1.SessionBiz {
+	return sessionv1.New(b.store, b.revoker)
+}
+
+// ImpersonationV1 返回一个实现了 ImpersonationBiz 接口的实例
+func (b *biz) ImpersonationV1() impersonationv1.ImpersonationBiz {
+	return impersonationv1.New(b.store, b.revoker)
 }
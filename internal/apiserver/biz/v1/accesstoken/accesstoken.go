@@ -0,0 +1,165 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/conversion"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// touchInterval 定义更新令牌最后使用时间的最小间隔，避免每个请求都写数据库
+const touchInterval = time.Minute
+
+// AccessTokenBiz 定义处理个人访问令牌请求所需的方法
+type AccessTokenBiz interface {
+	Create(ctx context.Context, rq *apiv1.CreateAccessTokenRequest) (*apiv1.CreateAccessTokenResponse, error)
+	Delete(ctx context.Context, rq *apiv1.DeleteAccessTokenRequest) (*apiv1.DeleteAccessTokenResponse, error)
+	List(ctx context.Context, rq *apiv1.ListAccessTokenRequest) (*apiv1.ListAccessTokenResponse, error)
+
+	AccessTokenExpansion
+}
+
+// AccessTokenExpansion 定义额外的个人访问令牌操作方法
+type AccessTokenExpansion interface {
+	// Verify 校验个人访问令牌，返回令牌所属的调用方，包括用户 ID、用户名、角色及令牌的权限范围
+	Verify(ctx context.Context, plain string) (*contextx.Principal, error)
+}
+
+// accessTokenBiz 是 AccessTokenBiz 接口的实现
+type accessTokenBiz struct {
+	store store.IStore
+}
+
+// 确保 accessTokenBiz 实现了 AccessTokenBiz 接口
+var _ AccessTokenBiz = (*accessTokenBiz)(nil)
+
+// New 创建 accessTokenBiz 的实例
+func New(store store.IStore) *accessTokenBiz {
+	return &accessTokenBiz{store: store}
+}
+
+// Create 实现 AccessTokenBiz 接口中的 Create 方法. 令牌值只在创建时返回一次，数据库中只保存其哈希值.
+func (b *accessTokenBiz) Create(ctx context.Context, rq *apiv1.CreateAccessTokenRequest) (*apiv1.CreateAccessTokenResponse, error) {
+	ctx, span := tracing.Start(ctx, "AccessTokenBiz.Create")
+	defer span.End()
+
+	plain, _, err := token.GenerateOpaque()
+	if err != nil {
+		return nil, errorsx.ErrInternal.WithMessage("%s", err.Error())
+	}
+	plain = known.AccessTokenPrefix + plain
+
+	tokenM := model.AccessToken{
+		UserID:    contextx.UserID(ctx),
+		Name:      rq.Name,
+		TokenHash: token.HashOpaque(plain),
+		Scopes:    strings.Join(rq.Scopes, " "),
+		ExpiresAt: rq.ExpiresAt,
+	}
+	if err := b.store.AccessToken().Create(ctx, &tokenM); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.CreateAccessTokenResponse{TokenID: tokenM.TokenID, Token: plain, ExpiresAt: tokenM.ExpiresAt}, nil
+}
+
+// Delete 实现 AccessTokenBiz 接口中的 Delete 方法，吊销当前用户的个人访问令牌
+func (b *accessTokenBiz) Delete(ctx context.Context, rq *apiv1.DeleteAccessTokenRequest) (*apiv1.DeleteAccessTokenResponse, error) {
+	ctx, span := tracing.Start(ctx, "AccessTokenBiz.Delete")
+	defer span.End()
+
+	whr := where.F("userID", contextx.UserID(ctx), "tokenID", rq.TokenID)
+	if _, err := b.store.AccessToken().Get(ctx, whr); err != nil {
+		return nil, err
+	}
+
+	if err := b.store.AccessToken().Delete(ctx, whr); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.DeleteAccessTokenResponse{}, nil
+}
+
+// List 实现 AccessTokenBiz 接口中的 List 方法，返回当前用户的个人访问令牌列表
+func (b *accessTokenBiz) List(ctx context.Context, rq *apiv1.ListAccessTokenRequest) (*apiv1.ListAccessTokenResponse, error) {
+	ctx, span := tracing.Start(ctx, "AccessTokenBiz.List")
+	defer span.End()
+
+	whr := where.P(int(rq.Offset), int(rq.Limit)).F("userID", contextx.UserID(ctx))
+	count, tokenList, err := b.store.AccessToken().List(ctx, whr)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*apiv1.AccessToken, 0, len(tokenList))
+	for _, tokenM := range tokenList {
+		tokens = append(tokens, conversion.AccessTokenModelToAccessTokenV1(tokenM))
+	}
+
+	return &apiv1.ListAccessTokenResponse{TotalCount: count, AccessTokens: tokens}, nil
+}
+
+// Verify 实现 AccessTokenBiz 接口中的 Verify 方法
+func (b *accessTokenBiz) Verify(ctx context.Context, plain string) (*contextx.Principal, error) {
+	ctx, span := tracing.Start(ctx, "AccessTokenBiz.Verify")
+	defer span.End()
+
+	tokenM, err := b.store.AccessToken().Get(ctx, where.F("tokenHash", token.HashOpaque(plain)))
+	if errors.Is(err, errorsx.ErrAccessTokenNotFound) {
+		return nil, errorsx.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if tokenM.ExpiresAt != nil && now.After(*tokenM.ExpiresAt) {
+		return nil, errorsx.ErrTokenInvalid
+	}
+
+	// 令牌所属的用户被删除或被封禁后，令牌不再可用
+	userM, err := b.store.User().Get(ctx, where.F("userID", tokenM.UserID))
+	if errors.Is(err, errorsx.ErrUserNotFound) {
+		return nil, errorsx.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if userM.Status == known.UserStatusSuspended {
+		return nil, errorsx.ErrUserSuspended
+	}
+
+	// 记录令牌最后使用时间，更新失败不影响本次请求
+	if tokenM.LastUsedAt == nil || now.Sub(*tokenM.LastUsedAt) >= touchInterval {
+		if err := b.store.AccessToken().Touch(ctx, tokenM.ID, now); err != nil {
+			slog.WarnContext(ctx, "Failed to record access token usage", "tokenID", tokenM.TokenID, "err", err)
+		}
+	}
+
+	// 保证返回非 nil 的权限范围，nil 表示不受权限范围限制
+	scopes := strings.Fields(tokenM.Scopes)
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return &contextx.Principal{
+		UserID:     tokenM.UserID,
+		Username:   userM.Username,
+		Roles:      []string{userM.Role},
+		Scopes:     scopes,
+		AuthMethod: known.AuthMethodPAT,
+		TokenID:    tokenM.TokenID,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package accesstoken
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndVerify(t *testing.T) {
+	s := storetest.New(&model.User{UserID: "user-000001", Username: "alice", Role: known.RoleUser})
+	b := New(s)
+	ctx := contextx.WithUserID(context.Background(), "user-000001")
+
+	resp, err := b.Create(ctx, &apiv1.CreateAccessTokenRequest{Name: "ci", Scopes: []string{known.ScopePostsRead, known.ScopePostsWrite}})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.Token, known.AccessTokenPrefix))
+	// 数据库中只保存令牌的哈希值
+	require.Len(t, s.AccessTokens, 1)
+	assert.NotContains(t, s.AccessTokens[0].TokenHash, strings.TrimPrefix(resp.Token, known.AccessTokenPrefix))
+
+	principal, err := b.Verify(context.Background(), resp.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-000001", principal.UserID)
+	assert.Equal(t, "alice", principal.Username)
+	assert.Equal(t, []string{known.RoleUser}, principal.Roles)
+	assert.Equal(t, []string{known.ScopePostsRead, known.ScopePostsWrite}, principal.Scopes)
+	assert.Equal(t, known.AuthMethodPAT, principal.AuthMethod)
+	assert.Equal(t, resp.TokenID, principal.TokenID)
+
+	// 最后使用时间按 touchInterval 节流更新
+	require.NotNil(t, s.AccessTokens[0].LastUsedAt)
+	lastUsedAt := *s.AccessTokens[0].LastUsedAt
+	_, err = b.Verify(context.Background(), resp.Token)
+	require.NoError(t, err)
+	assert.Equal(t, lastUsedAt, *s.AccessTokens[0].LastUsedAt)
+}
+
+func TestVerify(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		user       *model.User
+		expiresAt  *time.Time
+		scopes     []string
+		plain      string
+		wantErr    error
+		wantScopes []string
+	}{
+		{name: "valid", user: &model.User{Status: known.UserStatusActive}, expiresAt: &future, scopes: []string{known.ScopePostsRead}, wantScopes: []string{known.ScopePostsRead}},
+		// 没有任何权限范围的令牌也不能当作不受限制的 token 使用
+		{name: "no scopes", user: &model.User{Status: known.UserStatusActive}, wantScopes: []string{}},
+		{name: "unknown token", user: &model.User{Status: known.UserStatusActive}, plain: known.AccessTokenPrefix + "unknown", wantErr: errorsx.ErrTokenInvalid},
+		{name: "expired", user: &model.User{Status: known.UserStatusActive}, expiresAt: &past, wantErr: errorsx.ErrTokenInvalid},
+		{name: "user deleted", wantErr: errorsx.ErrTokenInvalid},
+		{name: "user suspended", user: &model.User{Status: known.UserStatusSuspended}, wantErr: errorsx.ErrUserSuspended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := storetest.New()
+			if tt.user != nil {
+				tt.user.UserID = "user-000001"
+				s.Users[tt.user.UserID] = tt.user
+			}
+			b := New(s)
+			ctx := contextx.WithUserID(context.Background(), "user-000001")
+
+			resp, err := b.Create(ctx, &apiv1.CreateAccessTokenRequest{Name: "ci", Scopes: tt.scopes, ExpiresAt: tt.expiresAt})
+			require.NoError(t, err)
+			plain := resp.Token
+			if tt.plain != "" {
+				plain = tt.plain
+			}
+
+			principal, err := b.Verify(context.Background(), plain)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantScopes, principal.Scopes)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package impersonation
+
+import (
+	"context"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/conversion"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/revoker"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// ImpersonationBiz 定义处理管理员模拟登录请求所需的方法
+type ImpersonationBiz interface {
+	Create(ctx context.Context, rq *apiv1.CreateImpersonationRequest) (*apiv1.CreateImpersonationResponse, error)
+	Delete(ctx context.Context, rq *apiv1.DeleteImpersonationRequest) (*apiv1.DeleteImpersonationResponse, error)
+	List(ctx context.Context, rq *apiv1.ListImpersonationRequest) (*apiv1.ListImpersonationResponse, error)
+
+	ImpersonationExpansion
+}
+
+// ImpersonationExpansion 定义额外的模拟登录操作方法
+type ImpersonationExpansion interface{}
+
+// impersonationBiz 是 ImpersonationBiz 接口的实现
+type impersonationBiz struct {
+	store   store.IStore
+	revoker *revoker.Revoker
+}
+
+// 确保 impersonationBiz 实现了 ImpersonationBiz 接口
+var _ ImpersonationBiz = (*impersonationBiz)(nil)
+
+// New 创建 impersonationBiz 的实例
+func New(store store.IStore, revoker *revoker.Revoker) *impersonationBiz {
+	return &impersonationBiz{store: store, revoker: revoker}
+}
+
+// Create 实现 ImpersonationBiz 接口中的 Create 方法. 管理员以目标用户的身份签发一个有时限的 token，
+// token 的 act 声明中记录管理员的用户 ID. 不能模拟自己或其他管理员.
+// 模拟登录 token 的有效期不超过普通 token 的有效期，以保证 token 吊销记录在 token 过期前有效.
+func (b *impersonationBiz) Create(ctx context.Context, rq *apiv1.CreateImpersonationRequest) (*apiv1.CreateImpersonationResponse, error) {
+	ctx, span := tracing.Start(ctx, "ImpersonationBiz.Create")
+	defer span.End()
+
+	actorID := contextx.UserID(ctx)
+	if rq.UserID == actorID {
+		return nil, errorsx.ErrImpersonationNotAllowed.WithMessage("You cannot impersonate yourself.")
+	}
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", rq.UserID))
+	if err != nil {
+		return nil, err
+	}
+	if userM.Role == known.RoleAdmin {
+		return nil, errorsx.ErrImpersonationNotAllowed.WithMessage("Administrators cannot be impersonated.")
+	}
+
+	expiration := token.Expiration()
+	if rq.ExpiresIn > 0 {
+		if time.Duration(rq.ExpiresIn)*time.Second > expiration {
+			return nil, errorsx.ErrInvalidArgument.WithMessage("ExpiresIn cannot exceed %d seconds", int64(expiration/time.Second))
+		}
+		expiration = time.Duration(rq.ExpiresIn) * time.Second
+	}
+
+	impersonationM := &model.Impersonation{
+		ActorID:   actorID,
+		UserID:    userM.UserID,
+		Reason:    rq.Reason,
+		ClientIP:  contextx.ClientIP(ctx),
+		ExpiresAt: time.Now().Add(expiration),
+	}
+	if err := b.store.Impersonation().Create(ctx, impersonationM); err != nil {
+		return nil, err
+	}
+
+	tokenStr, expireAt, err := token.SignImpersonation(token.Claims{
+		Identity: userM.UserID,
+		Username: userM.Username,
+		Role:     userM.Role,
+		Actor:    actorID,
+		ID:       impersonationM.ImpersonationID,
+	}, expiration)
+	if err != nil {
+		return nil, errorsx.ErrSignToken
+	}
+
+	return &apiv1.CreateImpersonationResponse{
+		ImpersonationID: impersonationM.ImpersonationID,
+		Token:           tokenStr,
+		ExpireAt:        expireAt,
+	}, nil
+}
+
+// Delete 实现 ImpersonationBiz 接口中的 Delete 方法，提前结束模拟登录，吊销模拟登录 token
+func (b *impersonationBiz) Delete(ctx context.Context, rq *apiv1.DeleteImpersonationRequest) (*apiv1.DeleteImpersonationResponse, error) {
+	ctx, span := tracing.Start(ctx, "ImpersonationBiz.Delete")
+	defer span.End()
+
+	impersonationM, err := b.store.Impersonation().Get(ctx, where.F("impersonationID", rq.ImpersonationID))
+	if err != nil {
+		return nil, err
+	}
+
+	if impersonationM.RevokedAt != nil || impersonationM.ExpiresAt.Before(time.Now()) {
+		return &apiv1.DeleteImpersonationResponse{}, nil
+	}
+
+	if err := b.revoker.RevokeToken(ctx, impersonationM.UserID, impersonationM.ImpersonationID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	impersonationM.RevokedAt = &now
+	if err := b.store.Impersonation().Update(ctx, impersonationM); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.DeleteImpersonationResponse{}, nil
+}
+
+// List 实现 ImpersonationBiz 接口中的 List 方法，返回模拟登录审计记录列表
+func (b *impersonationBiz) List(ctx context.Context, rq *apiv1.ListImpersonationRequest) (*apiv1.ListImpersonationResponse, error) {
+	ctx, span := tracing.Start(ctx, "ImpersonationBiz.List")
+	defer span.End()
+
+	whr := where.P(int(rq.Offset), int(rq.Limit))
+	if rq.ActorID != "" {
+		whr = whr.F("actorID", rq.ActorID)
+	}
+	if rq.UserID != "" {
+		whr = whr.F("userID", rq.UserID)
+	}
+
+	count, impersonationList, err := b.store.Impersonation().List(ctx, whr)
+	if err != nil {
+		return nil, err
+	}
+
+	impersonations := make([]*apiv1.Impersonation, 0, len(impersonationList))
+	for _, impersonationM := range impersonationList {
+		impersonations = append(impersonations, conversion.ImpersonationModelToImpersonationV1(impersonationM))
+	}
+
+	return &apiv1.ListImpersonationResponse{TotalCount: count, Impersonations: impersonations}, nil
+}
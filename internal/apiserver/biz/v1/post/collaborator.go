@@ -0,0 +1,150 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/conversion"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// AddCollaborator 邀请用户成为博文的协作者. 只有作者可以邀请，重复邀请会更新协作者角色.
+func (b *postBiz) AddCollaborator(ctx context.Context, rq *apiv1.AddCollaboratorRequest) (*apiv1.AddCollaboratorResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostBiz.AddCollaborator")
+	defer span.End()
+
+	postM, err := b.authorize(ctx, rq.PostID, known.PostRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	if rq.UserID == postM.UserID {
+		return nil, errorsx.ErrInvalidArgument.WithMessage("The author cannot be added as a collaborator")
+	}
+
+	// 确保被邀请的用户存在
+	if _, err := b.store.User().Get(ctx, where.F("userID", rq.UserID)); err != nil {
+		return nil, err
+	}
+
+	collaboratorM := &model.PostCollaborator{PostID: postM.PostID, UserID: rq.UserID, Role: rq.Role}
+	if err := b.store.PostCollaborator().Upsert(ctx, collaboratorM); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.AddCollaboratorResponse{}, nil
+}
+
+// RemoveCollaborator 移除博文的协作者. 作者可以移除任意协作者，协作者也可以主动退出协作.
+func (b *postBiz) RemoveCollaborator(ctx context.Context, rq *apiv1.RemoveCollaboratorRequest) (*apiv1.RemoveCollaboratorResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostBiz.RemoveCollaborator")
+	defer span.End()
+
+	allowed := []string{known.PostRoleOwner}
+	if rq.UserID == contextx.UserID(ctx) {
+		allowed = append(allowed, known.PostRoleEditor, known.PostRoleViewer)
+	}
+
+	if _, err := b.authorize(ctx, rq.PostID, allowed...); err != nil {
+		return nil, err
+	}
+
+	if err := b.store.PostCollaborator().Delete(ctx, where.F("postID", rq.PostID, "userID", rq.UserID)); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.RemoveCollaboratorResponse{}, nil
+}
+
+// ListCollaborator 列出博文的所有协作者. 作者和协作者均可查看.
+func (b *postBiz) ListCollaborator(ctx context.Context, rq *apiv1.ListCollaboratorRequest) (*apiv1.ListCollaboratorResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostBiz.ListCollaborator")
+	defer span.End()
+
+	if _, err := b.authorize(ctx, rq.PostID, known.PostRoleOwner, known.PostRoleEditor, known.PostRoleViewer); err != nil {
+		return nil, err
+	}
+
+	count, collaboratorList, err := b.store.PostCollaborator().List(ctx, where.F("postID", rq.PostID))
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators := make([]*apiv1.Collaborator, 0, len(collaboratorList))
+	for _, collaborator := range collaboratorList {
+		collaborators = append(collaborators, conversion.CollaboratorModelToCollaboratorV1(collaborator))
+	}
+
+	return &apiv1.ListCollaboratorResponse{TotalCount: count, Collaborators: collaborators}, nil
+}
+
+// ListShared 列出其他用户共享给当前用户的博文
+func (b *postBiz) ListShared(ctx context.Context, rq *apiv1.ListSharedPostRequest) (*apiv1.ListSharedPostResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostBiz.ListShared")
+	defer span.End()
+
+	// 在同一个查询中过滤被管理员隐藏的博文并分页，使分页和总数都不包含不再共享给协作者的博文
+	whr := where.F("hidden", false).
+		Q("postID IN (SELECT postID FROM "+model.TableNamePostCollaborator+" WHERE userID = ?)", contextx.UserID(ctx)).
+		P(int(rq.Offset), int(rq.Limit))
+	count, postList, err := b.store.Post().List(ctx, whr)
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]*apiv1.Post, 0, len(postList))
+	for _, post := range postList {
+		posts = append(posts, conversion.PostodelToPostV1(post))
+	}
+
+	if err := b.fillViewCount(ctx, posts...); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.ListSharedPostResponse{TotalCount: count, Posts: posts}, nil
+}
+
+// authorize 查询博文并校验当前用户在该博文上的角色是否属于 allowed，详见 Authorize
+func (b *postBiz) authorize(ctx context.Context, postID string, allowed ...string) (*model.Post, error) {
+	return Authorize(ctx, b.store, postID, allowed...)
+}
+
+// Authorize 查询博文并校验当前用户在该博文上的角色是否属于 allowed.
+// 与博文无关的用户返回 ErrPostNotFound，避免泄露博文是否存在；角色不满足时返回 ErrPostPermissionDenied.
+func Authorize(ctx context.Context, store store.IStore, postID string, allowed ...string) (*model.Post, error) {
+	postM, err := store.Post().Get(ctx, where.F("postID", postID))
+	if err != nil {
+		return nil, err
+	}
+
+	role := known.PostRoleOwner
+	if userID := contextx.UserID(ctx); postM.UserID != userID {
+		collaboratorM, err := store.PostCollaborator().Get(ctx, where.F("postID", postID, "userID", userID))
+		if err != nil {
+			if errors.Is(err, errorsx.ErrCollaboratorNotFound) {
+				return nil, errorsx.ErrPostNotFound
+			}
+			return nil, err
+		}
+		role = collaboratorM.Role
+
+		// 被管理员隐藏的博文只对作者可见
+		if postM.Hidden {
+			return nil, errorsx.ErrPostNotFound
+		}
+	}
+
+	if !slices.Contains(allowed, role) {
+		return nil, errorsx.ErrPostPermissionDenied
+	}
+
+	return postM, nil
+}
@@ -2,11 +2,18 @@ package post
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/contentfilter"
 	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/conversion"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/viewcounter"
 	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
 	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/metrics"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
 	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
 	"github.com/jinzhu/copier"
 	"github.com/onexstack/onexstack/pkg/store/where"
@@ -24,23 +31,46 @@ type PostBiz interface {
 }
 
 // PostExpansion 定义额外的帖子操作方法
-type PostExpansion interface{}
+type PostExpansion interface {
+	AddCollaborator(ctx context.Context, rq *apiv1.AddCollaboratorRequest) (*apiv1.AddCollaboratorResponse, error)
+	RemoveCollaborator(ctx context.Context, rq *apiv1.RemoveCollaboratorRequest) (*apiv1.RemoveCollaboratorResponse, error)
+	ListCollaborator(ctx context.Context, rq *apiv1.ListCollaboratorRequest) (*apiv1.ListCollaboratorResponse, error)
+	ListShared(ctx context.Context, rq *apiv1.ListSharedPostRequest) (*apiv1.ListSharedPostResponse, error)
+}
 
 // postBiz 是 PostBiz 接口的实现
 type postBiz struct {
-	store store.IStore
+	store  store.IStore
+	views  *viewcounter.Counter
+	filter *contentfilter.Filter
+	// requireVerifiedEmail 为 true 时，只有邮箱已验证的用户可以发布博文
+	requireVerifiedEmail bool
 }
 
 // 确保 postBiz 实现了 PostBiz 接口
 var _ PostBiz = (*postBiz)(nil)
 
 // New 创建 postBiz 的实例
-func New(store store.IStore) *postBiz {
-	return &postBiz{store: store}
+func New(store store.IStore, views *viewcounter.Counter, filter *contentfilter.Filter, requireVerifiedEmail bool) *postBiz {
+	return &postBiz{store: store, views: views, filter: filter, requireVerifiedEmail: requireVerifiedEmail}
 }
 
 // Create 实现 PostBiz 接口中的 Create 方法
 func (b *postBiz) Create(ctx context.Context, rq *apiv1.CreatePostRequest) (*apiv1.CreatePostResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostBiz.Create")
+	defer span.End()
+
+	// 服务调用方（mTLS 客户端证书认证）没有对应的用户，不需要校验邮箱
+	if b.requireVerifiedEmail && contextx.Role(ctx) != known.RoleService {
+		userM, err := b.store.User().Get(ctx, where.F("userID", contextx.UserID(ctx)))
+		if err != nil {
+			return nil, err
+		}
+		if userM.EmailVerifiedAt == nil {
+			return nil, errorsx.ErrEmailNotVerified
+		}
+	}
+
 	var postM model.Post
 	_ = copier.Copy(&postM, rq)
 	postM.UserID = contextx.UserID(ctx)
@@ -49,13 +79,19 @@ func (b *postBiz) Create(ctx context.Context, rq *apiv1.CreatePostRequest) (*api
 		return nil, err
 	}
 
+	metrics.ObservePostCreated()
+	b.screen(ctx, &postM)
+
 	return &apiv1.CreatePostResponse{PostID: postM.PostID}, nil
 }
 
 // Update 实现 PostBiz 接口中的 Update 方法
 func (b *postBiz) Update(ctx context.Context, rq *apiv1.UpdatePostRequest) (*apiv1.UpdatePostResponse, error) {
-	whr := where.F("userID", contextx.UserID(ctx), "postID", rq.PostID)
-	postM, err := b.store.Post().Get(ctx, whr)
+	ctx, span := tracing.Start(ctx, "PostBiz.Update")
+	defer span.End()
+
+	// 作者和 editor 角色的协作者可以更新博文
+	postM, err := b.authorize(ctx, rq.PostID, known.PostRoleOwner, known.PostRoleEditor)
 	if err != nil {
 		return nil, err
 	}
@@ -72,13 +108,39 @@ func (b *postBiz) Update(ctx context.Context, rq *apiv1.UpdatePostRequest) (*api
 		return nil, err
 	}
 
+	b.screen(ctx, postM)
+
 	return &apiv1.UpdatePostResponse{}, nil
 }
 
 // Delete 实现 PostBiz 接口中的 Delete 方法
 func (b *postBiz) Delete(ctx context.Context, rq *apiv1.DeletePostRequest) (*apiv1.DeletePostResponse, error) {
-	whr := where.F("userID", contextx.UserID(ctx), "postID", rq.PostIDs)
-	if err := b.store.Post().Delete(ctx, whr); err != nil {
+	ctx, span := tracing.Start(ctx, "PostBiz.Delete")
+	defer span.End()
+
+	// 只有作者可以删除博文，协作者和计数记录随博文一起删除
+	err := b.store.TX(ctx, func(ctx context.Context) error {
+		_, postList, err := b.store.Post().List(ctx, where.F("userID", contextx.UserID(ctx), "postID", rq.PostIDs))
+		if err != nil {
+			return err
+		}
+
+		postIDs := make([]string, 0, len(postList))
+		for _, post := range postList {
+			postIDs = append(postIDs, post.PostID)
+		}
+
+		if err := b.store.Post().Delete(ctx, where.F("postID", postIDs)); err != nil {
+			return err
+		}
+
+		if err := b.store.PostCollaborator().Delete(ctx, where.F("postID", postIDs)); err != nil {
+			return err
+		}
+
+		return b.store.PostCounter().Delete(ctx, where.F("postID", postIDs))
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -87,17 +149,30 @@ func (b *postBiz) Delete(ctx context.Context, rq *apiv1.DeletePostRequest) (*api
 
 // Get 实现 PostBiz 接口中的 Get 方法
 func (b *postBiz) Get(ctx context.Context, rq *apiv1.GetPostRequest) (*apiv1.GetPostResponse, error) {
-	whr := where.F("userID", contextx.UserID(ctx), "postID", rq.PostID)
-	postM, err := b.store.Post().Get(ctx, whr)
+	ctx, span := tracing.Start(ctx, "PostBiz.Get")
+	defer span.End()
+
+	// 作者和所有协作者都可以查看博文
+	postM, err := b.authorize(ctx, rq.PostID, known.PostRoleOwner, known.PostRoleEditor, known.PostRoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
-	return &apiv1.GetPostResponse{Post: conversion.PostodelToPostV1(postM)}, nil
+	b.views.Record(postM.PostID, viewer(ctx))
+
+	post := conversion.PostodelToPostV1(postM)
+	if err := b.fillViewCount(ctx, post); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.GetPostResponse{Post: post}, nil
 }
 
 // List 实现 PostBiz 接口中的 List 方法
 func (b *postBiz) List(ctx context.Context, rq *apiv1.ListPostRequest) (*apiv1.ListPostResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostBiz.List")
+	defer span.End()
+
 	whr := where.F("userID", contextx.UserID(ctx)).P(int(rq.Offset), int(rq.Limit))
 	if rq.Title != nil {
 		whr = whr.Q("title like ?", "%"+*rq.Title+"%")
@@ -114,5 +189,59 @@ func (b *postBiz) List(ctx context.Context, rq *apiv1.ListPostRequest) (*apiv1.L
 		posts = append(posts, converted)
 	}
 
+	if err := b.fillViewCount(ctx, posts...); err != nil {
+		return nil, err
+	}
+
 	return &apiv1.ListPostResponse{TotalCount: count, Posts: posts}, nil
 }
+
+// fillViewCount 为博文填充浏览次数，浏览次数 = 数据库中已持久化的次数 + 内存中尚未写入的次数
+func (b *postBiz) fillViewCount(ctx context.Context, posts ...*apiv1.Post) error {
+	postIDs := make([]string, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.PostID)
+	}
+
+	views, err := b.store.PostCounter().Views(ctx, postIDs...)
+	if err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		post.ViewCount = views[post.PostID] + b.views.Pending(post.PostID)
+	}
+
+	return nil
+}
+
+// viewer 返回用于浏览去重的访问者标识，优先使用用户 ID，其次使用客户端 IP
+func viewer(ctx context.Context) string {
+	if userID := contextx.UserID(ctx); userID != "" {
+		return "user:" + userID
+	}
+	if clientIP := contextx.ClientIP(ctx); clientIP != "" {
+		return "ip:" + clientIP
+	}
+	return ""
+}
+
+// screen 使用内容过滤器检查博文，命中敏感内容时自动提交举报，进入管理员的审核队列.
+// 自动举报失败不影响博文的创建和更新.
+func (b *postBiz) screen(ctx context.Context, postM *model.Post) {
+	rule, matched := b.filter.Match(postM.Title, postM.Content)
+	if !matched {
+		return
+	}
+
+	reportM := model.Report{
+		TargetType: known.ReportTargetPost,
+		TargetID:   postM.PostID,
+		Reason:     "Matched content filter: " + rule,
+		Source:     known.ReportSourceFilter,
+		Status:     known.ReportStatusPending,
+	}
+	if err := b.store.Report().Create(ctx, &reportM); err != nil {
+		slog.ErrorContext(ctx, "Failed to create report for filtered post", "err", err, "postID", postM.PostID)
+	}
+}
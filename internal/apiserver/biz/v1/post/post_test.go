@@ -0,0 +1,37 @@
+package post
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/contentfilter"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRequireVerifiedEmail(t *testing.T) {
+	filter, err := contentfilter.New(nil, nil)
+	require.NoError(t, err)
+	s := storetest.New(&model.User{UserID: "user-000001"})
+	b := New(s, nil, filter, true)
+	rq := &apiv1.CreatePostRequest{Title: "title", Content: "content"}
+
+	// 邮箱未验证的用户不能发布博文
+	ctx := contextx.WithPrincipal(context.Background(), &contextx.Principal{UserID: "user-000001", Roles: []string{known.RoleUser}})
+	_, err = b.Create(ctx, rq)
+	assert.ErrorIs(t, err, errorsx.ErrEmailNotVerified)
+
+	// 服务调用方没有对应的用户，不校验邮箱
+	ctx = contextx.WithPrincipal(context.Background(), &contextx.Principal{UserID: "service:billing", Roles: []string{known.RoleService}})
+	resp, err := b.Create(ctx, rq)
+	require.NoError(t, err)
+	assert.Equal(t, "post-000001", resp.PostID)
+	require.Len(t, s.Posts, 1)
+	assert.Equal(t, "service:billing", s.Posts[0].UserID)
+}
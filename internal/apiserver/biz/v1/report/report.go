@@ -0,0 +1,250 @@
+package report
+
+import (
+	"context"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/biz/v1/post"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/conversion"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/revoker"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm/clause"
+)
+
+// ReportBiz 定义处理举报和内容审核请求所需的方法
+type ReportBiz interface {
+	Create(ctx context.Context, rq *apiv1.CreateReportRequest) (*apiv1.CreateReportResponse, error)
+	List(ctx context.Context, rq *apiv1.ListReportRequest) (*apiv1.ListReportResponse, error)
+
+	ReportExpansion
+}
+
+// ReportExpansion 定义额外的举报操作方法
+type ReportExpansion interface {
+	Moderate(ctx context.Context, rq *apiv1.ModerateReportRequest) (*apiv1.ModerateReportResponse, error)
+	ListAction(ctx context.Context, rq *apiv1.ListModerationActionRequest) (*apiv1.ListModerationActionResponse, error)
+}
+
+// reportBiz 是 ReportBiz 接口的实现
+type reportBiz struct {
+	store   store.IStore
+	revoker *revoker.Revoker
+}
+
+// 确保 reportBiz 实现了 ReportBiz 接口
+var _ ReportBiz = (*reportBiz)(nil)
+
+// New 创建 reportBiz 的实例
+func New(store store.IStore, revoker *revoker.Revoker) *reportBiz {
+	return &reportBiz{store: store, revoker: revoker}
+}
+
+// Create 实现 ReportBiz 接口中的 Create 方法. 任何用户都可以举报博文或用户.
+func (b *reportBiz) Create(ctx context.Context, rq *apiv1.CreateReportRequest) (*apiv1.CreateReportResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReportBiz.Create")
+	defer span.End()
+
+	// 确保被举报的对象存在. 博文使用与查询博文详情相同的可见性校验，
+	// 对当前用户不可见的博文与不存在的博文一样返回 ErrPostNotFound，避免通过举报探测博文是否存在.
+	// 服务调用方不是任何博文的作者或协作者，只校验博文是否存在
+	switch rq.TargetType {
+	case known.ReportTargetPost:
+		if contextx.Role(ctx) == known.RoleService {
+			if _, err := b.store.Post().Get(ctx, where.F("postID", rq.TargetID)); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if _, err := post.Authorize(ctx, b.store, rq.TargetID, known.PostRoleOwner, known.PostRoleEditor, known.PostRoleViewer); err != nil {
+			return nil, err
+		}
+	case known.ReportTargetUser:
+		if _, err := b.store.User().Get(ctx, where.F("userID", rq.TargetID)); err != nil {
+			return nil, err
+		}
+	}
+
+	reportM := model.Report{
+		ReporterID: contextx.UserID(ctx),
+		TargetType: rq.TargetType,
+		TargetID:   rq.TargetID,
+		Reason:     rq.Reason,
+		Source:     known.ReportSourceUser,
+		Status:     known.ReportStatusPending,
+	}
+	if err := b.store.Report().Create(ctx, &reportM); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.CreateReportResponse{ReportID: reportM.ReportID}, nil
+}
+
+// List 实现 ReportBiz 接口中的 List 方法，返回管理员的审核队列
+func (b *reportBiz) List(ctx context.Context, rq *apiv1.ListReportRequest) (*apiv1.ListReportResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReportBiz.List")
+	defer span.End()
+
+	whr := where.P(int(rq.Offset), int(rq.Limit))
+	if rq.Status != nil {
+		whr = whr.F("status", *rq.Status)
+	}
+	if rq.TargetType != nil {
+		whr = whr.F("targetType", *rq.TargetType)
+	}
+	if rq.Source != nil {
+		whr = whr.F("source", *rq.Source)
+	}
+
+	count, reportList, err := b.store.Report().List(ctx, whr)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*apiv1.Report, 0, len(reportList))
+	for _, report := range reportList {
+		reports = append(reports, conversion.ReportModelToReportV1(report))
+	}
+
+	return &apiv1.ListReportResponse{TotalCount: count, Reports: reports}, nil
+}
+
+// Moderate 处理一条待处理的举报：隐藏博文、封禁用户或驳回举报. 每次处理都会记录审核操作.
+func (b *reportBiz) Moderate(ctx context.Context, rq *apiv1.ModerateReportRequest) (*apiv1.ModerateReportResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReportBiz.Moderate")
+	defer span.End()
+
+	// suspendedUserID 记录被封禁的用户，事务提交后再吊销其 token，避免事务回滚后用户仍被吊销
+	var suspendedUserID string
+	err := b.store.TX(ctx, func(ctx context.Context) error {
+		// 锁定举报记录，避免并发处理同一条举报时重复执行审核操作
+		reportM, err := b.store.Report().Get(ctx, where.F("reportID", rq.ReportID).C(clause.Locking{Strength: clause.LockingStrengthUpdate}))
+		if err != nil {
+			return err
+		}
+
+		if reportM.Status != known.ReportStatusPending {
+			return errorsx.ErrReportAlreadyHandled
+		}
+
+		actionM := &model.ModerationAction{
+			ReportID:   reportM.ReportID,
+			ActorID:    contextx.UserID(ctx),
+			Action:     rq.Action,
+			TargetType: reportM.TargetType,
+			TargetID:   reportM.TargetID,
+			Note:       rq.Note,
+		}
+
+		switch rq.Action {
+		case known.ModerationActionHidePost:
+			if err := b.hidePost(ctx, reportM); err != nil {
+				return err
+			}
+			reportM.Status = known.ReportStatusResolved
+		case known.ModerationActionSuspendUser:
+			userID, err := b.suspendUser(ctx, reportM)
+			if err != nil {
+				return err
+			}
+			actionM.TargetType, actionM.TargetID = known.ReportTargetUser, userID
+			suspendedUserID = userID
+			reportM.Status = known.ReportStatusResolved
+		case known.ModerationActionDismiss:
+			reportM.Status = known.ReportStatusDismissed
+		}
+
+		if err := b.store.Report().Update(ctx, reportM); err != nil {
+			return err
+		}
+
+		return b.store.ModerationAction().Create(ctx, actionM)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 封禁后立即吊销该用户所有已签发的 token
+	if suspendedUserID != "" {
+		if err := b.revoker.RevokeUser(ctx, suspendedUserID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &apiv1.ModerateReportResponse{}, nil
+}
+
+// ListAction 列出审核操作记录
+func (b *reportBiz) ListAction(ctx context.Context, rq *apiv1.ListModerationActionRequest) (*apiv1.ListModerationActionResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReportBiz.ListAction")
+	defer span.End()
+
+	whr := where.P(int(rq.Offset), int(rq.Limit))
+	if rq.ReportID != nil {
+		whr = whr.F("reportID", *rq.ReportID)
+	}
+
+	count, actionList, err := b.store.ModerationAction().List(ctx, whr)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]*apiv1.ModerationAction, 0, len(actionList))
+	for _, action := range actionList {
+		actions = append(actions, conversion.ModerationActionModelToModerationActionV1(action))
+	}
+
+	return &apiv1.ListModerationActionResponse{TotalCount: count, Actions: actions}, nil
+}
+
+// hidePost 隐藏被举报的博文. 只适用于博文类型的举报.
+func (b *reportBiz) hidePost(ctx context.Context, reportM *model.Report) error {
+	if reportM.TargetType != known.ReportTargetPost {
+		return errorsx.ErrModerationActionInvalid
+	}
+
+	postM, err := b.store.Post().Get(ctx, where.F("postID", reportM.TargetID))
+	if err != nil {
+		return err
+	}
+
+	postM.Hidden = true
+	return b.store.Post().Update(ctx, postM)
+}
+
+// suspendUser 封禁被举报的用户. 如果被举报的是博文，则封禁博文的作者. 返回被封禁的用户 ID.
+func (b *reportBiz) suspendUser(ctx context.Context, reportM *model.Report) (string, error) {
+	userID := reportM.TargetID
+	if reportM.TargetType == known.ReportTargetPost {
+		postM, err := b.store.Post().Get(ctx, where.F("postID", reportM.TargetID))
+		if err != nil {
+			return "", err
+		}
+		userID = postM.UserID
+	}
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", userID))
+	if err != nil {
+		return "", err
+	}
+
+	userM.Status = known.UserStatusSuspended
+	if err := b.store.User().Update(ctx, userM); err != nil {
+		return "", err
+	}
+
+	// 封禁后吊销该用户的 refresh token 及登录会话，已签发的 token 在事务提交后由 Moderate 吊销
+	if err := b.store.RefreshToken().Revoke(ctx, where.F("userID", userID)); err != nil {
+		return "", err
+	}
+	if err := b.store.Session().Revoke(ctx, where.F("userID", userID)); err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,95 @@
+package report
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/revoker"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+)
+
+func TestCreatePostReport(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *contextx.Principal
+		postID    string
+		wantErr   error
+	}{
+		{name: "collaborator", principal: &contextx.Principal{UserID: "user-000002", Roles: []string{known.RoleUser}}, postID: "post-000001"},
+		// 对当前用户不可见的博文与不存在的博文一样返回 ErrPostNotFound
+		{name: "not visible", principal: &contextx.Principal{UserID: "user-000003", Roles: []string{known.RoleUser}}, postID: "post-000001", wantErr: errorsx.ErrPostNotFound},
+		// 服务调用方不是博文的作者或协作者，只校验博文是否存在
+		{name: "service", principal: &contextx.Principal{UserID: "service:moderation", Roles: []string{known.RoleService}}, postID: "post-000001"},
+		{name: "service post not found", principal: &contextx.Principal{UserID: "service:moderation", Roles: []string{known.RoleService}}, postID: "post-000002", wantErr: errorsx.ErrPostNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := storetest.New()
+			s.Posts = []*model.Post{{PostID: "post-000001", UserID: "user-000001"}}
+			s.PostCollaborators = []*model.PostCollaborator{{PostID: "post-000001", UserID: "user-000002", Role: known.PostRoleViewer}}
+			b := New(s, nil)
+			ctx := contextx.WithPrincipal(context.Background(), tt.principal)
+
+			_, err := b.Create(ctx, &apiv1.CreateReportRequest{TargetType: known.ReportTargetPost, TargetID: tt.postID, Reason: "spam"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, s.Reports)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, s.Reports, 1)
+			assert.Equal(t, tt.principal.UserID, s.Reports[0].ReporterID)
+		})
+	}
+}
+
+func TestModerateSuspendUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		actionErr error
+		wantErr   error
+	}{
+		{name: "committed"},
+		// 事务回滚时用户未被封禁，也不应吊销其 token
+		{name: "rolled back", actionErr: errorsx.ErrDBWrite, wantErr: errorsx.ErrDBWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := storetest.New(&model.User{UserID: "user-000002", Username: "bob", Status: known.UserStatusActive})
+			s.Reports = []*model.Report{{ReportID: "report-000001", TargetType: known.ReportTargetUser, TargetID: "user-000002", Status: known.ReportStatusPending}}
+			if tt.actionErr != nil {
+				s.Errs["ModerationAction.Create"] = tt.actionErr
+			}
+			b := New(s, revoker.New(s.RevokedToken(), s.Session()))
+
+			_, err := b.Moderate(context.Background(), &apiv1.ModerateReportRequest{ReportID: "report-000001", Action: known.ModerationActionSuspendUser})
+
+			// 处理举报时锁定举报记录
+			require.NotNil(t, s.Opts["Report.Get"])
+			assert.Contains(t, s.Opts["Report.Get"].Clauses, clause.Locking{Strength: clause.LockingStrengthUpdate})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, known.UserStatusActive, s.Users["user-000002"].Status)
+				assert.Equal(t, known.ReportStatusPending, s.Reports[0].Status)
+				assert.Empty(t, s.RevokedTokens)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, known.UserStatusSuspended, s.Users["user-000002"].Status)
+			assert.Equal(t, known.ReportStatusResolved, s.Reports[0].Status)
+			require.Len(t, s.RevokedTokens, 1)
+			assert.Equal(t, "user-000002", s.RevokedTokens[0].UserID)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/conversion"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/revoker"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// SessionBiz 定义处理登录会话请求所需的方法
+type SessionBiz interface {
+	Delete(ctx context.Context, rq *apiv1.DeleteSessionRequest) (*apiv1.DeleteSessionResponse, error)
+	List(ctx context.Context, rq *apiv1.ListSessionRequest) (*apiv1.ListSessionResponse, error)
+
+	SessionExpansion
+}
+
+// SessionExpansion 定义额外的登录会话操作方法
+type SessionExpansion interface{}
+
+// sessionBiz 是 SessionBiz 接口的实现
+type sessionBiz struct {
+	store   store.IStore
+	revoker *revoker.Revoker
+}
+
+// 确保 sessionBiz 实现了 SessionBiz 接口
+var _ SessionBiz = (*sessionBiz)(nil)
+
+// New 创建 sessionBiz 的实例
+func New(store store.IStore, revoker *revoker.Revoker) *sessionBiz {
+	return &sessionBiz{store: store, revoker: revoker}
+}
+
+// Delete 实现 SessionBiz 接口中的 Delete 方法. 吊销当前用户的一个登录会话（设备），
+// 该会话签发的 token 和 refresh token 随即失效.
+func (b *sessionBiz) Delete(ctx context.Context, rq *apiv1.DeleteSessionRequest) (*apiv1.DeleteSessionResponse, error) {
+	ctx, span := tracing.Start(ctx, "SessionBiz.Delete")
+	defer span.End()
+
+	sessionM, err := b.store.Session().Get(ctx, where.F("userID", contextx.UserID(ctx), "sessionID", rq.SessionID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.revoker.RevokeSession(ctx, sessionM.SessionID); err != nil {
+		return nil, err
+	}
+
+	// 会话 ID 同时也是该会话 refresh token 的令牌族 ID
+	if err := b.store.RefreshToken().Revoke(ctx, where.F("familyID", sessionM.SessionID)); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.DeleteSessionResponse{}, nil
+}
+
+// List 实现 SessionBiz 接口中的 List 方法，返回当前用户未吊销且未过期的登录会话列表
+func (b *sessionBiz) List(ctx context.Context, rq *apiv1.ListSessionRequest) (*apiv1.ListSessionResponse, error) {
+	ctx, span := tracing.Start(ctx, "SessionBiz.List")
+	defer span.End()
+
+	whr := where.P(int(rq.Offset), int(rq.Limit)).
+		F("userID", contextx.UserID(ctx)).
+		Q("revokedAt IS NULL AND expiresAt > ?", time.Now())
+	count, sessionList, err := b.store.Session().List(ctx, whr)
+	if err != nil {
+		return nil, err
+	}
+
+	current := contextx.SessionID(ctx)
+	sessions := make([]*apiv1.Session, 0, len(sessionList))
+	for _, sessionM := range sessionList {
+		session := conversion.SessionModelToSessionV1(sessionM)
+		session.Current = sessionM.SessionID == current
+		sessions = append(sessions, session)
+	}
+
+	return &apiv1.ListSessionResponse{TotalCount: count, Sessions: sessions}, nil
+}
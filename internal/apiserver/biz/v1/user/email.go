@@ -0,0 +1,201 @@
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/mailer"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/auth"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// mailData 是验证邮箱和重置密码邮件模板中可以使用的数据
+type mailData struct {
+	Username string
+	Link     string
+	ExpireAt time.Time
+}
+
+// VerifyEmail 使用验证邮件中的 token 验证用户邮箱. 用户修改邮箱后，发送到旧邮箱的链接随即失效.
+func (b *userBiz) VerifyEmail(ctx context.Context, rq *apiv1.VerifyEmailRequest) (*apiv1.VerifyEmailResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.VerifyEmail")
+	defer span.End()
+
+	userID, fingerprint, err := token.ParseChallenge(rq.Token, token.PurposeVerifyEmail)
+	if err != nil {
+		return nil, errorsx.ErrEmailTokenInvalid
+	}
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", userID))
+	if err != nil && !errors.Is(err, errorsx.ErrUserNotFound) {
+		return nil, err
+	}
+	if err != nil || fingerprint != emailFingerprint(userM) {
+		return nil, errorsx.ErrEmailTokenInvalid
+	}
+
+	// 重复验证直接返回成功
+	if userM.EmailVerifiedAt != nil {
+		return &apiv1.VerifyEmailResponse{}, nil
+	}
+
+	now := time.Now()
+	userM.EmailVerifiedAt = &now
+	if err := b.store.User().Update(ctx, userM); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.VerifyEmailResponse{}, nil
+}
+
+// SendVerificationEmail 重新发送验证邮件，邮箱已验证时不再发送
+func (b *userBiz) SendVerificationEmail(ctx context.Context, rq *apiv1.SendVerificationEmailRequest) (*apiv1.SendVerificationEmailResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.SendVerificationEmail")
+	defer span.End()
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", rq.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	if userM.EmailVerifiedAt == nil {
+		if err := b.sendVerificationEmail(ctx, userM); err != nil {
+			return nil, err
+		}
+	}
+
+	return &apiv1.SendVerificationEmailResponse{}, nil
+}
+
+// ForgotPassword 向邮箱发送重置密码的链接. 无论邮箱是否已注册都返回成功，避免泄露注册信息.
+func (b *userBiz) ForgotPassword(ctx context.Context, rq *apiv1.ForgotPasswordRequest) (*apiv1.ForgotPasswordResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.ForgotPassword")
+	defer span.End()
+
+	_, users, err := b.store.User().List(ctx, where.F("email", strings.TrimSpace(rq.Email)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userM := range users {
+		expiration := b.mailOpts.PasswordResetExpiration
+		resetToken, expireAt, err := token.SignChallenge(userM.UserID, token.PurposeResetPassword, expiration, passwordFingerprint(userM))
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to sign password reset token", "err", err)
+			return nil, errorsx.ErrSignToken
+		}
+
+		b.notifier.Notify(ctx, userM.Email, mailer.TemplateResetPassword, mailData{
+			Username: userM.Username,
+			Link:     b.link("/reset-password", resetToken),
+			ExpireAt: expireAt,
+		})
+	}
+
+	return &apiv1.ForgotPasswordResponse{}, nil
+}
+
+// ResetPassword 使用重置密码邮件中的 token 设置新密码. 密码修改后 token 随即失效，因此每个 token 只能使用一次.
+func (b *userBiz) ResetPassword(ctx context.Context, rq *apiv1.ResetPasswordRequest) (*apiv1.ResetPasswordResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.ResetPassword")
+	defer span.End()
+
+	userID, fingerprint, err := token.ParseChallenge(rq.Token, token.PurposeResetPassword)
+	if err != nil {
+		return nil, errorsx.ErrEmailTokenInvalid
+	}
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", userID))
+	if err != nil && !errors.Is(err, errorsx.ErrUserNotFound) {
+		return nil, err
+	}
+	if err != nil || fingerprint != passwordFingerprint(userM) {
+		return nil, errorsx.ErrEmailTokenInvalid
+	}
+
+	if err := auth.ValidatePassword(rq.NewPassword, userM.Username); err != nil {
+		return nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error())
+	}
+
+	hash, err := auth.Encrypt(rq.NewPassword)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to encrypt password", "err", err)
+		return nil, errorsx.ErrInternal
+	}
+	userM.Password = hash
+	userM.Passwordless = false
+	// 能够收到重置密码邮件，说明用户拥有该邮箱
+	if userM.EmailVerifiedAt == nil {
+		now := time.Now()
+		userM.EmailVerifiedAt = &now
+	}
+	// 重置密码后，吊销该用户所有已签发的 token 和个人访问令牌，需要重新登录.
+	// 重置密码和吊销凭证在同一个事务中完成，避免密码已修改但旧凭证仍然可用
+	err = b.store.TX(ctx, func(ctx context.Context) error {
+		if err := b.store.User().Update(ctx, userM); err != nil {
+			return err
+		}
+		return b.revokeCredentials(ctx, userM.UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.revoker.RevokeUser(ctx, userM.UserID); err != nil {
+		return nil, err
+	}
+
+	// 能够重置密码说明用户拥有该账号，与管理员解锁一样清除登录失败记录，用户可以立即使用新密码登录
+	b.lockout.Reset(userM.UserID)
+
+	return &apiv1.ResetPasswordResponse{}, nil
+}
+
+// sendVerificationEmail 向用户当前的邮箱发送验证邮件
+func (b *userBiz) sendVerificationEmail(ctx context.Context, userM *model.User) error {
+	verifyToken, expireAt, err := token.SignChallenge(userM.UserID, token.PurposeVerifyEmail, b.mailOpts.VerifyEmailExpiration, emailFingerprint(userM))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to sign email verification token", "err", err)
+		return errorsx.ErrSignToken
+	}
+
+	b.notifier.Notify(ctx, userM.Email, mailer.TemplateVerifyEmail, mailData{
+		Username: userM.Username,
+		Link:     b.link("/verify-email", verifyToken),
+		ExpireAt: expireAt,
+	})
+
+	return nil
+}
+
+// link 返回邮件中携带 token 的链接
+func (b *userBiz) link(path string, tokenString string) string {
+	return strings.TrimSuffix(b.mailOpts.BaseURL, "/") + path + "?token=" + url.QueryEscape(tokenString)
+}
+
+// emailFingerprint 返回用户邮箱的摘要，用于验证邮箱的 token，用户修改邮箱后旧 token 失效
+func emailFingerprint(userM *model.User) string {
+	return fingerprint(userM.Email)
+}
+
+// passwordFingerprint 返回用户密码和邮箱的摘要，用于重置密码的 token，密码或邮箱修改后旧 token 失效
+func passwordFingerprint(userM *model.User) string {
+	return fingerprint(userM.Password + "\x00" + userM.Email)
+}
+
+// fingerprint 返回 s 的 SHA-256 摘要的前 16 个字节
+func fingerprint(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
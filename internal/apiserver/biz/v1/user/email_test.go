@@ -0,0 +1,42 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/auth"
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetPasswordUnlocks(t *testing.T) {
+	hash, err := auth.Encrypt("fastgo1234")
+	require.NoError(t, err)
+	s := storetest.New(&model.User{UserID: "user-000001", Username: "alice", Password: hash, Role: known.RoleUser})
+	b := newTestBiz(s)
+	ctx := context.Background()
+
+	// 登录失败次数达到上限后锁定账号
+	for range genericoptions.NewLockoutOptions().MaxAttempts {
+		_, err = b.login(ctx, &apiv1.LoginRequest{Username: "alice", Password: "wrong-password"})
+		require.Error(t, err)
+	}
+	_, err = b.login(ctx, &apiv1.LoginRequest{Username: "alice", Password: "fastgo1234"})
+	require.ErrorIs(t, err, errorsx.ErrAccountLocked)
+
+	// 重置密码后清除登录失败记录，可以立即使用新密码登录
+	resetToken, _, err := token.SignChallenge("user-000001", token.PurposeResetPassword, 0, passwordFingerprint(s.Users["user-000001"]))
+	require.NoError(t, err)
+	_, err = b.ResetPassword(ctx, &apiv1.ResetPasswordRequest{Token: resetToken, NewPassword: "fastgo-new-5678"})
+	require.NoError(t, err)
+
+	_, err = b.login(ctx, &apiv1.LoginRequest{Username: "alice", Password: "fastgo-new-5678"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,76 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/metrics"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/auth"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// dummyPasswordHash 用于用户不存在时仍然执行一次密码比较，使登录耗时与用户存在时一致，避免通过耗时推断用户名是否已注册
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := auth.Encrypt("fastgo-dummy-password")
+	return hash
+})
+
+// unknownAccount 返回未注册用户名的失败计数 key. 未注册的用户名同样会被锁定，避免通过锁定行为推断用户名是否已注册
+func unknownAccount(username string) string {
+	return "username:" + username
+}
+
+// checkLockout 检查账号和当前客户端 IP 是否因登录失败次数过多被临时锁定
+func (b *userBiz) checkLockout(ctx context.Context, account string) error {
+	if after := b.lockout.Check(account, contextx.ClientIP(ctx)); after > 0 {
+		return errorsx.WithRetryAfter(errorsx.ErrAccountLocked, after)
+	}
+
+	return nil
+}
+
+// loginFailed 记录一次登录失败. 本次失败导致账号或客户端 IP 被锁定时返回 ErrAccountLocked，否则返回 err
+func (b *userBiz) loginFailed(ctx context.Context, account string, err error) error {
+	if after := b.lockout.Fail(account, contextx.ClientIP(ctx)); after > 0 {
+		return errorsx.WithRetryAfter(errorsx.ErrAccountLocked, after)
+	}
+
+	return err
+}
+
+// Unlock 清除用户的登录失败记录，解除账号锁定
+func (b *userBiz) Unlock(ctx context.Context, rq *apiv1.UnlockUserRequest) (*apiv1.UnlockUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.Unlock")
+	defer span.End()
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", rq.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	b.lockout.Reset(userM.UserID)
+
+	return &apiv1.UnlockUserResponse{}, nil
+}
+
+// observeLogin 记录一次登录的结果. 通过了第一步认证但需要两步验证的登录单独统计
+func observeLogin(method string, resp *apiv1.LoginResponse, err error) {
+	switch {
+	case err != nil:
+		metrics.ObserveLogin(method, metrics.LoginFailure)
+	case resp.TwoFactorRequired:
+		metrics.ObserveLogin(method, metrics.LoginTwoFactorRequired)
+	default:
+		metrics.ObserveLogin(method, metrics.LoginSuccess)
+	}
+}
+
+// isSecondFactorFailure 判断两步验证失败是否由验证码错误导致，只有验证码错误才计入登录失败次数
+func isSecondFactorFailure(err error) bool {
+	return errors.Is(err, errorsx.ErrTOTPCodeInvalid)
+}
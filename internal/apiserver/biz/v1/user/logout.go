@@ -0,0 +1,89 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// Logout 注销当前登录：吊销当前请求使用的 token 及其所在的登录会话. 如果传入了 refresh token，一并吊销其所在的令牌族.
+func (b *userBiz) Logout(ctx context.Context, rq *apiv1.LogoutRequest) (*apiv1.LogoutResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.Logout")
+	defer span.End()
+
+	userID := contextx.UserID(ctx)
+	if err := b.revoker.RevokeToken(ctx, userID, contextx.TokenID(ctx)); err != nil {
+		return nil, err
+	}
+
+	if sessionID := contextx.SessionID(ctx); sessionID != "" {
+		if err := b.revoker.RevokeSession(ctx, sessionID); err != nil {
+			return nil, err
+		}
+		if err := b.store.RefreshToken().Revoke(ctx, where.F("familyID", sessionID)); err != nil {
+			return nil, err
+		}
+	}
+
+	if rq.RefreshToken != "" {
+		tokenM, err := b.store.RefreshToken().Get(ctx, where.F("tokenHash", token.HashOpaque(rq.RefreshToken), "userID", userID))
+		if err != nil {
+			// refresh token 无效时无需吊销，注销仍然成功
+			if errors.Is(err, errorsx.ErrRefreshTokenInvalid) {
+				return &apiv1.LogoutResponse{}, nil
+			}
+			return nil, err
+		}
+
+		if err := b.store.RefreshToken().Revoke(ctx, where.F("familyID", tokenM.FamilyID)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &apiv1.LogoutResponse{}, nil
+}
+
+// LogoutAll 注销所有登录：吊销当前用户所有已签发的 token、refresh token、登录会话及个人访问令牌
+func (b *userBiz) LogoutAll(ctx context.Context, rq *apiv1.LogoutAllRequest) (*apiv1.LogoutAllResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.LogoutAll")
+	defer span.End()
+
+	if err := b.revokeAll(ctx, contextx.UserID(ctx)); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.LogoutAllResponse{}, nil
+}
+
+// revokeAll 吊销用户所有已签发的 token、refresh token、登录会话及个人访问令牌.
+// 数据库中的吊销在同一个事务中完成，事务提交后再吊销已签发的 token
+func (b *userBiz) revokeAll(ctx context.Context, userID string) error {
+	if err := b.store.TX(ctx, func(ctx context.Context) error {
+		return b.revokeCredentials(ctx, userID)
+	}); err != nil {
+		return err
+	}
+
+	return b.revoker.RevokeUser(ctx, userID)
+}
+
+// revokeCredentials 吊销用户所有的 refresh token、登录会话及个人访问令牌，需要在事务中调用.
+// 个人访问令牌不检查 token 吊销记录，只能通过删除吊销. 已签发的 token 需要在事务提交后由调用方通过 RevokeUser 吊销，
+// 避免事务回滚后用户仍被缓存为已吊销
+func (b *userBiz) revokeCredentials(ctx context.Context, userID string) error {
+	if err := b.store.RefreshToken().Revoke(ctx, where.F("userID", userID)); err != nil {
+		return err
+	}
+
+	if err := b.store.Session().Revoke(ctx, where.F("userID", userID)); err != nil {
+		return err
+	}
+
+	return b.store.AccessToken().Delete(ctx, where.F("userID", userID))
+}
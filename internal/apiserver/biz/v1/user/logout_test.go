@@ -0,0 +1,158 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// login 为用户签发一组新的登录令牌，返回解析后的 token 声明、refresh token 及以该 token 认证的上下文
+func login(t *testing.T, b *userBiz, userM *model.User) (*token.Claims, string, context.Context) {
+	t.Helper()
+
+	resp, err := b.issueTokens(context.Background(), userM)
+	require.NoError(t, err)
+	claims, err := token.Parse(resp.Token, testTokenKey)
+	require.NoError(t, err)
+
+	ctx := contextx.WithPrincipal(context.Background(), &contextx.Principal{
+		UserID:    claims.Identity,
+		Username:  claims.Username,
+		Roles:     []string{claims.Role},
+		TokenID:   claims.ID,
+		SessionID: claims.SessionID,
+	})
+	return claims, resp.RefreshToken, ctx
+}
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		name         string
+		refreshToken func(current string, other string) string
+		wantRevoked  bool
+	}{
+		{name: "without refresh token", refreshToken: func(string, string) string { return "" }},
+		{name: "with current refresh token", refreshToken: func(current string, _ string) string { return current }},
+		// 传入的 refresh token 无效时注销仍然成功
+		{name: "with invalid refresh token", refreshToken: func(string, string) string { return "invalid" }},
+		// 传入其他会话的 refresh token 时，一并吊销其所在的令牌族
+		{name: "with other refresh token", refreshToken: func(_ string, other string) string { return other }, wantRevoked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := storetest.New(&model.User{UserID: "user-000001", Username: "alice", Role: known.RoleUser})
+			b := newTestBiz(s)
+			claims, refreshToken, ctx := login(t, b, s.Users["user-000001"])
+			otherClaims, otherRefreshToken, _ := login(t, b, s.Users["user-000001"])
+
+			_, err := b.Logout(ctx, &apiv1.LogoutRequest{RefreshToken: tt.refreshToken(refreshToken, otherRefreshToken)})
+			require.NoError(t, err)
+
+			// 当前 token、登录会话及其 refresh token 被吊销
+			revoked, err := b.revoker.IsRevoked(context.Background(), claims)
+			require.NoError(t, err)
+			assert.True(t, revoked)
+			assert.NotNil(t, s.Sessions[0].RevokedAt)
+			assert.NotNil(t, s.RefreshTokens[0].RevokedAt)
+
+			// 其他登录会话签发的 token 不受影响
+			revoked, err = b.revoker.IsRevoked(context.Background(), otherClaims)
+			require.NoError(t, err)
+			assert.False(t, revoked)
+			assert.Nil(t, s.Sessions[1].RevokedAt)
+			assert.Equal(t, tt.wantRevoked, s.RefreshTokens[1].RevokedAt != nil)
+		})
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	s := storetest.New(
+		&model.User{UserID: "user-000001", Username: "alice", Role: known.RoleUser},
+		&model.User{UserID: "user-000002", Username: "bob", Role: known.RoleUser},
+	)
+	b := newTestBiz(s)
+	claims, _, ctx := login(t, b, s.Users["user-000001"])
+	otherClaims, _, _ := login(t, b, s.Users["user-000001"])
+	bobClaims, _, _ := login(t, b, s.Users["user-000002"])
+
+	_, err := b.LogoutAll(ctx, &apiv1.LogoutAllRequest{})
+	require.NoError(t, err)
+
+	// 当前用户所有会话签发的 token、refresh token 及登录会话都被吊销
+	for _, c := range []*token.Claims{claims, otherClaims} {
+		revoked, err := b.revoker.IsRevoked(context.Background(), c)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+	}
+	for i := range 2 {
+		assert.NotNil(t, s.Sessions[i].RevokedAt)
+		assert.NotNil(t, s.RefreshTokens[i].RevokedAt)
+	}
+
+	// 其他用户不受影响
+	revoked, err := b.revoker.IsRevoked(context.Background(), bobClaims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	assert.Nil(t, s.Sessions[2].RevokedAt)
+	assert.Nil(t, s.RefreshTokens[2].RevokedAt)
+
+	// 注销后重新登录获得的 token 仍然有效
+	newClaims, _, _ := login(t, b, s.Users["user-000001"])
+	revoked, err = b.revoker.IsRevoked(context.Background(), newClaims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestLogoutAllAccessTokens(t *testing.T) {
+	s := storetest.New(
+		&model.User{UserID: "user-000001", Username: "alice", Role: known.RoleUser},
+		&model.User{UserID: "user-000002", Username: "bob", Role: known.RoleUser},
+	)
+	s.AccessTokens = []*model.AccessToken{
+		{TokenID: "pat-000001", UserID: "user-000001"},
+		{TokenID: "pat-000002", UserID: "user-000002"},
+	}
+	b := newTestBiz(s)
+	ctx := contextx.WithUserID(context.Background(), "user-000001")
+
+	// 注销所有登录时一并删除当前用户的个人访问令牌，其他用户的令牌不受影响
+	_, err := b.LogoutAll(ctx, &apiv1.LogoutAllRequest{})
+	require.NoError(t, err)
+	require.Len(t, s.AccessTokens, 1)
+	assert.Equal(t, "user-000002", s.AccessTokens[0].UserID)
+}
+
+func TestDeleteFailedKeepsTokens(t *testing.T) {
+	s := storetest.New(&model.User{UserID: "user-000001", Username: "alice", Role: known.RoleUser})
+	b := newTestBiz(s)
+	claims, _, _ := login(t, b, s.Users["user-000001"])
+
+	// 删除账号的事务失败时，已签发的 token 不应被吊销
+	errDelete := errors.New("delete recovery codes failed")
+	s.Errs["RecoveryCode.Delete"] = errDelete
+	_, err := b.Delete(context.Background(), &apiv1.DeleteUserRequest{UserID: "user-000001"})
+	require.ErrorIs(t, err, errDelete)
+	assert.Contains(t, s.Users, "user-000001")
+
+	revoked, err := b.revoker.IsRevoked(context.Background(), claims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// 删除成功后已签发的 token 随即失效
+	delete(s.Errs, "RecoveryCode.Delete")
+	_, err = b.Delete(context.Background(), &apiv1.DeleteUserRequest{UserID: "user-000001"})
+	require.NoError(t, err)
+	assert.NotContains(t, s.Users, "user-000001")
+	require.Len(t, s.RevokedTokens, 1)
+	assert.Equal(t, "user-000001", s.RevokedTokens[0].UserID)
+}
@@ -0,0 +1,361 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/conversion"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/oidc"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+const (
+	// maxUsernameLength 是自动创建账号时用户名的最大长度，与注册接口的校验规则一致
+	maxUsernameLength = 32
+	// maxNicknameLength 是自动创建账号时昵称的最大长度，与注册接口的校验规则一致
+	maxNicknameLength = 32
+	// provisionUsernameAttempts 是自动创建账号时生成可用用户名的最大尝试次数
+	provisionUsernameAttempts = 5
+)
+
+// ListOIDCProvider 返回可用于登录的外部 OIDC 提供方列表
+func (b *userBiz) ListOIDCProvider(ctx context.Context, rq *apiv1.ListOIDCProviderRequest) (*apiv1.ListOIDCProviderResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.ListOIDCProvider")
+	defer span.End()
+
+	providers := make([]*apiv1.OIDCProvider, 0, len(b.providers.List()))
+	for _, p := range b.providers.List() {
+		providers = append(providers, &apiv1.OIDCProvider{Name: p.Name(), DisplayName: p.DisplayName()})
+	}
+
+	return &apiv1.ListOIDCProviderResponse{Providers: providers}, nil
+}
+
+// AuthorizeOIDCLogin 发起使用外部账号登录，返回跳转到提供方的授权地址和状态 token
+func (b *userBiz) AuthorizeOIDCLogin(ctx context.Context, rq *apiv1.AuthorizeOIDCRequest) (*apiv1.AuthorizeOIDCResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.AuthorizeOIDCLogin")
+	defer span.End()
+
+	// 登录时还没有用户身份，状态 token 的 subject 使用提供方名称
+	return b.authorizeOIDC(ctx, rq.Provider, token.PurposeOIDCLogin, rq.Provider)
+}
+
+// LoginOIDC 使用外部账号登录. 外部账号已关联本地账号时登录该账号；
+// 未关联时，如果提供方允许自动创建账号，则创建一个新账号并关联，否则返回错误.
+// 已有的本地账号不会根据邮箱自动关联，需要登录后在账号设置中手动关联，以免提供方未验证的邮箱被用于接管账号.
+func (b *userBiz) LoginOIDC(ctx context.Context, rq *apiv1.OIDCLoginRequest) (*apiv1.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.LoginOIDC")
+	defer span.End()
+
+	resp, err := b.loginOIDC(ctx, rq)
+	observeLogin(known.LoginMethodOIDC, resp, err)
+	return resp, err
+}
+
+// loginOIDC 使用授权码换取外部账号信息，登录关联的本地账号或自动创建账号
+func (b *userBiz) loginOIDC(ctx context.Context, rq *apiv1.OIDCLoginRequest) (*apiv1.LoginResponse, error) {
+	p, identity, err := b.exchangeOIDC(ctx, rq.Provider, token.PurposeOIDCLogin, rq.Provider, rq.Code, rq.State, rq.StateToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var userM *model.User
+	identityM, err := b.store.ExternalIdentity().Get(ctx, where.F("provider", p.Name(), "subject", identity.Subject))
+	switch {
+	case err == nil:
+		userM, err = b.store.User().Get(ctx, where.F("userID", identityM.UserID))
+		if errors.Is(err, errorsx.ErrUserNotFound) {
+			return nil, errorsx.ErrIdentityNotLinked
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// 记录外部账号最新的邮箱及最后一次登录时间，更新失败不影响登录
+		identityM.Email = identity.Email
+		if err := b.store.ExternalIdentity().Update(ctx, identityM); err != nil {
+			slog.WarnContext(ctx, "Failed to update external identity", "userID", userM.UserID, "provider", p.Name(), "err", err)
+		}
+	case errors.Is(err, errorsx.ErrIdentityNotFound):
+		if !p.AutoProvision() {
+			return nil, errorsx.ErrIdentityNotLinked
+		}
+
+		userM, err = b.provisionUser(ctx, p, identity)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	return b.completeLogin(ctx, userM)
+}
+
+// ListExternalIdentity 返回当前用户已关联的外部账号列表
+func (b *userBiz) ListExternalIdentity(ctx context.Context, rq *apiv1.ListExternalIdentityRequest) (*apiv1.ListExternalIdentityResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.ListExternalIdentity")
+	defer span.End()
+
+	count, identityList, err := b.store.ExternalIdentity().List(ctx, where.F("userID", contextx.UserID(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]*apiv1.ExternalIdentity, 0, len(identityList))
+	for _, identity := range identityList {
+		identities = append(identities, conversion.ExternalIdentityModelToExternalIdentityV1(identity))
+	}
+
+	return &apiv1.ListExternalIdentityResponse{TotalCount: count, Identities: identities}, nil
+}
+
+// AuthorizeExternalIdentity 发起为当前用户关联外部账号，返回跳转到提供方的授权地址和状态 token
+func (b *userBiz) AuthorizeExternalIdentity(ctx context.Context, rq *apiv1.AuthorizeOIDCRequest) (*apiv1.AuthorizeOIDCResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.AuthorizeExternalIdentity")
+	defer span.End()
+
+	// 状态 token 与当前用户绑定，只能由该用户完成关联
+	return b.authorizeOIDC(ctx, rq.Provider, token.PurposeOIDCLink, contextx.UserID(ctx))
+}
+
+// LinkExternalIdentity 为当前用户关联外部账号. 每个提供方只能关联一个外部账号，
+// 一个外部账号也只能关联一个用户.
+func (b *userBiz) LinkExternalIdentity(ctx context.Context, rq *apiv1.LinkExternalIdentityRequest) (*apiv1.LinkExternalIdentityResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.LinkExternalIdentity")
+	defer span.End()
+
+	userID := contextx.UserID(ctx)
+	p, identity, err := b.exchangeOIDC(ctx, rq.Provider, token.PurposeOIDCLink, userID, rq.Code, rq.State, rq.StateToken)
+	if err != nil {
+		return nil, err
+	}
+
+	identityM, err := b.store.ExternalIdentity().Get(ctx, where.F("provider", p.Name(), "subject", identity.Subject))
+	if err == nil {
+		// 重复关联同一个外部账号时直接返回
+		if identityM.UserID == userID {
+			return &apiv1.LinkExternalIdentityResponse{Identity: conversion.ExternalIdentityModelToExternalIdentityV1(identityM)}, nil
+		}
+		return nil, errorsx.ErrIdentityAlreadyLinked
+	}
+	if !errors.Is(err, errorsx.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	if _, err := b.store.ExternalIdentity().Get(ctx, where.F("userID", userID, "provider", p.Name())); err == nil {
+		return nil, errorsx.ErrIdentityAlreadyLinked
+	} else if !errors.Is(err, errorsx.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	identityM = &model.ExternalIdentity{UserID: userID, Provider: p.Name(), Subject: identity.Subject, Email: identity.Email}
+	if err := b.store.ExternalIdentity().Create(ctx, identityM); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.LinkExternalIdentityResponse{Identity: conversion.ExternalIdentityModelToExternalIdentityV1(identityM)}, nil
+}
+
+// UnlinkExternalIdentity 解除当前用户与指定提供方外部账号的关联.
+// 自动创建的账号没有设置过密码，不允许解除唯一的外部账号关联，需要先通过忘记密码设置密码，否则将无法再登录.
+func (b *userBiz) UnlinkExternalIdentity(ctx context.Context, rq *apiv1.UnlinkExternalIdentityRequest) (*apiv1.UnlinkExternalIdentityResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.UnlinkExternalIdentity")
+	defer span.End()
+
+	userID := contextx.UserID(ctx)
+	whr := where.F("userID", userID, "provider", rq.Provider)
+	if _, err := b.store.ExternalIdentity().Get(ctx, whr); err != nil {
+		return nil, err
+	}
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", userID))
+	if err != nil {
+		return nil, err
+	}
+	if userM.Passwordless {
+		count, _, err := b.store.ExternalIdentity().List(ctx, where.F("userID", userID))
+		if err != nil {
+			return nil, err
+		}
+		if count <= 1 {
+			return nil, errorsx.ErrLastIdentity
+		}
+	}
+
+	if err := b.store.ExternalIdentity().Delete(ctx, whr); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.UnlinkExternalIdentityResponse{}, nil
+}
+
+// authorizeOIDC 生成本次流程的随机密钥，返回跳转到提供方的授权地址以及保存该密钥的状态 token.
+// 状态 token 的用途中包含提供方名称，因此只能在发起流程的提供方上使用.
+func (b *userBiz) authorizeOIDC(ctx context.Context, name string, purpose string, subject string) (*apiv1.AuthorizeOIDCResponse, error) {
+	p, ok := b.providers.Get(name)
+	if !ok {
+		return nil, errorsx.ErrOIDCProviderNotFound
+	}
+
+	secret, err := oidc.NewSecret()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to generate oidc secret", "err", err)
+		return nil, errorsx.ErrInternal
+	}
+
+	authorizeURL, err := p.AuthCodeURL(ctx, secret)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to build oidc authorize url", "provider", name, "err", err)
+		return nil, errorsx.ErrOIDCUnavailable
+	}
+
+	stateToken, stateExpireAt, err := token.SignChallenge(subject, purpose+":"+name, b.providers.StateExpiration(), secret)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to sign oidc state token", "err", err)
+		return nil, errorsx.ErrSignToken
+	}
+
+	return &apiv1.AuthorizeOIDCResponse{AuthorizeURL: authorizeURL, StateToken: stateToken, StateExpireAt: stateExpireAt}, nil
+}
+
+// exchangeOIDC 校验状态 token 的用途及 subject，然后使用授权码换取并校验外部账号信息
+func (b *userBiz) exchangeOIDC(ctx context.Context, name string, purpose string, subject string, code string, state string, stateToken string) (*oidc.Provider, *oidc.Identity, error) {
+	p, ok := b.providers.Get(name)
+	if !ok {
+		return nil, nil, errorsx.ErrOIDCProviderNotFound
+	}
+
+	tokenSubject, secret, err := token.ParseChallenge(stateToken, purpose+":"+name)
+	if err != nil || tokenSubject != subject || secret == "" {
+		return nil, nil, errorsx.ErrOIDCLoginFailed
+	}
+
+	identity, err := p.Exchange(ctx, code, state, secret)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to complete oidc authorization", "provider", name, "err", err)
+		return nil, nil, errorsx.ErrOIDCLoginFailed
+	}
+	if identity.Subject == "" {
+		return nil, nil, errorsx.ErrOIDCLoginFailed
+	}
+
+	return p, identity, nil
+}
+
+// provisionUser 为首次登录的外部账号创建本地账号并关联. 账号使用随机密码并标记为没有可用的密码，
+// 用户可以通过忘记密码设置自己的密码. 提供方已验证的邮箱直接视为已验证.
+func (b *userBiz) provisionUser(ctx context.Context, p *oidc.Provider, identity *oidc.Identity) (*model.User, error) {
+	password, err := oidc.NewSecret()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to generate random password", "err", err)
+		return nil, errorsx.ErrInternal
+	}
+
+	username, err := b.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	userM := &model.User{
+		Username:     username,
+		Password:     password,
+		Passwordless: true,
+		Nickname:     truncate(identity.Name, maxNicknameLength),
+		Email:        identity.Email,
+	}
+	if identity.Email != "" && identity.EmailVerified {
+		now := time.Now()
+		userM.EmailVerifiedAt = &now
+	}
+
+	err = b.store.TX(ctx, func(ctx context.Context) error {
+		if err := b.store.User().Create(ctx, userM); err != nil {
+			return err
+		}
+
+		return b.store.ExternalIdentity().Create(ctx, &model.ExternalIdentity{
+			UserID:   userM.UserID,
+			Provider: p.Name(),
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Provisioned user from external identity", "userID", userM.UserID, "provider", p.Name())
+	return userM, nil
+}
+
+// availableUsername 根据外部账号信息生成一个未被占用的用户名. 优先使用偏好的用户名，其次使用邮箱前缀，
+// 用户名已被占用时追加随机后缀.
+func (b *userBiz) availableUsername(ctx context.Context, identity *oidc.Identity) (string, error) {
+	base := sanitizeUsername(identity.PreferredUsername)
+	if base == "" {
+		local, _, _ := strings.Cut(identity.Email, "@")
+		base = sanitizeUsername(local)
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	candidate := base
+	for range provisionUsernameAttempts {
+		if len(candidate) >= 4 {
+			_, err := b.store.User().Get(ctx, where.F("username", candidate))
+			if errors.Is(err, errorsx.ErrUserNotFound) {
+				return candidate, nil
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			slog.ErrorContext(ctx, "Failed to generate username suffix", "err", err)
+			return "", errorsx.ErrInternal
+		}
+		candidate = truncate(base, maxUsernameLength-7) + "-" + hex.EncodeToString(suffix)
+	}
+
+	return "", errorsx.ErrUserAlreadyExists
+}
+
+// sanitizeUsername 只保留用户名中的字母、数字、下划线、点和连字符
+func sanitizeUsername(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		default:
+			return -1
+		}
+	}, s)
+
+	return truncate(s, maxUsernameLength)
+}
+
+// truncate 将字符串截断为最多 n 个字符
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return string([]rune(s)[:n])
+}
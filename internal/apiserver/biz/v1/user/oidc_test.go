@@ -0,0 +1,50 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnlinkExternalIdentity(t *testing.T) {
+	tests := []struct {
+		name         string
+		passwordless bool
+		providers    []string
+		wantErr      error
+	}{
+		{name: "with password", providers: []string{"github"}},
+		{name: "passwordless with other identity", passwordless: true, providers: []string{"github", "google"}},
+		// 没有设置密码的用户解除唯一的外部账号关联后将无法再登录
+		{name: "passwordless last identity", passwordless: true, providers: []string{"github"}, wantErr: errorsx.ErrLastIdentity},
+		{name: "not linked", providers: []string{"google"}, wantErr: errorsx.ErrIdentityNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := storetest.New(&model.User{UserID: "user-000001", Username: "alice", Role: known.RoleUser, Passwordless: tt.passwordless})
+			for _, provider := range tt.providers {
+				s.ExternalIdentities = append(s.ExternalIdentities, &model.ExternalIdentity{UserID: "user-000001", Provider: provider, Subject: provider + "-alice"})
+			}
+			b := newTestBiz(s)
+			ctx := contextx.WithUserID(context.Background(), "user-000001")
+
+			_, err := b.UnlinkExternalIdentity(ctx, &apiv1.UnlinkExternalIdentityRequest{Provider: "github"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, s.ExternalIdentities, len(tt.providers))
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, s.ExternalIdentities, len(tt.providers)-1)
+		})
+	}
+}
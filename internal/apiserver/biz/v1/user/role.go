@@ -0,0 +1,63 @@
+package user
+
+import (
+	"context"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm/clause"
+)
+
+// UpdateRole 修改用户角色. 角色会写入 token，修改后吊销该用户已签发的 token，
+// 用户刷新令牌或重新登录后新角色生效. 不允许降级最后一个管理员.
+func (b *userBiz) UpdateRole(ctx context.Context, rq *apiv1.UpdateUserRoleRequest) (*apiv1.UpdateUserRoleResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.UpdateRole")
+	defer span.End()
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", rq.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	if userM.Role == rq.Role {
+		return &apiv1.UpdateUserRoleResponse{}, nil
+	}
+
+	err = b.store.TX(ctx, func(ctx context.Context) error {
+		// 不允许降级最后一个管理员（包括管理员降级自己），否则将没有人可以审核内容、解锁账号
+		if userM.Role == known.RoleAdmin {
+			if err := b.ensureOtherAdmins(ctx); err != nil {
+				return err
+			}
+		}
+
+		userM.Role = rq.Role
+		return b.store.User().Update(ctx, userM)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.revoker.RevokeUser(ctx, userM.UserID); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.UpdateUserRoleResponse{}, nil
+}
+
+// ensureOtherAdmins 确认系统中至少还有两个管理员，用于降级或删除管理员之前的检查，需要在事务中调用.
+// 统计时锁定所有管理员记录，避免并发降级或删除不同的管理员后没有任何管理员
+func (b *userBiz) ensureOtherAdmins(ctx context.Context) error {
+	count, _, err := b.store.User().List(ctx, where.F("role", known.RoleAdmin).C(clause.Locking{Strength: clause.LockingStrengthUpdate}))
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return errorsx.ErrLastAdmin
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRoleLastAdmin(t *testing.T) {
+	s := storetest.New(
+		&model.User{UserID: "user-000001", Role: known.RoleAdmin},
+		&model.User{UserID: "user-000002", Role: known.RoleAdmin},
+	)
+	b := newTestBiz(s)
+	ctx := context.Background()
+
+	// 还有其他管理员时可以降级
+	_, err := b.UpdateRole(ctx, &apiv1.UpdateUserRoleRequest{UserID: "user-000002", Role: known.RoleUser})
+	require.NoError(t, err)
+	assert.Equal(t, known.RoleUser, s.Users["user-000002"].Role)
+
+	// 不能降级最后一个管理员
+	_, err = b.UpdateRole(ctx, &apiv1.UpdateUserRoleRequest{UserID: "user-000001", Role: known.RoleUser})
+	assert.ErrorIs(t, err, errorsx.ErrLastAdmin)
+	assert.Equal(t, known.RoleAdmin, s.Users["user-000001"].Role)
+}
+
+func TestDeleteLastAdmin(t *testing.T) {
+	s := storetest.New(
+		&model.User{UserID: "user-000001", Role: known.RoleAdmin},
+		&model.User{UserID: "user-000002", Role: known.RoleAdmin},
+		&model.User{UserID: "user-000003", Role: known.RoleUser},
+	)
+	s.AccessTokens = []*model.AccessToken{{TokenID: "pat-000001", UserID: "user-000002"}}
+	b := newTestBiz(s)
+	ctx := context.Background()
+
+	// 还有其他管理员时可以删除管理员，同时清理该用户的个人访问令牌
+	_, err := b.Delete(ctx, &apiv1.DeleteUserRequest{UserID: "user-000002"})
+	require.NoError(t, err)
+	assert.NotContains(t, s.Users, "user-000002")
+	assert.Empty(t, s.AccessTokens)
+
+	// 不能删除最后一个管理员
+	_, err = b.Delete(ctx, &apiv1.DeleteUserRequest{UserID: "user-000001"})
+	assert.ErrorIs(t, err, errorsx.ErrLastAdmin)
+	assert.Contains(t, s.Users, "user-000001")
+
+	// 普通用户不受影响
+	_, err = b.Delete(ctx, &apiv1.DeleteUserRequest{UserID: "user-000003"})
+	require.NoError(t, err)
+	assert.NotContains(t, s.Users, "user-000003")
+}
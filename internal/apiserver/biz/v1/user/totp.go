@@ -0,0 +1,316 @@
+package user
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/auth"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+const (
+	// totpIssuer 是认证器应用中显示的服务名称
+	totpIssuer = "fastgo"
+	// recoveryCodeCount 是每次生成的恢复码数量
+	recoveryCodeCount = 10
+	// totpCodeLength 是 TOTP 验证码的长度，长度不同的验证码按恢复码处理
+	totpCodeLength = 6
+)
+
+// LoginTOTP 使用 /login 返回的挑战 token 和验证码（TOTP 验证码或恢复码）完成两步验证登录
+func (b *userBiz) LoginTOTP(ctx context.Context, rq *apiv1.LoginTOTPRequest) (*apiv1.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.LoginTOTP")
+	defer span.End()
+
+	resp, err := b.loginTOTP(ctx, rq)
+	observeLogin(known.LoginMethodTOTP, resp, err)
+	return resp, err
+}
+
+// loginTOTP 校验挑战 token 和验证码，通过后签发 token
+func (b *userBiz) loginTOTP(ctx context.Context, rq *apiv1.LoginTOTPRequest) (*apiv1.LoginResponse, error) {
+	userID, _, err := token.ParseChallenge(rq.ChallengeToken, token.PurposeTwoFactor)
+	if err != nil {
+		return nil, errorsx.ErrChallengeInvalid
+	}
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", userID))
+	if errors.Is(err, errorsx.ErrUserNotFound) {
+		return nil, errorsx.ErrChallengeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if userM.Status == known.UserStatusSuspended {
+		return nil, errorsx.ErrUserSuspended
+	}
+
+	totpM, enabled, err := b.totpEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, errorsx.ErrChallengeInvalid
+	}
+
+	// 两步验证的验证码错误同样计入登录失败次数，防止暴力破解验证码
+	if err := b.checkLockout(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := b.verifySecondFactor(ctx, totpM, rq.Code); err != nil {
+		if isSecondFactorFailure(err) {
+			return nil, b.loginFailed(ctx, userID, err)
+		}
+		return nil, err
+	}
+
+	b.lockout.Reset(userID)
+	return b.issueTokens(ctx, userM)
+}
+
+// EnrollTOTP 开始设置两步验证：生成新的 TOTP 密钥. 需要调用 ConfirmTOTP 提交验证码后才会生效.
+func (b *userBiz) EnrollTOTP(ctx context.Context, rq *apiv1.EnrollTOTPRequest) (*apiv1.EnrollTOTPResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.EnrollTOTP")
+	defer span.End()
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", contextx.UserID(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	totpM, err := b.store.UserTOTP().Get(ctx, where.F("userID", userM.UserID))
+	if err != nil && !errors.Is(err, errorsx.ErrTOTPNotEnabled) {
+		return nil, err
+	}
+	if totpM != nil && totpM.EnabledAt != nil {
+		return nil, errorsx.ErrTOTPAlreadyEnabled
+	}
+
+	key, err := auth.GenerateTOTP(totpIssuer, userM.Username)
+	if err != nil {
+		return nil, errorsx.ErrInternal.WithMessage("%s", err.Error())
+	}
+
+	// 重复设置时覆盖尚未确认的密钥
+	if totpM == nil {
+		err = b.store.UserTOTP().Create(ctx, &model.UserTOTP{UserID: userM.UserID, Secret: key.Secret})
+	} else {
+		totpM.Secret = key.Secret
+		totpM.LastUsedStep = 0
+		err = b.store.UserTOTP().Update(ctx, totpM)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.EnrollTOTPResponse{
+		Secret: key.Secret,
+		URI:    key.URI,
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(key.QRCode),
+	}, nil
+}
+
+// ConfirmTOTP 提交认证器应用生成的验证码，确认开启两步验证，并返回恢复码
+func (b *userBiz) ConfirmTOTP(ctx context.Context, rq *apiv1.ConfirmTOTPRequest) (*apiv1.ConfirmTOTPResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.ConfirmTOTP")
+	defer span.End()
+
+	totpM, err := b.store.UserTOTP().Get(ctx, where.F("userID", contextx.UserID(ctx)))
+	if err != nil {
+		return nil, err
+	}
+	if totpM.EnabledAt != nil {
+		return nil, errorsx.ErrTOTPAlreadyEnabled
+	}
+
+	// 确认时只接受 TOTP 验证码
+	if err := b.verifyTOTP(ctx, totpM, rq.Code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = b.store.TX(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		totpM.EnabledAt = &now
+		if err := b.store.UserTOTP().Update(ctx, totpM); err != nil {
+			return err
+		}
+
+		codes, err = b.resetRecoveryCodes(ctx, totpM.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.ConfirmTOTPResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP 关闭两步验证，需要重新验证密码和验证码
+func (b *userBiz) DisableTOTP(ctx context.Context, rq *apiv1.DisableTOTPRequest) (*apiv1.DisableTOTPResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.DisableTOTP")
+	defer span.End()
+
+	userID := contextx.UserID(ctx)
+	if err := b.reauthenticate(ctx, userID, rq.Password, rq.Code); err != nil {
+		return nil, err
+	}
+
+	err := b.store.TX(ctx, func(ctx context.Context) error {
+		if err := b.store.UserTOTP().Delete(ctx, where.F("userID", userID)); err != nil {
+			return err
+		}
+		return b.store.RecoveryCode().Delete(ctx, where.F("userID", userID))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.DisableTOTPResponse{}, nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧的恢复码全部失效. 需要重新验证密码和验证码.
+func (b *userBiz) RegenerateRecoveryCodes(ctx context.Context, rq *apiv1.RegenerateRecoveryCodesRequest) (*apiv1.RegenerateRecoveryCodesResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.RegenerateRecoveryCodes")
+	defer span.End()
+
+	userID := contextx.UserID(ctx)
+	if err := b.reauthenticate(ctx, userID, rq.Password, rq.Code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := b.store.TX(ctx, func(ctx context.Context) error {
+		var err error
+		codes, err = b.resetRecoveryCodes(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.RegenerateRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// totpEnabled 查询用户的两步验证设置，返回用户是否已开启两步验证
+func (b *userBiz) totpEnabled(ctx context.Context, userID string) (*model.UserTOTP, bool, error) {
+	totpM, err := b.store.UserTOTP().Get(ctx, where.F("userID", userID))
+	if err != nil {
+		if errors.Is(err, errorsx.ErrTOTPNotEnabled) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return totpM, totpM.EnabledAt != nil, nil
+}
+
+// reauthenticate 在执行敏感操作前重新验证用户的密码和第二因素. 没有可用密码的用户（例如通过外部账号自动创建的用户）
+// 只验证第二因素. 密码或验证码错误与登录一样计入失败次数，防止通过敏感操作暴力破解密码
+func (b *userBiz) reauthenticate(ctx context.Context, userID string, password string, code string) error {
+	userM, err := b.store.User().Get(ctx, where.F("userID", userID))
+	if err != nil {
+		return err
+	}
+
+	totpM, enabled, err := b.totpEnabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return errorsx.ErrTOTPNotEnabled
+	}
+
+	if err := b.checkLockout(ctx, userID); err != nil {
+		return err
+	}
+	if !userM.Passwordless {
+		if err := auth.Compare(userM.Password, password); err != nil {
+			return b.loginFailed(ctx, userID, errorsx.ErrPasswordInvalid)
+		}
+	}
+	if err := b.verifySecondFactor(ctx, totpM, code); err != nil {
+		if isSecondFactorFailure(err) {
+			return b.loginFailed(ctx, userID, err)
+		}
+		return err
+	}
+
+	b.lockout.Reset(userID)
+	return nil
+}
+
+// verifySecondFactor 校验 TOTP 验证码或恢复码
+func (b *userBiz) verifySecondFactor(ctx context.Context, totpM *model.UserTOTP, code string) error {
+	if len(code) == totpCodeLength {
+		return b.verifyTOTP(ctx, totpM, code)
+	}
+
+	codeM, err := b.store.RecoveryCode().Get(ctx, where.F("userID", totpM.UserID, "codeHash", token.HashOpaque(auth.NormalizeRecoveryCode(code))))
+	if err != nil {
+		return err
+	}
+
+	// 原子地将恢复码标记为已使用，保证每个恢复码只能使用一次
+	ok, err := b.store.RecoveryCode().MarkUsed(ctx, codeM.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errorsx.ErrTOTPCodeInvalid
+	}
+
+	slog.InfoContext(ctx, "Recovery code used", "userID", totpM.UserID)
+	return nil
+}
+
+// verifyTOTP 校验 TOTP 验证码，同一个验证码只能使用一次
+func (b *userBiz) verifyTOTP(ctx context.Context, totpM *model.UserTOTP, code string) error {
+	step, ok := auth.ValidateTOTP(totpM.Secret, code, time.Now())
+	if !ok {
+		return errorsx.ErrTOTPCodeInvalid
+	}
+
+	ok, err := b.store.UserTOTP().Advance(ctx, totpM.ID, step)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errorsx.ErrTOTPCodeInvalid
+	}
+	totpM.LastUsedStep = step
+
+	return nil
+}
+
+// resetRecoveryCodes 删除用户已有的恢复码并生成新的恢复码，数据库中只保存其哈希值
+func (b *userBiz) resetRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, errorsx.ErrInternal.WithMessage("%s", err.Error())
+	}
+
+	if err := b.store.RecoveryCode().Delete(ctx, where.F("userID", userID)); err != nil {
+		return nil, err
+	}
+
+	codeList := make([]*model.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		codeList = append(codeList, &model.RecoveryCode{UserID: userID, CodeHash: token.HashOpaque(code)})
+	}
+	if err := b.store.RecoveryCode().Create(ctx, codeList); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
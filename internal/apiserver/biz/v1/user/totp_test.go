@@ -0,0 +1,108 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/auth"
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTOTPStore 创建一个开启了两步验证的用户，返回其可用的恢复码
+func newTOTPStore(t *testing.T, passwordless bool) (*storetest.Store, []string) {
+	t.Helper()
+
+	hash, err := auth.Encrypt("fastgo1234")
+	require.NoError(t, err)
+	s := storetest.New(&model.User{UserID: "user-000001", Username: "alice", Password: hash, Passwordless: passwordless, Role: known.RoleUser})
+
+	now := time.Now()
+	s.TOTPs = []*model.UserTOTP{{ID: 1, UserID: "user-000001", EnabledAt: &now}}
+	codes := make([]string, 0, 10)
+	for i := range 10 {
+		code := fmt.Sprintf("code-%02d", i)
+		codes = append(codes, code)
+		s.RecoveryCodes = append(s.RecoveryCodes, &model.RecoveryCode{ID: int64(i + 1), UserID: "user-000001", CodeHash: token.HashOpaque(auth.NormalizeRecoveryCode(code))})
+	}
+	return s, codes
+}
+
+func TestReauthenticate(t *testing.T) {
+	tests := []struct {
+		name         string
+		passwordless bool
+		totpDisabled bool
+		password     string
+		validCode    bool
+		wantErr      error
+	}{
+		{name: "valid", password: "fastgo1234", validCode: true},
+		{name: "wrong password", password: "wrong-password", validCode: true, wantErr: errorsx.ErrPasswordInvalid},
+		{name: "empty password", password: "", validCode: true, wantErr: errorsx.ErrPasswordInvalid},
+		{name: "wrong code", password: "fastgo1234", wantErr: errorsx.ErrTOTPCodeInvalid},
+		{name: "totp not enabled", password: "fastgo1234", validCode: true, totpDisabled: true, wantErr: errorsx.ErrTOTPNotEnabled},
+		// 没有可用密码的用户只验证第二因素
+		{name: "passwordless", passwordless: true, validCode: true},
+		{name: "passwordless wrong code", passwordless: true, wantErr: errorsx.ErrTOTPCodeInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, codes := newTOTPStore(t, tt.passwordless)
+			if tt.totpDisabled {
+				s.TOTPs = nil
+			}
+			b := newTestBiz(s)
+
+			code := "wrong-code"
+			if tt.validCode {
+				code = codes[0]
+			}
+			err := b.reauthenticate(context.Background(), "user-000001", tt.password, code)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReauthenticateLockout(t *testing.T) {
+	s, codes := newTOTPStore(t, false)
+	b := newTestBiz(s)
+	ctx := context.Background()
+
+	// 密码和验证码错误都计入失败次数，达到上限后锁定账号
+	maxAttempts := genericoptions.NewLockoutOptions().MaxAttempts
+	for i := range maxAttempts - 1 {
+		password, code := "wrong-password", codes[0]
+		if i%2 == 1 {
+			password, code = "fastgo1234", "wrong-code"
+		}
+		err := b.reauthenticate(ctx, "user-000001", password, code)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, errorsx.ErrAccountLocked)
+	}
+	err := b.reauthenticate(ctx, "user-000001", "wrong-password", codes[0])
+	assert.ErrorIs(t, err, errorsx.ErrAccountLocked)
+
+	// 锁定期间即使密码和验证码正确也不能通过，也不会消耗恢复码
+	err = b.reauthenticate(ctx, "user-000001", "fastgo1234", codes[1])
+	assert.ErrorIs(t, err, errorsx.ErrAccountLocked)
+	assert.Nil(t, s.RecoveryCodes[1].UsedAt)
+
+	// 锁定同样影响登录
+	_, err = b.login(ctx, &apiv1.LoginRequest{Username: "alice", Password: "fastgo1234"})
+	assert.ErrorIs(t, err, errorsx.ErrAccountLocked)
+}
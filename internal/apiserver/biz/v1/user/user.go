@@ -2,17 +2,25 @@ package user
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
 	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/conversion"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/lockout"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/mailer"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/oidc"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/revoker"
 	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
 	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
 	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
 	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
 	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
 	"github.com/TobyIcetea/fastgo/pkg/auth"
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
 	"github.com/TobyIcetea/fastgo/pkg/token"
 	"github.com/jinzhu/copier"
 	"github.com/onexstack/onexstack/pkg/store/where"
@@ -35,70 +43,294 @@ type UserExpansion interface {
 	Login(ctx context.Context, rq *apiv1.LoginRequest) (*apiv1.LoginResponse, error)
 	RefreshToken(ctx context.Context, rq *apiv1.RefreshTokenRequest) (*apiv1.RefreshTokenResponse, error)
 	ChangePassword(ctx context.Context, rq *apiv1.ChangePasswordRequest) (*apiv1.ChangePasswordResponse, error)
+	Logout(ctx context.Context, rq *apiv1.LogoutRequest) (*apiv1.LogoutResponse, error)
+	LogoutAll(ctx context.Context, rq *apiv1.LogoutAllRequest) (*apiv1.LogoutAllResponse, error)
+	UpdateRole(ctx context.Context, rq *apiv1.UpdateUserRoleRequest) (*apiv1.UpdateUserRoleResponse, error)
+	LoginTOTP(ctx context.Context, rq *apiv1.LoginTOTPRequest) (*apiv1.LoginResponse, error)
+	EnrollTOTP(ctx context.Context, rq *apiv1.EnrollTOTPRequest) (*apiv1.EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, rq *apiv1.ConfirmTOTPRequest) (*apiv1.ConfirmTOTPResponse, error)
+	DisableTOTP(ctx context.Context, rq *apiv1.DisableTOTPRequest) (*apiv1.DisableTOTPResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, rq *apiv1.RegenerateRecoveryCodesRequest) (*apiv1.RegenerateRecoveryCodesResponse, error)
+	VerifyEmail(ctx context.Context, rq *apiv1.VerifyEmailRequest) (*apiv1.VerifyEmailResponse, error)
+	SendVerificationEmail(ctx context.Context, rq *apiv1.SendVerificationEmailRequest) (*apiv1.SendVerificationEmailResponse, error)
+	ForgotPassword(ctx context.Context, rq *apiv1.ForgotPasswordRequest) (*apiv1.ForgotPasswordResponse, error)
+	ResetPassword(ctx context.Context, rq *apiv1.ResetPasswordRequest) (*apiv1.ResetPasswordResponse, error)
+	Unlock(ctx context.Context, rq *apiv1.UnlockUserRequest) (*apiv1.UnlockUserResponse, error)
+	ListOIDCProvider(ctx context.Context, rq *apiv1.ListOIDCProviderRequest) (*apiv1.ListOIDCProviderResponse, error)
+	AuthorizeOIDCLogin(ctx context.Context, rq *apiv1.AuthorizeOIDCRequest) (*apiv1.AuthorizeOIDCResponse, error)
+	LoginOIDC(ctx context.Context, rq *apiv1.OIDCLoginRequest) (*apiv1.LoginResponse, error)
+	ListExternalIdentity(ctx context.Context, rq *apiv1.ListExternalIdentityRequest) (*apiv1.ListExternalIdentityResponse, error)
+	AuthorizeExternalIdentity(ctx context.Context, rq *apiv1.AuthorizeOIDCRequest) (*apiv1.AuthorizeOIDCResponse, error)
+	LinkExternalIdentity(ctx context.Context, rq *apiv1.LinkExternalIdentityRequest) (*apiv1.LinkExternalIdentityResponse, error)
+	UnlinkExternalIdentity(ctx context.Context, rq *apiv1.UnlinkExternalIdentityRequest) (*apiv1.UnlinkExternalIdentityResponse, error)
 }
 
 // userBiz 是 UserBiz 接口的实现
 type userBiz struct {
-	store store.IStore
+	store    store.IStore
+	revoker  *revoker.Revoker
+	notifier *mailer.Notifier
+	mailOpts *genericoptions.MailOptions
+	lockout  *lockout.Lockout
+	// providers 保存可用于登录和关联的外部 OIDC 提供方
+	providers *oidc.Providers
 }
 
 // 确保 userBiz 实现了 UserBiz 接口
 var _ UserBiz = (*userBiz)(nil)
 
-func New(store store.IStore) *userBiz {
-	return &userBiz{store: store}
+func New(store store.IStore, revoker *revoker.Revoker, notifier *mailer.Notifier, mailOpts *genericoptions.MailOptions, lockout *lockout.Lockout, providers *oidc.Providers) *userBiz {
+	return &userBiz{store: store, revoker: revoker, notifier: notifier, mailOpts: mailOpts, lockout: lockout, providers: providers}
 }
 
 // Login 实现 UserBiz 接口中的 Login 方法.
+// 用户不存在和密码错误返回相同的错误. 同一账号或同一客户端 IP 连续登录失败过多时会被临时锁定.
 func (b *userBiz) Login(ctx context.Context, rq *apiv1.LoginRequest) (*apiv1.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.Login")
+	defer span.End()
+
+	resp, err := b.login(ctx, rq)
+	observeLogin(known.LoginMethodPassword, resp, err)
+	return resp, err
+}
+
+// login 使用用户名和密码登录
+func (b *userBiz) login(ctx context.Context, rq *apiv1.LoginRequest) (*apiv1.LoginResponse, error) {
 	// 获取登录用户的所有信息
 	whr := where.F("username", rq.Username)
 	userM, err := b.store.User().Get(ctx, whr)
+	if errors.Is(err, errorsx.ErrUserNotFound) {
+		// 用户不存在时仍然执行一次密码比较，并同样计入失败次数
+		account := unknownAccount(rq.Username)
+		if err := b.checkLockout(ctx, account); err != nil {
+			return nil, err
+		}
+		_ = auth.Compare(dummyPasswordHash(), rq.Password)
+		return nil, b.loginFailed(ctx, account, errorsx.ErrLoginFailed)
+	}
+	// 查询失败时不计入登录失败次数，避免数据库故障导致账号被锁定
 	if err != nil {
-		return nil, errorsx.ErrUserNotFound
+		return nil, err
+	}
+
+	// 账号被锁定期间不再校验密码
+	if err := b.checkLockout(ctx, userM.UserID); err != nil {
+		return nil, err
 	}
 
 	// 对比传入的明文密码和数据库中已加密过的密码是否匹配
 	if err := auth.Compare(userM.Password, rq.Password); err != nil {
-		slog.ErrorContext(ctx, "Failed to compare password", "err", err)
-		return nil, errorsx.ErrPasswordInvalid
+		slog.WarnContext(ctx, "Failed to compare password", "userID", userM.UserID, "err", err)
+		return nil, b.loginFailed(ctx, userM.UserID, errorsx.ErrLoginFailed)
+	}
+
+	// 密码使用旧的哈希算法或参数时，使用本次提交的明文密码重新哈希，更新失败不影响登录
+	if auth.NeedsRehash(userM.Password) {
+		b.rehashPassword(ctx, userM, rq.Password)
 	}
 
-	// 如果匹配成功，说明登录成功，签发 token 并返回
-	tokenStr, expireAt, err := token.Sign(userM.UserID)
+	return b.completeLogin(ctx, userM)
+}
+
+// rehashPassword 使用当前配置的哈希算法及参数重新哈希用户密码
+func (b *userBiz) rehashPassword(ctx context.Context, userM *model.User, password string) {
+	hash, err := auth.Encrypt(password)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to rehash password", "userID", userM.UserID, "err", err)
+		return
+	}
+
+	userM.Password = hash
+	if err := b.store.User().Update(ctx, userM); err != nil {
+		slog.WarnContext(ctx, "Failed to update rehashed password", "userID", userM.UserID, "err", err)
+		return
+	}
+
+	slog.InfoContext(ctx, "Rehashed password with current hash configuration", "userID", userM.UserID)
+}
+
+// completeLogin 在用户通过第一步认证（密码或外部账号）后完成登录：
+// 被封禁的用户不允许登录，开启了两步验证的用户返回挑战 token，否则清除失败记录并签发 token.
+func (b *userBiz) completeLogin(ctx context.Context, userM *model.User) (*apiv1.LoginResponse, error) {
+	// 被管理员封禁的用户不允许登录
+	if userM.Status == known.UserStatusSuspended {
+		return nil, errorsx.ErrUserSuspended
+	}
+
+	// 开启了两步验证的用户，需要使用挑战 token 和验证码完成登录. 完成两步验证前不清除失败记录
+	if _, enabled, err := b.totpEnabled(ctx, userM.UserID); err != nil {
+		return nil, err
+	} else if enabled {
+		challenge, challengeExpireAt, err := token.SignChallenge(userM.UserID, token.PurposeTwoFactor, 0, "")
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to sign challenge token", "err", err)
+			return nil, errorsx.ErrSignToken
+		}
+		return &apiv1.LoginResponse{TwoFactorRequired: true, ChallengeToken: challenge, ChallengeExpireAt: &challengeExpireAt}, nil
+	}
+
+	// 登录成功，清除失败记录，签发 token 并返回
+	b.lockout.Reset(userM.UserID)
+	return b.issueTokens(ctx, userM)
+}
+
+// issueTokens 为登录成功的用户签发 token. 每次登录都会创建一个新的登录会话，
+// 会话 ID 同时作为 refresh token 的令牌族 ID，吊销会话时一并吊销该令牌族.
+func (b *userBiz) issueTokens(ctx context.Context, userM *model.User) (*apiv1.LoginResponse, error) {
+	now := time.Now()
+	sessionM := &model.Session{
+		UserID:       userM.UserID,
+		UserAgent:    contextx.UserAgent(ctx),
+		ClientIP:     contextx.ClientIP(ctx),
+		LastActiveAt: now,
+		ExpiresAt:    now.Add(token.RefreshExpiration()),
+	}
+	if err := b.store.Session().Create(ctx, sessionM); err != nil {
+		return nil, err
+	}
+
+	tokenStr, expireAt, err := token.Sign(token.Claims{Identity: userM.UserID, Username: userM.Username, Role: userM.Role, SessionID: sessionM.SessionID})
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to sign token", "err", err)
 		return nil, errorsx.ErrSignToken
 	}
 
-	return &apiv1.LoginResponse{Token: tokenStr, ExpireAt: expireAt}, nil
+	refreshToken, refreshExpireAt, err := b.issueRefreshToken(ctx, userM.UserID, sessionM.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.LoginResponse{
+		Token:           tokenStr,
+		ExpireAt:        expireAt,
+		RefreshToken:    refreshToken,
+		RefreshExpireAt: refreshExpireAt,
+	}, nil
 }
 
-// RefreshToken 用于刷新用户的身份验证令牌.
-// 当用户的令牌即将过期时，可以调用此方法生成一个新的令牌.
+// RefreshToken 使用 refresh token 换取新的身份验证令牌.
+// 每个 refresh token 只能使用一次，使用后会轮换出同一族的新 refresh token.
+// 如果一个已经轮换过的 refresh token 被再次使用，说明它可能已经泄露，此时会吊销整个令牌族及其登录会话.
 func (b *userBiz) RefreshToken(ctx context.Context, rq *apiv1.RefreshTokenRequest) (*apiv1.RefreshTokenResponse, error) {
-	// 如果匹配成功，说明登录成功，签发 token 并返回
-	tokenStr, expireAt, err := token.Sign(contextx.UserID(ctx))
+	ctx, span := tracing.Start(ctx, "UserBiz.RefreshToken")
+	defer span.End()
+
+	tokenM, err := b.store.RefreshToken().Get(ctx, where.F("tokenHash", token.HashOpaque(rq.RefreshToken)))
 	if err != nil {
-		return nil, errorsx.ErrSignToken.WithMessage(err.Error())
+		return nil, err
+	}
+
+	if tokenM.RevokedAt != nil || time.Now().After(tokenM.ExpiresAt) {
+		return nil, errorsx.ErrRefreshTokenInvalid
 	}
-	return &apiv1.RefreshTokenResponse{Token: tokenStr, ExpireAt: expireAt}, nil
+
+	// 原子地将令牌标记为已使用，标记失败说明令牌已经被使用过（重放）
+	ok, err := b.store.RefreshToken().MarkUsed(ctx, tokenM.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// 令牌族 ID 即登录会话 ID，同时吊销该会话，使已经使用泄露的 refresh token 换取的 token 随即失效
+		slog.WarnContext(ctx, "Refresh token reuse detected, revoking token family and session", "userID", tokenM.UserID, "familyID", tokenM.FamilyID)
+		if err := b.store.RefreshToken().Revoke(ctx, where.F("familyID", tokenM.FamilyID)); err != nil {
+			return nil, err
+		}
+		if err := b.revoker.RevokeSession(ctx, tokenM.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, errorsx.ErrRefreshTokenReused
+	}
+
+	// 被封禁的用户不允许继续刷新令牌
+	userM, err := b.store.User().Get(ctx, where.F("userID", tokenM.UserID))
+	if errors.Is(err, errorsx.ErrUserNotFound) {
+		return nil, errorsx.ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if userM.Status == known.UserStatusSuspended {
+		return nil, errorsx.ErrUserSuspended
+	}
+
+	// 使用用户当前的角色签发新 token，角色变更在下一次刷新时生效. 令牌族 ID 即登录会话 ID
+	tokenStr, expireAt, err := token.Sign(token.Claims{Identity: tokenM.UserID, Username: userM.Username, Role: userM.Role, SessionID: tokenM.FamilyID})
+	if err != nil {
+		return nil, errorsx.ErrSignToken.WithMessage("%s", err.Error())
+	}
+
+	refreshToken, refreshExpireAt, err := b.issueRefreshToken(ctx, tokenM.UserID, tokenM.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 刷新令牌视为会话的一次活动，更新失败不影响本次刷新
+	if err := b.store.Session().Touch(ctx, tokenM.FamilyID, contextx.ClientIP(ctx), time.Now(), refreshExpireAt); err != nil {
+		slog.WarnContext(ctx, "Failed to touch session", "sessionID", tokenM.FamilyID, "err", err)
+	}
+
+	return &apiv1.RefreshTokenResponse{
+		Token:           tokenStr,
+		ExpireAt:        expireAt,
+		RefreshToken:    refreshToken,
+		RefreshExpireAt: refreshExpireAt,
+	}, nil
+}
+
+// issueRefreshToken 签发一个属于 familyID 令牌族的 refresh token，数据库中只保存其哈希值
+func (b *userBiz) issueRefreshToken(ctx context.Context, userID string, familyID string) (string, time.Time, error) {
+	plain, hash, expireAt, err := token.SignRefresh()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to sign refresh token", "err", err)
+		return "", time.Time{}, errorsx.ErrSignToken
+	}
+
+	tokenM := &model.RefreshToken{UserID: userID, FamilyID: familyID, TokenHash: hash, ExpiresAt: expireAt}
+	if err := b.store.RefreshToken().Create(ctx, tokenM); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return plain, expireAt, nil
 }
 
 // ChangePassword 实现 UserBiz 接口中的 ChangePassword 方法.
+// 用户修改自己的密码时需要校验旧密码，管理员重置其他用户的密码时不需要.
 func (b *userBiz) ChangePassword(ctx context.Context, rq *apiv1.ChangePasswordRequest) (*apiv1.ChangePasswordResponse, error) {
-	userM, err := b.store.User().Get(ctx, where.F("userID", contextx.UserID(ctx)))
+	ctx, span := tracing.Start(ctx, "UserBiz.ChangePassword")
+	defer span.End()
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", rq.UserID))
 	if err != nil {
 		return nil, err
 	}
 
-	if err := auth.Compare(userM.Password, rq.OldPassword); err != nil {
-		slog.ErrorContext(ctx, "Failed to compare password", "err", err)
-		return nil, errorsx.ErrPasswordInvalid
+	if rq.UserID == contextx.UserID(ctx) {
+		if err := auth.Compare(userM.Password, rq.OldPassword); err != nil {
+			slog.ErrorContext(ctx, "Failed to compare password", "err", err)
+			return nil, errorsx.ErrPasswordInvalid
+		}
 	}
 
-	userM.Password, _ = auth.Encrypt(rq.NewPassword)
-	if err := b.store.User().Update(ctx, userM); err != nil {
+	hash, err := auth.Encrypt(rq.NewPassword)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to encrypt password", "err", err)
+		return nil, errorsx.ErrInternal
+	}
+	userM.Password = hash
+	userM.Passwordless = false
+	// 修改密码后，吊销该用户所有已签发的 token 和个人访问令牌，需要重新登录.
+	// 修改密码和吊销凭证在同一个事务中完成，避免密码已修改但旧凭证仍然可用
+	err = b.store.TX(ctx, func(ctx context.Context) error {
+		if err := b.store.User().Update(ctx, userM); err != nil {
+			return err
+		}
+		return b.revokeCredentials(ctx, userM.UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.revoker.RevokeUser(ctx, userM.UserID); err != nil {
 		return nil, err
 	}
 
@@ -107,6 +339,9 @@ func (b *userBiz) ChangePassword(ctx context.Context, rq *apiv1.ChangePasswordRe
 
 // Create 实现 UserBiz 接口中的 Create 方法
 func (b *userBiz) Create(ctx context.Context, rq *apiv1.CreateUserRequest) (*apiv1.CreateUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.Create")
+	defer span.End()
+
 	var userM model.User
 	_ = copier.Copy(&userM, rq)
 
@@ -114,12 +349,20 @@ func (b *userBiz) Create(ctx context.Context, rq *apiv1.CreateUserRequest) (*api
 		return nil, err
 	}
 
+	// 发送验证邮件，发送失败不影响注册，用户可以稍后重新发送
+	if err := b.sendVerificationEmail(ctx, &userM); err != nil {
+		slog.ErrorContext(ctx, "Failed to send verification email", "userID", userM.UserID, "err", err)
+	}
+
 	return &apiv1.CreateUserResponse{UserID: userM.UserID}, nil
 }
 
 // Update 实现 UserBiz 接口中的 Update 方法
 func (b *userBiz) Update(ctx context.Context, rq *apiv1.UpdateUserRequest) (*apiv1.UpdateUserResponse, error) {
-	userM, err := b.store.User().Get(ctx, where.F("userID", contextx.UserID(ctx)))
+	ctx, span := tracing.Start(ctx, "UserBiz.Update")
+	defer span.End()
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", rq.UserID))
 	if err != nil {
 		return nil, err
 	}
@@ -127,8 +370,11 @@ func (b *userBiz) Update(ctx context.Context, rq *apiv1.UpdateUserRequest) (*api
 	if rq.Username != nil {
 		userM.Username = *rq.Username
 	}
-	if rq.Email != nil {
+	// 修改邮箱后需要重新验证
+	emailChanged := rq.Email != nil && *rq.Email != userM.Email
+	if emailChanged {
 		userM.Email = *rq.Email
+		userM.EmailVerifiedAt = nil
 	}
 	if rq.Nickname != nil {
 		userM.Nickname = *rq.Nickname
@@ -141,12 +387,63 @@ func (b *userBiz) Update(ctx context.Context, rq *apiv1.UpdateUserRequest) (*api
 		return nil, err
 	}
 
+	if emailChanged {
+		if err := b.sendVerificationEmail(ctx, userM); err != nil {
+			slog.ErrorContext(ctx, "Failed to send verification email", "userID", userM.UserID, "err", err)
+		}
+	}
+
 	return &apiv1.UpdateUserResponse{}, nil
 }
 
-// Delete 实现 UserBiz 接口中的 Delete 方法
+// Delete 实现 UserBiz 接口中的 Delete 方法. 不允许删除最后一个管理员
 func (b *userBiz) Delete(ctx context.Context, rq *apiv1.DeleteUserRequest) (*apiv1.DeleteUserResponse, error) {
-	if err := b.store.User().Delete(ctx, where.F("userID", contextx.UserID(ctx))); err != nil {
+	ctx, span := tracing.Start(ctx, "UserBiz.Delete")
+	defer span.End()
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", rq.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	// 删除账号及清理关联数据在同一个事务中完成，避免删除账号后仍残留可用的凭证
+	err = b.store.TX(ctx, func(ctx context.Context) error {
+		// 不允许删除最后一个管理员（包括管理员删除自己）
+		if userM.Role == known.RoleAdmin {
+			if err := b.ensureOtherAdmins(ctx); err != nil {
+				return err
+			}
+		}
+
+		if err := b.store.User().Delete(ctx, where.F("userID", rq.UserID)); err != nil {
+			return err
+		}
+
+		// 删除账号后，吊销该用户所有的 refresh token 和个人访问令牌，已签发的 token 在事务提交后吊销
+		if err := b.revokeCredentials(ctx, rq.UserID); err != nil {
+			return err
+		}
+
+		if err := b.store.Session().Delete(ctx, where.F("userID", rq.UserID)); err != nil {
+			return err
+		}
+
+		// 解除所有外部账号关联
+		if err := b.store.ExternalIdentity().Delete(ctx, where.F("userID", rq.UserID)); err != nil {
+			return err
+		}
+
+		// 清理两步验证数据
+		if err := b.store.UserTOTP().Delete(ctx, where.F("userID", rq.UserID)); err != nil {
+			return err
+		}
+		return b.store.RecoveryCode().Delete(ctx, where.F("userID", rq.UserID))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.revoker.RevokeUser(ctx, rq.UserID); err != nil {
 		return nil, err
 	}
 
@@ -155,7 +452,10 @@ func (b *userBiz) Delete(ctx context.Context, rq *apiv1.DeleteUserRequest) (*api
 
 // Get 实现 UserBiz 接口中的 Get 方法
 func (b *userBiz) Get(ctx context.Context, rq *apiv1.GetUserRequest) (*apiv1.GetUserResponse, error) {
-	userM, err := b.store.User().Get(ctx, where.F("userID", contextx.UserID(ctx)))
+	ctx, span := tracing.Start(ctx, "UserBiz.Get")
+	defer span.End()
+
+	userM, err := b.store.User().Get(ctx, where.F("userID", rq.UserID))
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +465,9 @@ func (b *userBiz) Get(ctx context.Context, rq *apiv1.GetUserRequest) (*apiv1.Get
 
 // List 实现 UserBiz 接口中的 List 方法
 func (b *userBiz) List(ctx context.Context, rq *apiv1.ListUserRequest) (*apiv1.ListUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserBiz.List")
+	defer span.End()
+
 	whr := where.P(int(rq.Offset), int(rq.Limit))
 	count, userList, err := b.store.User().List(ctx, whr)
 	if err != nil {
@@ -184,7 +487,7 @@ func (b *userBiz) List(ctx context.Context, rq *apiv1.ListUserRequest) (*apiv1.L
 			case <-ctx.Done():
 				return nil
 			default:
-				postCount, _, err := b.store.Post().List(ctx, where.F("userID", contextx.UserID(ctx)))
+				postCount, _, err := b.store.Post().List(ctx, where.F("userID", user.UserID))
 				if err != nil {
 					return err
 				}
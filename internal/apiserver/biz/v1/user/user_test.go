@@ -0,0 +1,113 @@
+package user
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/lockout"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/revoker"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTokenKey 是测试中签发和解析 token 使用的密钥
+const testTokenKey = "fastgo-test-token-key"
+
+// newTestBiz 创建使用 s 作为存储的 userBiz
+func newTestBiz(s *storetest.Store) *userBiz {
+	return &userBiz{
+		store:   s,
+		revoker: revoker.New(s.RevokedToken(), s.Session()),
+		lockout: lockout.New(genericoptions.NewLockoutOptions()),
+	}
+}
+
+func TestMain(m *testing.M) {
+	token.Init(testTokenKey, "", 0, 0)
+	os.Exit(m.Run())
+}
+
+func TestRefreshToken(t *testing.T) {
+	s := storetest.New(&model.User{UserID: "user-000001", Username: "alice", Role: known.RoleUser})
+	b := newTestBiz(s)
+	ctx := context.Background()
+
+	login, err := b.issueTokens(ctx, s.Users["user-000001"])
+	require.NoError(t, err)
+
+	// 刷新后轮换出同一族的新 refresh token，新 token 属于同一个登录会话
+	refreshed, err := b.RefreshToken(ctx, &apiv1.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	require.NoError(t, err)
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+	require.Len(t, s.RefreshTokens, 2)
+	assert.Equal(t, s.RefreshTokens[0].FamilyID, s.RefreshTokens[1].FamilyID)
+	claims, err := token.Parse(refreshed.Token, testTokenKey)
+	require.NoError(t, err)
+	assert.Equal(t, s.Sessions[0].SessionID, claims.SessionID)
+
+	// 轮换后的 refresh token 仍然可以继续使用
+	refreshed, err = b.RefreshToken(ctx, &apiv1.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	require.NoError(t, err)
+	claims, err = token.Parse(refreshed.Token, testTokenKey)
+	require.NoError(t, err)
+	revoked, err := b.revoker.IsRevoked(ctx, claims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// 重放已经轮换过的 refresh token 时，吊销整个令牌族及其登录会话
+	_, err = b.RefreshToken(ctx, &apiv1.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assert.ErrorIs(t, err, errorsx.ErrRefreshTokenReused)
+	for _, tokenM := range s.RefreshTokens {
+		assert.NotNil(t, tokenM.RevokedAt)
+	}
+	assert.NotNil(t, s.Sessions[0].RevokedAt)
+
+	// 令牌族中最新的 refresh token 也不能再使用，已经签发的 token 随会话一起失效
+	_, err = b.RefreshToken(ctx, &apiv1.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	assert.ErrorIs(t, err, errorsx.ErrRefreshTokenInvalid)
+	revoked, err = b.revoker.IsRevoked(ctx, claims)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// 其他登录会话不受影响
+	other, err := b.issueTokens(ctx, s.Users["user-000001"])
+	require.NoError(t, err)
+	_, err = b.RefreshToken(ctx, &apiv1.RefreshTokenRequest{RefreshToken: other.RefreshToken})
+	assert.NoError(t, err)
+}
+
+func TestRefreshTokenSuspendedUser(t *testing.T) {
+	s := storetest.New(&model.User{UserID: "user-000001", Username: "alice", Role: known.RoleUser})
+	b := newTestBiz(s)
+	ctx := context.Background()
+
+	login, err := b.issueTokens(ctx, s.Users["user-000001"])
+	require.NoError(t, err)
+
+	// 被封禁的用户不能继续刷新令牌
+	s.Users["user-000001"].Status = known.UserStatusSuspended
+	_, err = b.RefreshToken(ctx, &apiv1.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assert.ErrorIs(t, err, errorsx.ErrUserSuspended)
+}
+
+func TestLoginUserLookupFailed(t *testing.T) {
+	s := storetest.New(&model.User{UserID: "user-000001", Username: "alice", Role: known.RoleUser})
+	s.Errs["User.Get"] = errorsx.ErrDBRead
+	b := newTestBiz(s)
+	ctx := context.Background()
+
+	// 查询用户失败时直接返回错误，不当作用户不存在处理，也不计入登录失败次数
+	for range genericoptions.NewLockoutOptions().MaxAttempts + 1 {
+		_, err := b.login(ctx, &apiv1.LoginRequest{Username: "alice", Password: "fastgo1234"})
+		assert.ErrorIs(t, err, errorsx.ErrDBRead)
+	}
+	assert.Zero(t, b.lockout.Check(unknownAccount("alice"), ""))
+}
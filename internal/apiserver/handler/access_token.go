@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAccessToken 创建个人访问令牌
+func (h *Handler) CreateAccessToken(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Create access token function called")
+
+	var rq v1.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateCreateAccessTokenRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.AccessTokenV1().Create(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// DeleteAccessToken 吊销个人访问令牌
+func (h *Handler) DeleteAccessToken(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Delete access token function called")
+
+	var rq v1.DeleteAccessTokenRequest
+	if err := c.ShouldBindUri(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateDeleteAccessTokenRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.AccessTokenV1().Delete(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ListAccessToken 查询个人访问令牌列表
+func (h *Handler) ListAccessToken(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "List access token function called")
+
+	var rq v1.ListAccessTokenRequest
+	if err := c.ShouldBindQuery(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateListAccessTokenRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.AccessTokenV1().List(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// AddCollaborator 邀请用户协作编辑博客
+func (h *Handler) AddCollaborator(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Add collaborator function called")
+
+	var rq v1.AddCollaboratorRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+	rq.PostID = c.Param("postID")
+
+	if err := h.val.ValidateAddCollaboratorRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.PostV1().AddCollaborator(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// RemoveCollaborator 移除博客协作者
+func (h *Handler) RemoveCollaborator(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Remove collaborator function called")
+
+	var rq v1.RemoveCollaboratorRequest
+	if err := c.ShouldBindUri(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateRemoveCollaboratorRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.PostV1().RemoveCollaborator(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ListCollaborator 列出博客协作者
+func (h *Handler) ListCollaborator(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "List collaborator function called")
+
+	var rq v1.ListCollaboratorRequest
+	if err := c.ShouldBindUri(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateListCollaboratorRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.PostV1().ListCollaborator(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ListSharedPost 列出其他用户共享给我的博客
+func (h *Handler) ListSharedPost(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "List shared post function called")
+
+	var rq v1.ListSharedPostRequest
+	if err := c.ShouldBindQuery(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateListSharedPostRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.PostV1().ListShared(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.SetLastModified(c, postsLastModified(resp.Posts))
+	core.WriteResponse(c, resp, nil)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyEmail 使用验证邮件中的 token 验证用户邮箱
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Verify email function called")
+
+	var rq v1.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateVerifyEmailRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().VerifyEmail(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// SendVerificationEmail 重新发送验证邮件
+func (h *Handler) SendVerificationEmail(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Send verification email function called")
+
+	var rq v1.SendVerificationEmailRequest
+	if err := c.ShouldBindUri(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateSendVerificationEmailRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().SendVerificationEmail(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ForgotPassword 向用户邮箱发送重置密码的链接
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Forgot password function called")
+
+	var rq v1.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateForgotPasswordRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().ForgotPassword(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ResetPassword 使用重置密码邮件中的 token 设置新密码
+func (h *Handler) ResetPassword(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Reset password function called")
+
+	var rq v1.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateResetPasswordRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().ResetPassword(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
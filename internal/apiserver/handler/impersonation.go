@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateImpersonation 管理员模拟登录指定用户
+func (h *Handler) CreateImpersonation(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Create impersonation function called")
+
+	var rq v1.CreateImpersonationRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateCreateImpersonationRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.ImpersonationV1().Create(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// DeleteImpersonation 提前结束模拟登录
+func (h *Handler) DeleteImpersonation(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Delete impersonation function called")
+
+	var rq v1.DeleteImpersonationRequest
+	if err := c.ShouldBindUri(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateDeleteImpersonationRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.ImpersonationV1().Delete(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ListImpersonation 查询模拟登录审计记录列表
+func (h *Handler) ListImpersonation(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "List impersonation function called")
+
+	var rq v1.ListImpersonationRequest
+	if err := c.ShouldBindQuery(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateListImpersonationRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.ImpersonationV1().List(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// ListOIDCProvider 查询可用于登录的外部 OIDC 提供方列表
+func (h *Handler) ListOIDCProvider(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "List OIDC provider function called")
+
+	var rq v1.ListOIDCProviderRequest
+
+	if err := h.val.ValidateListOIDCProviderRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().ListOIDCProvider(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// AuthorizeOIDCLogin 发起使用外部账号登录，返回授权地址和状态 token
+func (h *Handler) AuthorizeOIDCLogin(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Authorize OIDC login function called")
+
+	var rq v1.AuthorizeOIDCRequest
+	if err := c.ShouldBindUri(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateAuthorizeOIDCRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().AuthorizeOIDCLogin(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// LoginOIDC 使用外部账号登录
+func (h *Handler) LoginOIDC(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Login OIDC function called")
+
+	var rq v1.OIDCLoginRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+	rq.Provider = c.Param("provider")
+
+	if err := h.val.ValidateOIDCLoginRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().LoginOIDC(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ListExternalIdentity 查询当前用户已关联的外部账号列表
+func (h *Handler) ListExternalIdentity(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "List external identity function called")
+
+	var rq v1.ListExternalIdentityRequest
+
+	if err := h.val.ValidateListExternalIdentityRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().ListExternalIdentity(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// AuthorizeExternalIdentity 发起关联外部账号，返回授权地址和状态 token
+func (h *Handler) AuthorizeExternalIdentity(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Authorize external identity function called")
+
+	var rq v1.AuthorizeOIDCRequest
+	if err := c.ShouldBindUri(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateAuthorizeOIDCRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().AuthorizeExternalIdentity(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// LinkExternalIdentity 为当前用户关联外部账号
+func (h *Handler) LinkExternalIdentity(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Link external identity function called")
+
+	var rq v1.LinkExternalIdentityRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+	rq.Provider = c.Param("provider")
+
+	if err := h.val.ValidateLinkExternalIdentityRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().LinkExternalIdentity(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// UnlinkExternalIdentity 解除关联外部账号
+func (h *Handler) UnlinkExternalIdentity(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Unlink external identity function called")
+
+	var rq v1.UnlinkExternalIdentityRequest
+	if err := c.ShouldBindUri(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateUnlinkExternalIdentityRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().UnlinkExternalIdentity(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
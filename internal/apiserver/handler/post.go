@@ -2,6 +2,7 @@ package handler
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/TobyIcetea/fastgo/internal/pkg/core"
 	"github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
@@ -11,7 +12,7 @@ import (
 
 // CreatePost 创建新博客
 func (h *Handler) CreatePost(c *gin.Context) {
-	slog.Info("Create post function called")
+	slog.InfoContext(c.Request.Context(), "Create post function called")
 
 	var rq v1.CreatePostRequest
 	if err := c.ShouldBindJSON(&rq); err != nil {
@@ -20,7 +21,7 @@ func (h *Handler) CreatePost(c *gin.Context) {
 	}
 
 	if err := h.val.ValidateCreatePostRequest(c.Request.Context(), &rq); err != nil {
-		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage(err.Error()))
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
 		return
 	}
 
@@ -35,7 +36,7 @@ func (h *Handler) CreatePost(c *gin.Context) {
 
 // UpdatePost 更新博客信息
 func (h *Handler) UpdatePost(c *gin.Context) {
-	slog.Info("Update post function called")
+	slog.InfoContext(c.Request.Context(), "Update post function called")
 
 	var rq v1.UpdatePostRequest
 	if err := c.ShouldBindJSON(&rq); err != nil {
@@ -45,7 +46,7 @@ func (h *Handler) UpdatePost(c *gin.Context) {
 	rq.PostID = c.Param("postID")
 
 	if err := h.val.ValidateUpdatePostRequest(c.Request.Context(), &rq); err != nil {
-		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage(err.Error()))
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
 		return
 	}
 
@@ -60,7 +61,7 @@ func (h *Handler) UpdatePost(c *gin.Context) {
 
 // DeletePost 删除博客
 func (h *Handler) DeletePost(c *gin.Context) {
-	slog.Info("Delete post function called")
+	slog.InfoContext(c.Request.Context(), "Delete post function called")
 
 	var rq v1.DeletePostRequest
 	if err := c.ShouldBindJSON(&rq); err != nil {
@@ -70,7 +71,7 @@ func (h *Handler) DeletePost(c *gin.Context) {
 
 	// 补全校验代码
 	if err := h.val.ValidateDeletePostRequest(c.Request.Context(), &rq); err != nil {
-		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage(err.Error()))
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
 		return
 	}
 
@@ -85,7 +86,7 @@ func (h *Handler) DeletePost(c *gin.Context) {
 
 // GetPost 获取博客信息
 func (h *Handler) GetPost(c *gin.Context) {
-	slog.Info("Get post function called")
+	slog.InfoContext(c.Request.Context(), "Get post function called")
 
 	var rq v1.GetPostRequest
 	if err := c.ShouldBindUri(&rq); err != nil {
@@ -95,7 +96,7 @@ func (h *Handler) GetPost(c *gin.Context) {
 
 	// 补全校验代码
 	if err := h.val.ValidateGetPostRequest(c.Request.Context(), &rq); err != nil {
-		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage(err.Error()))
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
 		return
 	}
 
@@ -105,12 +106,14 @@ func (h *Handler) GetPost(c *gin.Context) {
 		return
 	}
 
+	// 浏览次数变化不会更新 UpdatedAt，只带 If-Modified-Since 的客户端可能看到过期的浏览次数，带 If-None-Match 时以 ETag 为准
+	core.SetLastModified(c, resp.Post.UpdateAt)
 	core.WriteResponse(c, resp, nil)
 }
 
 // List 列出博客信息
 func (h *Handler) ListPost(c *gin.Context) {
-	slog.Info("List post function called")
+	slog.InfoContext(c.Request.Context(), "List post function called")
 
 	var rq v1.ListPostRequest
 	if err := c.ShouldBindQuery(&rq); err != nil {
@@ -120,7 +123,7 @@ func (h *Handler) ListPost(c *gin.Context) {
 
 	// 补全校验代码
 	if err := h.val.ValidateListPostRequest(c.Request.Context(), &rq); err != nil {
-		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage(err.Error()))
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
 		return
 	}
 
@@ -130,5 +133,17 @@ func (h *Handler) ListPost(c *gin.Context) {
 		return
 	}
 
+	core.SetLastModified(c, postsLastModified(resp.Posts))
 	core.WriteResponse(c, resp, nil)
 }
+
+// postsLastModified 返回博客列表中最近一次更新的时间
+func postsLastModified(posts []*v1.Post) time.Time {
+	var t time.Time
+	for _, post := range posts {
+		if post.UpdateAt.After(t) {
+			t = post.UpdateAt
+		}
+	}
+	return t
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateReport 举报博客或用户
+func (h *Handler) CreateReport(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Create report function called")
+
+	var rq v1.CreateReportRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateCreateReportRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.ReportV1().Create(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ListReport 查询审核队列
+func (h *Handler) ListReport(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "List report function called")
+
+	var rq v1.ListReportRequest
+	if err := c.ShouldBindQuery(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateListReportRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.ReportV1().List(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ModerateReport 处理举报：隐藏博客、封禁用户或驳回举报
+func (h *Handler) ModerateReport(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Moderate report function called")
+
+	var rq v1.ModerateReportRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+	rq.ReportID = c.Param("reportID")
+
+	if err := h.val.ValidateModerateReportRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.ReportV1().Moderate(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ListModerationAction 查询审核操作记录
+func (h *Handler) ListModerationAction(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "List moderation action function called")
+
+	var rq v1.ListModerationActionRequest
+	if err := c.ShouldBindQuery(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateListModerationActionRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.ReportV1().ListAction(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
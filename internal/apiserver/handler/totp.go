@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// LoginTOTP 使用挑战 token 和验证码完成两步验证登录
+func (h *Handler) LoginTOTP(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Login totp function called")
+
+	var rq v1.LoginTOTPRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateLoginTOTPRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().LoginTOTP(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// EnrollTOTP 开始设置 TOTP 两步验证，返回密钥及二维码
+func (h *Handler) EnrollTOTP(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Enroll totp function called")
+
+	var rq v1.EnrollTOTPRequest
+
+	if err := h.val.ValidateEnrollTOTPRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().EnrollTOTP(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// ConfirmTOTP 提交验证码确认开启 TOTP 两步验证，返回恢复码
+func (h *Handler) ConfirmTOTP(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Confirm totp function called")
+
+	var rq v1.ConfirmTOTPRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateConfirmTOTPRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().ConfirmTOTP(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// DisableTOTP 关闭 TOTP 两步验证
+func (h *Handler) DisableTOTP(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Disable totp function called")
+
+	var rq v1.DisableTOTPRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateDisableTOTPRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().DisableTOTP(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// RegenerateRecoveryCodes 重新生成两步验证恢复码
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Regenerate recovery codes function called")
+
+	var rq v1.RegenerateRecoveryCodesRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateRegenerateRecoveryCodesRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrInvalidArgument.WithMessage("%s", err.Error()))
+		return
+	}
+
+	resp, err := h.biz.UserV1().RegenerateRecoveryCodes(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
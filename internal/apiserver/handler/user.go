@@ -2,6 +2,7 @@ package handler
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/TobyIcetea/fastgo/internal/pkg/core"
 	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
@@ -11,7 +12,7 @@ import (
 
 // Login 用户登录并返回 JWT Token.
 func (h *Handler) Login(c *gin.Context) {
-	slog.Info("Login function called")
+	slog.InfoContext(c.Request.Context(), "Login function called")
 
 	var rq v1.LoginRequest
 	if err := c.ShouldBindJSON(&rq); err != nil {
@@ -32,7 +33,7 @@ func (h *Handler) Login(c *gin.Context) {
 
 // RefreshToken 刷新 JWT Token.
 func (h *Handler) RefreshToken(c *gin.Context) {
-	slog.Info("Refresh token function called")
+	slog.InfoContext(c.Request.Context(), "Refresh token function called")
 
 	var rq v1.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&rq); err != nil {
@@ -40,7 +41,10 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// 小作业：请你自行补全校验代码
+	if err := h.val.ValidateRefreshTokenRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
 
 	resp, err := h.biz.UserV1().RefreshToken(c.Request.Context(), &rq)
 	if err != nil {
@@ -51,17 +55,58 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 	core.WriteResponse(c, resp, nil)
 }
 
+// Logout 注销当前登录，吊销当前使用的 JWT Token.
+func (h *Handler) Logout(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Logout function called")
+
+	var rq v1.LogoutRequest
+	// 请求体是可选的
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&rq); err != nil {
+			core.WriteResponse(c, nil, errorsx.ErrBind)
+			return
+		}
+	}
+
+	resp, err := h.biz.UserV1().Logout(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// LogoutAll 注销所有登录，吊销当前用户所有已签发的 JWT Token.
+func (h *Handler) LogoutAll(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Logout all function called")
+
+	var rq v1.LogoutAllRequest
+
+	resp, err := h.biz.UserV1().LogoutAll(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
 // ChangeUserPassword 修改用户密码.
 func (h *Handler) ChangePassword(c *gin.Context) {
-	slog.Info("Change password function called")
+	slog.InfoContext(c.Request.Context(), "Change password function called")
 
 	var rq v1.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&rq); err != nil {
 		core.WriteResponse(c, nil, errorsx.ErrBind)
 		return
 	}
+	rq.UserID = c.Param("userID")
 
-	// 小作业：请你自行补全校验代码
+	if err := h.val.ValidateChangePasswordRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
 
 	resp, err := h.biz.UserV1().ChangePassword(c.Request.Context(), &rq)
 	if err != nil {
@@ -74,7 +119,7 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 
 // CreateUser 创建新用户
 func (h *Handler) CreateUser(c *gin.Context) {
-	slog.Info("Create user function called")
+	slog.InfoContext(c.Request.Context(), "Create user function called")
 
 	var rq v1.CreateUserRequest
 	if err := c.ShouldBindJSON(&rq); err != nil {
@@ -98,13 +143,14 @@ func (h *Handler) CreateUser(c *gin.Context) {
 
 // UpdateUser 更新用户信息
 func (h *Handler) UpdateUser(c *gin.Context) {
-	slog.Info("Update user function called")
+	slog.InfoContext(c.Request.Context(), "Update user function called")
 
 	var rq v1.UpdateUserRequest
 	if err := c.ShouldBindJSON(&rq); err != nil {
 		core.WriteResponse(c, nil, errorsx.ErrBind)
 		return
 	}
+	rq.UserID = c.Param("userID")
 
 	if err := h.val.ValidateUpdateUserRequest(c.Request.Context(), &rq); err != nil {
 		core.WriteResponse(c, nil, err)
@@ -122,10 +168,10 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 
 // DeleteUser 删除用户
 func (h *Handler) DeleteUser(c *gin.Context) {
-	slog.Info("Delete user function called")
+	slog.InfoContext(c.Request.Context(), "Delete user function called")
 
 	var rq v1.DeleteUserRequest
-	if err := c.ShouldBindJSON(&rq); err != nil {
+	if err := c.ShouldBindUri(&rq); err != nil {
 		core.WriteResponse(c, nil, errorsx.ErrBind)
 		return
 	}
@@ -147,10 +193,10 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 
 // GetUser 获取用户信息
 func (h *Handler) GetUser(c *gin.Context) {
-	slog.Info("Get user function called")
+	slog.InfoContext(c.Request.Context(), "Get user function called")
 
 	var rq v1.GetUserRequest
-	if err := c.ShouldBindJSON(&rq); err != nil {
+	if err := c.ShouldBindUri(&rq); err != nil {
 		core.WriteResponse(c, nil, errorsx.ErrBind)
 		return
 	}
@@ -167,15 +213,17 @@ func (h *Handler) GetUser(c *gin.Context) {
 		return
 	}
 
+	// 用户信息的所有字段都随 UpdatedAt 更新，可以使用 Last-Modified
+	core.SetLastModified(c, resp.User.UpdateAt)
 	core.WriteResponse(c, resp, nil)
 }
 
 // ListUser 列出用户信息
 func (h *Handler) ListUser(c *gin.Context) {
-	slog.Info("List user function called")
+	slog.InfoContext(c.Request.Context(), "List user function called")
 
 	var rq v1.ListUserRequest
-	if err := c.ShouldBindJSON(&rq); err != nil {
+	if err := c.ShouldBindQuery(&rq); err != nil {
 		core.WriteResponse(c, nil, errorsx.ErrBind)
 		return
 	}
@@ -192,5 +240,66 @@ func (h *Handler) ListUser(c *gin.Context) {
 		return
 	}
 
+	core.SetLastModified(c, usersLastModified(resp.Users))
+	core.WriteResponse(c, resp, nil)
+}
+
+// usersLastModified 返回用户列表中最近一次更新的时间
+func usersLastModified(users []*v1.User) time.Time {
+	var t time.Time
+	for _, user := range users {
+		if user.UpdateAt.After(t) {
+			t = user.UpdateAt
+		}
+	}
+	return t
+}
+
+// UpdateUserRole 修改用户角色，只有管理员可以调用
+func (h *Handler) UpdateUserRole(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Update user role function called")
+
+	var rq v1.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+	rq.UserID = c.Param("userID")
+
+	if err := h.val.ValidateUpdateUserRoleRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	resp, err := h.biz.UserV1().UpdateRole(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	core.WriteResponse(c, resp, nil)
+}
+
+// UnlockUser 解除因登录失败次数过多导致的账号锁定，只有管理员可以调用
+func (h *Handler) UnlockUser(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Unlock user function called")
+
+	var rq v1.UnlockUserRequest
+	if err := c.ShouldBindUri(&rq); err != nil {
+		core.WriteResponse(c, nil, errorsx.ErrBind)
+		return
+	}
+
+	if err := h.val.ValidateUnlockUserRequest(c.Request.Context(), &rq); err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
+	resp, err := h.biz.UserV1().Unlock(c.Request.Context(), &rq)
+	if err != nil {
+		core.WriteResponse(c, nil, err)
+		return
+	}
+
 	core.WriteResponse(c, resp, nil)
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
 	"github.com/TobyIcetea/fastgo/internal/pkg/rid"
 	"github.com/TobyIcetea/fastgo/pkg/auth"
 	"gorm.io/gorm"
@@ -22,6 +23,16 @@ func (m *User) BeforeCreate(tx *gorm.DB) error {
 		return err
 	}
 
+	// 新用户默认为普通用户
+	if m.Role == "" {
+		m.Role = known.RoleUser
+	}
+
+	// 新用户默认为正常状态
+	if m.Status == "" {
+		m.Status = known.UserStatusActive
+	}
+
 	return nil
 }
 
@@ -31,3 +42,31 @@ func (m *User) AfterCreate(tx *gorm.DB) error {
 
 	return tx.Save(m).Error
 }
+
+// AfterCreate 在创建数据库记录之后生成 reportID
+func (m *Report) AfterCreate(tx *gorm.DB) error {
+	m.ReportID = rid.ReportID.New(uint64(m.ID))
+
+	return tx.Save(m).Error
+}
+
+// AfterCreate 在创建数据库记录之后生成 tokenID
+func (m *AccessToken) AfterCreate(tx *gorm.DB) error {
+	m.TokenID = rid.AccessTokenID.New(uint64(m.ID))
+
+	return tx.Save(m).Error
+}
+
+// AfterCreate 在创建数据库记录之后生成 sessionID
+func (m *Session) AfterCreate(tx *gorm.DB) error {
+	m.SessionID = rid.SessionID.New(uint64(m.ID))
+
+	return tx.Save(m).Error
+}
+
+// AfterCreate 在创建数据库记录之后生成 impersonationID
+func (m *Impersonation) AfterCreate(tx *gorm.DB) error {
+	m.ImpersonationID = rid.ImpersonationID.New(uint64(m.ID))
+
+	return tx.Save(m).Error
+}
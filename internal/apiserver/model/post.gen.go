@@ -17,6 +17,7 @@ type Post struct {
 	PostID    string    `gorm:"column:postID;not null;comment:博文唯一 ID" json:"postID"`                                    // 博文唯一 ID
 	Title     string    `gorm:"column:title;not null;comment:博文标题" json:"title"`                                         // 博文标题
 	Content   string    `gorm:"column:content;not null;comment:博文内容" json:"content"`                                     // 博文内容
+	Hidden    bool      `gorm:"column:hidden;not null;default:0;comment:博文是否被管理员隐藏" json:"hidden"`                       // 博文是否被管理员隐藏
 	CreatedAt time.Time `gorm:"column:createdAt;not null;default:current_timestamp();comment:博文创建时间" json:"createdAt"`   // 博文创建时间
 	UpdatedAt time.Time `gorm:"column:updatedAt;not null;default:current_timestamp();comment:博文最后修改时间" json:"updatedAt"` // 博文最后修改时间
 }
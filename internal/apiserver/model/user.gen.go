@@ -12,15 +12,19 @@ const TableNameUser = "user"
 
 // User 用户表
 type User struct {
-	ID        int64     `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
-	UserID    string    `gorm:"column:userID;not null;comment:用户唯一 ID" json:"userID"`                                    // 用户唯一 ID
-	Username  string    `gorm:"column:username;not null;comment:用户名（唯一）" json:"username"`                                // 用户名（唯一）
-	Password  string    `gorm:"column:password;not null;comment:用户密码（加密后）" json:"password"`                              // 用户密码（加密后）
-	Nickname  string    `gorm:"column:nickname;not null;comment:用户昵称" json:"nickname"`                                   // 用户昵称
-	Email     string    `gorm:"column:email;not null;comment:用户电子邮箱地址" json:"email"`                                     // 用户电子邮箱地址
-	Phone     string    `gorm:"column:phone;not null;comment:用户手机号" json:"phone"`                                        // 用户手机号
-	CreatedAt time.Time `gorm:"column:createdAt;not null;default:current_timestamp();comment:用户创建时间" json:"createdAt"`   // 用户创建时间
-	UpdatedAt time.Time `gorm:"column:updatedAt;not null;default:current_timestamp();comment:用户最后修改时间" json:"updatedAt"` // 用户最后修改时间
+	ID              int64      `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
+	UserID          string     `gorm:"column:userID;not null;comment:用户唯一 ID" json:"userID"`                                                          // 用户唯一 ID
+	Username        string     `gorm:"column:username;not null;comment:用户名（唯一）" json:"username"`                                                      // 用户名（唯一）
+	Password        string     `gorm:"column:password;not null;comment:用户密码（加密后）" json:"password"`                                                    // 用户密码（加密后）
+	Nickname        string     `gorm:"column:nickname;not null;comment:用户昵称" json:"nickname"`                                                         // 用户昵称
+	Email           string     `gorm:"column:email;not null;comment:用户电子邮箱地址" json:"email"`                                                           // 用户电子邮箱地址
+	Phone           string     `gorm:"column:phone;not null;comment:用户手机号" json:"phone"`                                                              // 用户手机号
+	Role            string     `gorm:"column:role;not null;default:user;comment:用户角色，可选值：user、admin" json:"role"`                                     // 用户角色，可选值：user、admin
+	EmailVerifiedAt *time.Time `gorm:"column:emailVerifiedAt;comment:邮箱验证时间，为空表示邮箱未验证" json:"emailVerifiedAt"`                                        // 邮箱验证时间，为空表示邮箱未验证
+	Status          string     `gorm:"column:status;not null;default:active;comment:用户状态，可选值：active、suspended" json:"status"`                         // 用户状态，可选值：active、suspended
+	Passwordless    bool       `gorm:"column:passwordless;not null;default:false;comment:用户是否没有可用的密码（例如通过外部账号自动创建），设置密码后为 false" json:"passwordless"` // 用户是否没有可用的密码（例如通过外部账号自动创建），设置密码后为 false
+	CreatedAt       time.Time  `gorm:"column:createdAt;not null;default:current_timestamp();comment:用户创建时间" json:"createdAt"`                         // 用户创建时间
+	UpdatedAt       time.Time  `gorm:"column:updatedAt;not null;default:current_timestamp();comment:用户最后修改时间" json:"updatedAt"`                       // 用户最后修改时间
 }
 
 // TableName User's table name
@@ -0,0 +1,52 @@
+package contentfilter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Filter 使用关键词和正则表达式检查文本中是否包含敏感内容
+type Filter struct {
+	keywords []string
+	patterns []*regexp.Regexp
+}
+
+// New 创建一个 Filter 实例. 关键词匹配不区分大小写.
+func New(keywords []string, patterns []string) (*Filter, error) {
+	f := &Filter{}
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			f.keywords = append(f.keywords, strings.ToLower(keyword))
+		}
+	}
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.patterns = append(f.patterns, re)
+	}
+
+	return f, nil
+}
+
+// Match 检查 texts 中是否包含敏感内容. 命中时返回命中的规则（关键词或正则表达式）.
+func (f *Filter) Match(texts ...string) (string, bool) {
+	for _, text := range texts {
+		lower := strings.ToLower(text)
+		for _, keyword := range f.keywords {
+			if strings.Contains(lower, keyword) {
+				return keyword, true
+			}
+		}
+
+		for _, re := range f.patterns {
+			if re.MatchString(text) {
+				return re.String(), true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,32 @@
+package contentfilter_test
+
+import (
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/contentfilter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Match(t *testing.T) {
+	f, err := contentfilter.New([]string{"Spam", " "}, []string{`\d{3}-\d{4}`})
+	assert.NoError(t, err)
+
+	// 关键词匹配不区分大小写
+	rule, matched := f.Match("hello", "Buy SPAM now")
+	assert.True(t, matched)
+	assert.Equal(t, "spam", rule)
+
+	// 正则匹配
+	rule, matched = f.Match("call 555-1234")
+	assert.True(t, matched)
+	assert.Equal(t, `\d{3}-\d{4}`, rule)
+
+	// 空白关键词会被忽略
+	_, matched = f.Match("nothing to see here")
+	assert.False(t, matched)
+}
+
+func TestNew_InvalidPattern(t *testing.T) {
+	_, err := contentfilter.New(nil, []string{"("})
+	assert.Error(t, err)
+}
@@ -0,0 +1,20 @@
+package conversion
+
+import (
+	"strings"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+// AccessTokenModelToAccessTokenV1 将模型层的 AccessToken（个人访问令牌模型对象）转换为 Protobuf 层的 AccessToken（v1 个人访问令牌对象）
+func AccessTokenModelToAccessTokenV1(tokenModel *model.AccessToken) *apiv1.AccessToken {
+	return &apiv1.AccessToken{
+		TokenID:    tokenModel.TokenID,
+		Name:       tokenModel.Name,
+		Scopes:     strings.Fields(tokenModel.Scopes),
+		ExpiresAt:  tokenModel.ExpiresAt,
+		LastUsedAt: tokenModel.LastUsedAt,
+		CreateAt:   tokenModel.CreatedAt,
+	}
+}
@@ -0,0 +1,20 @@
+package conversion
+
+import (
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+// ImpersonationModelToImpersonationV1 将模型层的 Impersonation（模拟登录记录模型对象）转换为 Protobuf 层的 Impersonation（v1 模拟登录记录对象）
+func ImpersonationModelToImpersonationV1(impersonationModel *model.Impersonation) *apiv1.Impersonation {
+	return &apiv1.Impersonation{
+		ImpersonationID: impersonationModel.ImpersonationID,
+		ActorID:         impersonationModel.ActorID,
+		UserID:          impersonationModel.UserID,
+		Reason:          impersonationModel.Reason,
+		ClientIP:        impersonationModel.ClientIP,
+		ExpiresAt:       impersonationModel.ExpiresAt,
+		RevokedAt:       impersonationModel.RevokedAt,
+		CreateAt:        impersonationModel.CreatedAt,
+	}
+}
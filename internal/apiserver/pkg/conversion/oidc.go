@@ -0,0 +1,17 @@
+package conversion
+
+import (
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+// ExternalIdentityModelToExternalIdentityV1 将模型层的 ExternalIdentity（外部账号关联模型对象）转换为 Protobuf 层的 ExternalIdentity（v1 外部账号对象）
+func ExternalIdentityModelToExternalIdentityV1(identityModel *model.ExternalIdentity) *apiv1.ExternalIdentity {
+	return &apiv1.ExternalIdentity{
+		Provider:    identityModel.Provider,
+		Subject:     identityModel.Subject,
+		Email:       identityModel.Email,
+		CreateAt:    identityModel.CreatedAt,
+		LastLoginAt: identityModel.UpdatedAt,
+	}
+}
@@ -19,3 +19,11 @@ func PostV1ToPostodel(protoPost *apiv1.Post) *model.Post {
 	_ = copier.Copy(&postModel, protoPost)
 	return &postModel
 }
+
+// CollaboratorModelToCollaboratorV1 将模型层的 PostCollaborator（博客协作者模型对象）转换为 Protobuf 层的 Collaborator（v1 协作者对象）
+func CollaboratorModelToCollaboratorV1(collaboratorModel *model.PostCollaborator) *apiv1.Collaborator {
+	var protoCollaborator apiv1.Collaborator
+	_ = copier.Copy(&protoCollaborator, collaboratorModel)
+	protoCollaborator.CreateAt = collaboratorModel.CreatedAt
+	return &protoCollaborator
+}
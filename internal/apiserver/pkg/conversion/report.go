@@ -0,0 +1,24 @@
+package conversion
+
+import (
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/jinzhu/copier"
+)
+
+// ReportModelToReportV1 将模型层的 Report（举报模型对象）转换为 Protobuf 层的 Report（v1 举报对象）
+func ReportModelToReportV1(reportModel *model.Report) *apiv1.Report {
+	var protoReport apiv1.Report
+	_ = copier.Copy(&protoReport, reportModel)
+	protoReport.CreateAt = reportModel.CreatedAt
+	protoReport.UpdateAt = reportModel.UpdatedAt
+	return &protoReport
+}
+
+// ModerationActionModelToModerationActionV1 将模型层的 ModerationAction（审核操作模型对象）转换为 Protobuf 层的 ModerationAction（v1 审核操作对象）
+func ModerationActionModelToModerationActionV1(actionModel *model.ModerationAction) *apiv1.ModerationAction {
+	var protoAction apiv1.ModerationAction
+	_ = copier.Copy(&protoAction, actionModel)
+	protoAction.CreateAt = actionModel.CreatedAt
+	return &protoAction
+}
@@ -0,0 +1,18 @@
+package conversion
+
+import (
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	apiv1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+// SessionModelToSessionV1 将模型层的 Session（登录会话模型对象）转换为 Protobuf 层的 Session（v1 登录会话对象）
+func SessionModelToSessionV1(sessionModel *model.Session) *apiv1.Session {
+	return &apiv1.Session{
+		SessionID:    sessionModel.SessionID,
+		UserAgent:    sessionModel.UserAgent,
+		ClientIP:     sessionModel.ClientIP,
+		LastActiveAt: sessionModel.LastActiveAt,
+		ExpiresAt:    sessionModel.ExpiresAt,
+		CreateAt:     sessionModel.CreatedAt,
+	}
+}
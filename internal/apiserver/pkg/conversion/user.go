@@ -10,6 +10,7 @@ import (
 func UserodelToUserV1(userModel *model.User) *apiv1.User {
 	var protoUser apiv1.User
 	_ = copier.Copy(&protoUser, userModel)
+	protoUser.EmailVerified = userModel.EmailVerifiedAt != nil
 	return &protoUser
 }
 
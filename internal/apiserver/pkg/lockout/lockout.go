@@ -0,0 +1,133 @@
+package lockout
+
+import (
+	"sync"
+	"time"
+
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+)
+
+// sweepInterval 定义清理过期记录的最小间隔
+const sweepInterval = 10 * time.Minute
+
+// record 是一个账号或客户端 IP 的登录失败记录
+type record struct {
+	// failures 是连续失败次数
+	failures int
+	// lastFailure 是最近一次失败的时间
+	lastFailure time.Time
+	// lockedUntil 是锁定的截止时间
+	lockedUntil time.Time
+}
+
+// guard 按 key 统计登录失败次数，失败次数达到阈值后锁定 key，锁定时长随失败次数指数增长
+type guard struct {
+	maxAttempts int
+	records     map[string]*record
+}
+
+// Lockout 负责登录的暴力破解防护，分别按账号和客户端 IP 统计连续登录失败次数.
+// 失败记录保存在内存中，多实例部署时每个实例单独计数，服务重启后清零.
+type Lockout struct {
+	opts *genericoptions.LockoutOptions
+
+	mu        sync.Mutex
+	accounts  *guard
+	ips       *guard
+	lastSweep time.Time
+	// now 便于测试时替换当前时间
+	now func() time.Time
+}
+
+// New 创建一个 Lockout 实例
+func New(opts *genericoptions.LockoutOptions) *Lockout {
+	return &Lockout{
+		opts:     opts,
+		accounts: &guard{maxAttempts: opts.MaxAttempts, records: make(map[string]*record)},
+		ips:      &guard{maxAttempts: opts.IPMaxAttempts, records: make(map[string]*record)},
+		now:      time.Now,
+	}
+}
+
+// Check 检查账号和客户端 IP 是否被锁定，返回剩余的锁定时长，未锁定时返回 0.
+// ip 为空时只检查账号.
+func (l *Lockout) Check(account string, ip string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	return max(l.accounts.remaining(account, now), l.ips.remaining(ip, now))
+}
+
+// Fail 记录一次登录失败，返回记录后账号或客户端 IP 剩余的锁定时长，未锁定时返回 0
+func (l *Lockout) Fail(account string, ip string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	return max(l.fail(l.accounts, account, now), l.fail(l.ips, ip, now))
+}
+
+// Reset 清除账号的登录失败记录，用于登录成功或管理员解锁账号.
+// 客户端 IP 的失败记录不会因为某个账号登录成功而清除，避免攻击者用自己的账号重置计数.
+func (l *Lockout) Reset(account string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.accounts.records, account)
+}
+
+// remaining 返回 key 剩余的锁定时长
+func (g *guard) remaining(key string, now time.Time) time.Duration {
+	r, ok := g.records[key]
+	if key == "" || !ok || !now.Before(r.lockedUntil) {
+		return 0
+	}
+
+	return r.lockedUntil.Sub(now)
+}
+
+// fail 为 key 记录一次失败. 失败次数达到 maxAttempts 时开始锁定，
+// 之后每多失败一次锁定时长翻倍，最长不超过 MaxDuration.
+func (l *Lockout) fail(g *guard, key string, now time.Time) time.Duration {
+	if key == "" {
+		return 0
+	}
+
+	r, ok := g.records[key]
+	if !ok || now.Sub(r.lastFailure) >= l.opts.ResetAfter {
+		r = &record{}
+		g.records[key] = r
+	}
+	r.failures++
+	r.lastFailure = now
+
+	if excess := r.failures - g.maxAttempts; excess >= 0 {
+		d := l.opts.MaxDuration
+		// 避免位移溢出
+		if excess < 32 {
+			d = min(l.opts.Duration<<excess, l.opts.MaxDuration)
+		}
+		r.lockedUntil = now.Add(d)
+	}
+
+	return g.remaining(key, now)
+}
+
+// sweep 清理已经过了 ResetAfter 且不再锁定的记录，避免内存无限增长
+func (l *Lockout) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for _, g := range []*guard{l.accounts, l.ips} {
+		for key, r := range g.records {
+			if now.Sub(r.lastFailure) >= l.opts.ResetAfter && !now.Before(r.lockedUntil) {
+				delete(g.records, key)
+			}
+		}
+	}
+}
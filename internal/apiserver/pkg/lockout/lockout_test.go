@@ -0,0 +1,73 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLockout() (*Lockout, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New(&genericoptions.LockoutOptions{
+		MaxAttempts:   3,
+		IPMaxAttempts: 5,
+		Duration:      time.Minute,
+		MaxDuration:   5 * time.Minute,
+		ResetAfter:    time.Hour,
+	})
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLockout_ExponentialBackoff(t *testing.T) {
+	l, now := newTestLockout()
+
+	assert.Zero(t, l.Fail("user-000001", ""))
+	assert.Zero(t, l.Fail("user-000001", ""))
+	assert.Equal(t, time.Minute, l.Fail("user-000001", ""))
+	assert.Equal(t, time.Minute, l.Check("user-000001", ""))
+
+	// 锁定期间未到时，锁定时长翻倍
+	*now = now.Add(time.Minute)
+	assert.Zero(t, l.Check("user-000001", ""))
+	assert.Equal(t, 2*time.Minute, l.Fail("user-000001", ""))
+	*now = now.Add(2 * time.Minute)
+	assert.Equal(t, 4*time.Minute, l.Fail("user-000001", ""))
+	*now = now.Add(4 * time.Minute)
+	assert.Equal(t, 5*time.Minute, l.Fail("user-000001", ""), "lockout is capped at max duration")
+
+	// 管理员解锁后清零
+	l.Reset("user-000001")
+	assert.Zero(t, l.Check("user-000001", ""))
+	assert.Zero(t, l.Fail("user-000001", ""))
+}
+
+func TestLockout_ResetAfter(t *testing.T) {
+	l, now := newTestLockout()
+
+	l.Fail("user-000001", "")
+	l.Fail("user-000001", "")
+	*now = now.Add(time.Hour)
+
+	// 距离上次失败超过 ResetAfter 后重新计数
+	assert.Zero(t, l.Fail("user-000001", ""))
+	assert.Zero(t, l.Fail("user-000001", ""))
+}
+
+func TestLockout_ClientIP(t *testing.T) {
+	l, _ := newTestLockout()
+
+	// 同一 IP 尝试多个不同的账号
+	for i := range 4 {
+		assert.Zero(t, l.Fail(string(rune('a'+i)), "10.0.0.1"))
+	}
+	assert.Equal(t, time.Minute, l.Fail("e", "10.0.0.1"))
+	assert.Equal(t, time.Minute, l.Check("f", "10.0.0.1"))
+	assert.Zero(t, l.Check("f", "10.0.0.2"))
+
+	// 账号登录成功不会清除 IP 的失败记录
+	l.Reset("f")
+	assert.Equal(t, time.Minute, l.Check("f", "10.0.0.1"))
+}
@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer 将邮件以 .eml 文件的形式写入目录，用于开发和测试环境
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// 确保 FileMailer 实现了 Mailer 接口
+var _ Mailer = (*FileMailer)(nil)
+
+// NewFile 创建 FileMailer 实例
+func NewFile(dir string, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+// Send 将邮件写入 <dir>/<时间>-<收件人>.eml
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := encode(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405.000000000"), strings.NewReplacer("/", "_", "\\", "_").Replace(msg.To))
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o600)
+}
+
+// LogMailer 将邮件内容打印到日志中，用于开发环境
+type LogMailer struct{}
+
+// 确保 LogMailer 实现了 Mailer 接口
+var _ Mailer = (*LogMailer)(nil)
+
+// NewLog 创建 LogMailer 实例
+func NewLog() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send 将邮件的收件人、主题和纯文本正文打印到日志中
+func (m *LogMailer) Send(ctx context.Context, msg *Message) error {
+	slog.InfoContext(ctx, "Mail sent", "to", msg.To, "subject", msg.Subject, "text", msg.Text)
+	return nil
+}
@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Message 是一封待发送的邮件
+type Message struct {
+	// To 是收件人地址
+	To string
+	// Subject 是邮件主题
+	Subject string
+	// Text 是纯文本格式的邮件正文
+	Text string
+	// HTML 是 HTML 格式的邮件正文
+	HTML string
+}
+
+// Mailer 定义了发送邮件的方法，不同的实现可以通过 SMTP 发送邮件，或将邮件写入文件、日志以便开发调试
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Notifier 使用模板渲染邮件，并在后台异步发送.
+// 异步发送可以避免邮件服务的延迟影响接口响应，也避免通过响应时间推断邮箱是否已注册.
+type Notifier struct {
+	mailer    Mailer
+	templates *Templates
+}
+
+// NewNotifier 创建 Notifier 实例
+func NewNotifier(mailer Mailer, templates *Templates) *Notifier {
+	return &Notifier{mailer: mailer, templates: templates}
+}
+
+// Notify 使用名为 name 的模板渲染邮件，并异步发送给 to. 发送失败只记录日志
+func (n *Notifier) Notify(ctx context.Context, to string, name string, data any) {
+	msg, err := n.templates.Render(name, data)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to render mail template", "template", name, "err", err)
+		return
+	}
+	msg.To = to
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := n.mailer.Send(ctx, msg); err != nil {
+			slog.ErrorContext(ctx, "Failed to send mail", "template", name, "to", to, "err", err)
+		}
+	}()
+}
@@ -0,0 +1,155 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// sendTimeout 是 ctx 没有设置截止时间时，发送一封邮件的最长时间，避免 SMTP 服务器无响应时发送协程一直阻塞
+const sendTimeout = 30 * time.Second
+
+// SMTPMailer 通过 SMTP 服务器发送邮件
+type SMTPMailer struct {
+	host string
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// 确保 SMTPMailer 实现了 Mailer 接口
+var _ Mailer = (*SMTPMailer)(nil)
+
+// NewSMTP 创建 SMTPMailer 实例. username 为空时不进行 SMTP 认证
+func NewSMTP(host string, port int, username string, password string, from string) *SMTPMailer {
+	m := &SMTPMailer{host: host, addr: net.JoinHostPort(host, strconv.Itoa(port)), from: from}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return m
+}
+
+// Send 发送一封同时包含纯文本和 HTML 正文的邮件. 连接和整个 SMTP 会话都受 ctx 的截止时间约束，
+// ctx 没有截止时间时最多等待 sendTimeout
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := encode(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(sendTimeout)
+	}
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	// ctx 被取消时关闭连接，中断正在进行的 SMTP 会话
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	return m.send(c, msg.To, data)
+}
+
+// send 在已建立的 SMTP 会话中发送邮件，流程与 smtp.SendMail 相同
+func (m *SMTPMailer) send(c *smtp.Client, to string, data []byte) error {
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(m.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// encode 将邮件编码为 multipart/alternative 格式的 MIME 消息
+func encode(from string, msg *Message) ([]byte, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	boundary := hex.EncodeToString(b)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		if part.body == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		fmt.Fprintf(&buf, "Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		w := quotedprintable.NewWriter(&buf)
+		if _, err := w.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
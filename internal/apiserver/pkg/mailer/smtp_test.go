@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPMailerSendDeadline(t *testing.T) {
+	// 接受连接但从不发送问候语的 SMTP 服务器
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	m := NewSMTP(host, portNum, "", "", "noreply@fastgo.local")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = m.Send(ctx, &Message{To: "alice@fastgo.local", Subject: "hello", Text: "hello"})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
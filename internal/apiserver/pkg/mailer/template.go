@@ -0,0 +1,89 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+)
+
+// defaultTemplates 是内置的邮件模板
+//
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// 内置的邮件模板名称
+const (
+	// TemplateVerifyEmail 是验证邮箱的邮件模板
+	TemplateVerifyEmail = "verify_email"
+	// TemplateResetPassword 是重置密码的邮件模板
+	TemplateResetPassword = "reset_password"
+)
+
+// Templates 是邮件模板集合. 每个邮件模板 <name> 由以下 3 个文件组成：
+// <name>.subject.tmpl（主题）、<name>.txt.tmpl（纯文本正文）、<name>.html.tmpl（HTML 正文，可选）.
+type Templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// LoadTemplates 加载邮件模板. 先加载内置模板，dir 不为空时再加载 dir 中的模板，覆盖同名的内置模板.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{text: texttemplate.New(""), html: htmltemplate.New("")}
+	if err := t.parse(defaultTemplates, "templates"); err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		if err := t.parse(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// parse 解析 fsys 中 dir 目录下的所有模板
+func (t *Templates) parse(fsys fs.FS, dir string) error {
+	if matches, _ := fs.Glob(fsys, dir+"/*.html.tmpl"); len(matches) > 0 {
+		if _, err := t.html.ParseFS(fsys, matches...); err != nil {
+			return err
+		}
+	}
+
+	matches, _ := fs.Glob(fsys, dir+"/*.tmpl")
+	var textFiles []string
+	for _, match := range matches {
+		if !strings.HasSuffix(match, ".html.tmpl") {
+			textFiles = append(textFiles, match)
+		}
+	}
+	if len(textFiles) > 0 {
+		if _, err := t.text.ParseFS(fsys, textFiles...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Render 使用名为 name 的模板渲染邮件，返回的邮件不包含收件人
+func (t *Templates) Render(name string, data any) (*Message, error) {
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, name+".subject.tmpl", data); err != nil {
+		return nil, err
+	}
+	if err := t.text.ExecuteTemplate(&text, name+".txt.tmpl", data); err != nil {
+		return nil, err
+	}
+	if t.html.Lookup(name+".html.tmpl") != nil {
+		if err := t.html.ExecuteTemplate(&html, name+".html.tmpl", data); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Message{Subject: strings.TrimSpace(subject.String()), Text: text.String(), HTML: html.String()}, nil
+}
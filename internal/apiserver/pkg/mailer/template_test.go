@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	data := map[string]any{"Username": "colin", "Link": "http://example.com/verify-email?token=a&b", "ExpireAt": time.Now()}
+
+	templates, err := LoadTemplates("")
+	require.NoError(t, err)
+
+	msg, err := templates.Render(TemplateVerifyEmail, data)
+	require.NoError(t, err)
+	assert.Equal(t, "请验证你的邮箱地址", msg.Subject)
+	assert.Contains(t, msg.Text, "token=a&b")
+	assert.Contains(t, msg.HTML, "token=a&amp;b")
+
+	// 自定义模板覆盖同名的内置模板，未覆盖的内置模板仍然可用
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "verify_email.subject.tmpl"), []byte("Verify {{.Username}}"), 0o600))
+
+	templates, err = LoadTemplates(dir)
+	require.NoError(t, err)
+
+	msg, err = templates.Render(TemplateVerifyEmail, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Verify colin", msg.Subject)
+
+	_, err = templates.Render(TemplateResetPassword, data)
+	assert.NoError(t, err)
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	m := NewFile(dir, "noreply@example.com")
+
+	require.NoError(t, m.Send(context.Background(), &Message{To: "colin@example.com", Subject: "Hi", Text: "hello", HTML: "<p>hello</p>"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "To: colin@example.com")
+	assert.Contains(t, string(data), "multipart/alternative")
+}
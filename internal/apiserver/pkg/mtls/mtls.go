@@ -0,0 +1,111 @@
+// Package mtls 实现基于 TLS 客户端证书的服务调用方认证.
+// 内部服务通过 mTLS 监听地址访问 apiserver，服务端校验客户端证书后，
+// 根据配置将证书的主题或主题备用名称（SAN）映射为服务调用方，服务调用方拥有独立的权限范围.
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+)
+
+// userIDPrefix 是服务调用方用户 ID 的前缀，用于与普通用户区分
+const userIDPrefix = "service:"
+
+// scopes 是服务调用方可以拥有的权限范围. 只有博文和举报接口接受客户端证书认证，
+// 用户接口不接受，因此不包含 users:read、users:write
+var scopes = []string{known.ScopePostsRead, known.ScopePostsWrite, known.ScopeReportsWrite}
+
+// Authenticator 根据客户端证书识别服务调用方
+type Authenticator struct {
+	services []genericoptions.ServicePrincipalOptions
+}
+
+// New 创建一个 Authenticator 实例. 未开启 mTLS 时返回的实例不会识别任何证书
+func New(opts *genericoptions.MTLSOptions) (*Authenticator, error) {
+	for _, s := range opts.Services {
+		for _, scope := range s.Scopes {
+			if !slices.Contains(scopes, scope) {
+				return nil, fmt.Errorf("invalid scope %q of mtls service %q", scope, s.Name)
+			}
+		}
+	}
+
+	return &Authenticator{services: opts.Services}, nil
+}
+
+// Verify 将已通过校验的客户端证书映射为服务调用方. 证书的 Common Name、完整主题或任意 SAN
+// 与某个服务的配置匹配时即认为是该服务，按配置顺序取第一个匹配的服务.
+func (a *Authenticator) Verify(cert *x509.Certificate) (*contextx.Principal, error) {
+	names := certNames(cert)
+	for _, s := range a.services {
+		matched := slices.Contains(s.Subjects, cert.Subject.CommonName) || slices.Contains(s.Subjects, cert.Subject.String())
+		for _, name := range names {
+			matched = matched || slices.Contains(s.SANs, name)
+		}
+		if !matched {
+			continue
+		}
+
+		// 保证返回非 nil 的权限范围，nil 表示不受权限范围限制
+		scopes := slices.Clone(s.Scopes)
+		if scopes == nil {
+			scopes = []string{}
+		}
+
+		return &contextx.Principal{
+			UserID:     userIDPrefix + s.Name,
+			Username:   s.Name,
+			Roles:      []string{known.RoleService},
+			Scopes:     scopes,
+			AuthMethod: known.AuthMethodMTLS,
+			TokenID:    cert.SerialNumber.String(),
+		}, nil
+	}
+
+	return nil, errorsx.ErrClientCertUnknown
+}
+
+// certNames 返回证书中的所有主题备用名称
+func certNames(cert *x509.Certificate) []string {
+	names := slices.Clone(cert.DNSNames)
+	names = append(names, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
+
+// TLSConfig 根据配置创建 mTLS 监听使用的 TLS 配置，要求客户端提供由 CA 签发的证书
+func TLSConfig(opts *genericoptions.MTLSOptions) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mtls server certificate: %w", err)
+	}
+
+	data, err := os.ReadFile(opts.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mtls client ca file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no valid certificate found in mtls client ca file %s", opts.ClientCAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package mtls
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/url"
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	a, err := New(&genericoptions.MTLSOptions{Services: []genericoptions.ServicePrincipalOptions{
+		{Name: "billing", Subjects: []string{"CN=billing,O=fastgo"}, Scopes: []string{known.ScopePostsRead}},
+		{Name: "search", SANs: []string{"spiffe://fastgo/search"}},
+	}})
+	require.NoError(t, err)
+
+	billing := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "billing", Organization: []string{"fastgo"}}}
+	p, err := a.Verify(billing)
+	require.NoError(t, err)
+	assert.Equal(t, "service:billing", p.UserID)
+	assert.Equal(t, known.AuthMethodMTLS, p.AuthMethod)
+	assert.Equal(t, []string{known.ScopePostsRead}, p.Scopes)
+	assert.True(t, p.HasRole(known.RoleService))
+
+	uri, _ := url.Parse("spiffe://fastgo/search")
+	search := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "other"}, URIs: []*url.URL{uri}}
+	p, err = a.Verify(search)
+	require.NoError(t, err)
+	assert.Equal(t, "search", p.Username)
+	assert.NotNil(t, p.Scopes)
+	assert.Empty(t, p.Scopes)
+
+	unknown := &x509.Certificate{SerialNumber: big.NewInt(3), Subject: pkix.Name{CommonName: "billing-dev"}}
+	_, err = a.Verify(unknown)
+	assert.ErrorIs(t, err, errorsx.ErrClientCertUnknown)
+
+	_, err = New(&genericoptions.MTLSOptions{Services: []genericoptions.ServicePrincipalOptions{
+		{Name: "billing", Subjects: []string{"billing"}, Scopes: []string{"admin"}},
+	}})
+	assert.Error(t, err)
+
+	// 用户接口不接受客户端证书认证，服务调用方不能拥有用户相关的权限范围
+	_, err = New(&genericoptions.MTLSOptions{Services: []genericoptions.ServicePrincipalOptions{
+		{Name: "billing", Subjects: []string{"billing"}, Scopes: []string{known.ScopeUsersRead}},
+	}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+// Package oidc 实现了使用外部 OpenID Connect 提供方登录所需的授权码流程（带 PKCE）及 ID Token 校验.
+//
+// 整个流程是无状态的：发起登录时生成一个随机密钥，state、nonce 及 PKCE code verifier 均由该密钥派生.
+// 密钥只保存在签名的状态 token 中由客户端持有，不会出现在跳转地址里，
+// 因此即使回调地址（包含授权码和 state）被截获，没有状态 token 也无法完成登录.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// httpClient 是请求外部提供方时使用的 HTTP 客户端
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// ErrStateMismatch 表示回调中的 state 与发起登录时生成的不一致，请求可能被伪造
+var ErrStateMismatch = errors.New("oidc: state mismatch")
+
+// Identity 是从 ID Token 中解析出的外部账号信息
+type Identity struct {
+	// Subject 是外部账号在提供方内的唯一标识
+	Subject string `json:"sub"`
+	// Email 是外部账号的邮箱地址，可能为空
+	Email string `json:"email"`
+	// EmailVerified 表示提供方是否已验证该邮箱
+	EmailVerified bool `json:"email_verified"`
+	// Name 是外部账号的显示名称
+	Name string `json:"name"`
+	// PreferredUsername 是外部账号偏好的用户名
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// Provider 表示一个外部 OIDC 提供方. 提供方配置在第一次使用时通过发现文档加载，
+// 加载失败时下次使用会重试，因此提供方暂时不可用不会影响服务启动.
+type Provider struct {
+	opts genericoptions.OIDCProviderOptions
+
+	mu       sync.Mutex
+	config   *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// Providers 保存所有已配置的外部 OIDC 提供方
+type Providers struct {
+	providers       []*Provider
+	stateExpiration time.Duration
+}
+
+// New 根据配置创建 Providers
+func New(opts *genericoptions.OIDCOptions) *Providers {
+	ps := &Providers{}
+	if opts == nil {
+		return ps
+	}
+
+	ps.stateExpiration = opts.StateExpiration
+
+	for _, o := range opts.Providers {
+		ps.providers = append(ps.providers, &Provider{opts: o})
+	}
+
+	return ps
+}
+
+// Get 根据名称返回提供方
+func (ps *Providers) Get(name string) (*Provider, bool) {
+	for _, p := range ps.providers {
+		if p.opts.Name == name {
+			return p, true
+		}
+	}
+
+	return nil, false
+}
+
+// List 按配置顺序返回所有提供方
+func (ps *Providers) List() []*Provider {
+	return ps.providers
+}
+
+// StateExpiration 返回从发起登录到完成回调的最长时间
+func (ps *Providers) StateExpiration() time.Duration {
+	return ps.stateExpiration
+}
+
+// Name 返回提供方名称
+func (p *Provider) Name() string {
+	return p.opts.Name
+}
+
+// DisplayName 返回展示给用户的提供方名称，未配置时返回提供方名称
+func (p *Provider) DisplayName() string {
+	if p.opts.DisplayName != "" {
+		return p.opts.DisplayName
+	}
+	return p.opts.Name
+}
+
+// AutoProvision 返回外部账号首次登录时是否自动创建本地账号
+func (p *Provider) AutoProvision() bool {
+	return p.opts.AutoProvision
+}
+
+// AuthCodeURL 返回跳转到提供方的授权地址. secret 是 NewSecret 生成的随机密钥，
+// state、nonce 及 PKCE code challenge 均由其派生.
+func (p *Provider) AuthCodeURL(ctx context.Context, secret string) (string, error) {
+	config, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return config.AuthCodeURL(
+		derive(secret, "state"),
+		gooidc.Nonce(derive(secret, "nonce")),
+		oauth2.S256ChallengeOption(derive(secret, "verifier")),
+	), nil
+}
+
+// Exchange 校验回调中的 state，使用授权码和 PKCE code verifier 换取 ID Token，
+// 校验 ID Token 的签名、签发方、受众、有效期及 nonce 后返回外部账号信息.
+func (p *Provider) Exchange(ctx context.Context, code string, state string, secret string) (*Identity, error) {
+	if subtle.ConstantTimeCompare([]byte(state), []byte(derive(secret, "state"))) != 1 {
+		return nil, ErrStateMismatch
+	}
+
+	config, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	tok, err := config.Exchange(ctx, code, oauth2.VerifierOption(derive(secret, "verifier")))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := tok.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("oidc: no id_token in token response")
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: failed to verify id token: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(derive(secret, "nonce"))) != 1 {
+		return nil, errors.New("oidc: nonce mismatch")
+	}
+
+	var identity Identity
+	if err := idToken.Claims(&identity); err != nil {
+		return nil, fmt.Errorf("oidc: failed to parse id token claims: %w", err)
+	}
+	identity.Subject = idToken.Subject
+
+	return &identity, nil
+}
+
+// discover 加载提供方的发现文档，加载成功后缓存结果
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.config != nil {
+		return p.config, p.verifier, nil
+	}
+
+	provider, err := gooidc.NewProvider(gooidc.ClientContext(ctx, httpClient), p.opts.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc: failed to discover provider %q: %w", p.opts.Name, err)
+	}
+
+	scopes := []string{gooidc.ScopeOpenID}
+	for _, scope := range p.opts.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	p.config = &oauth2.Config{
+		ClientID:     p.opts.ClientID,
+		ClientSecret: p.opts.ClientSecret,
+		RedirectURL:  p.opts.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.opts.ClientID})
+
+	return p.config, p.verifier, nil
+}
+
+// NewSecret 生成一次登录流程使用的随机密钥
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// derive 从随机密钥派生出指定用途的值. 结果为 43 个 URL 安全字符，满足 PKCE code verifier 的格式要求
+func derive(secret string, label string) string {
+	sum := sha256.Sum256([]byte(label + ":" + secret))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,173 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIssuer 是一个本地的 OIDC 提供方，实现了发现文档、JWKS 和带 PKCE 校验的 token 接口
+type mockIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu sync.Mutex
+	// codes 保存已签发的授权码对应的 code challenge 和 nonce
+	codes map[string][2]string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockIssuer{key: key, codes: map[string][2]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                m.URL,
+			"authorization_endpoint":                m.URL + "/authorize",
+			"token_endpoint":                        m.URL + "/token",
+			"jwks_uri":                              m.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		m.mu.Lock()
+		issued, ok := m.codes[r.PostForm.Get("code")]
+		delete(m.codes, r.PostForm.Get("code"))
+		m.mu.Unlock()
+
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != issued[0] {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            m.URL,
+			"aud":            "fastgo",
+			"sub":            "external-user-1",
+			"email":          "alice@example.com",
+			"email_verified": true,
+			"name":           "Alice",
+			"nonce":          issued[1],
+			"iat":            time.Now().Unix(),
+			"exp":            time.Now().Add(time.Minute).Unix(),
+		})
+		idToken.Header["kid"] = "test"
+		signed, _ := idToken.SignedString(key)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "at", "token_type": "Bearer", "id_token": signed})
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// authorize 模拟用户在提供方完成授权，返回回调中的授权码和 state
+func (m *mockIssuer) authorize(t *testing.T, authURL string) (string, string) {
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := u.Query()
+	require.Equal(t, "S256", q.Get("code_challenge_method"))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	code := "code-" + q.Get("state")[:8]
+	m.codes[code] = [2]string{q.Get("code_challenge"), q.Get("nonce")}
+
+	return code, q.Get("state")
+}
+
+func newTestProvider(issuer string) *Provider {
+	ps := New(&genericoptions.OIDCOptions{Providers: []genericoptions.OIDCProviderOptions{{
+		Name:        "mock",
+		Issuer:      issuer,
+		ClientID:    "fastgo",
+		RedirectURL: "http://127.0.0.1/callback",
+	}}})
+	p, _ := ps.Get("mock")
+	return p
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	issuer := newMockIssuer(t)
+	p := newTestProvider(issuer.URL)
+	ctx := context.Background()
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	authURL, err := p.AuthCodeURL(ctx, secret)
+	require.NoError(t, err)
+
+	code, state := issuer.authorize(t, authURL)
+	identity, err := p.Exchange(ctx, code, state, secret)
+	require.NoError(t, err)
+	assert.Equal(t, "external-user-1", identity.Subject)
+	assert.Equal(t, "alice@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Alice", identity.Name)
+
+	// 授权码只能使用一次
+	_, err = p.Exchange(ctx, code, state, secret)
+	assert.Error(t, err)
+}
+
+func TestProvider_ExchangeRequiresSecret(t *testing.T) {
+	issuer := newMockIssuer(t)
+	p := newTestProvider(issuer.URL)
+	ctx := context.Background()
+
+	secret, _ := NewSecret()
+	authURL, err := p.AuthCodeURL(ctx, secret)
+	require.NoError(t, err)
+	code, state := issuer.authorize(t, authURL)
+
+	// 截获了回调地址但没有发起登录时的密钥，无法完成登录
+	other, _ := NewSecret()
+	_, err = p.Exchange(ctx, code, state, other)
+	assert.ErrorIs(t, err, ErrStateMismatch)
+}
+
+func TestProvider_DiscoveryRetry(t *testing.T) {
+	issuer := newMockIssuer(t)
+	p := newTestProvider(issuer.URL + "/missing")
+
+	_, err := p.AuthCodeURL(context.Background(), "secret")
+	assert.Error(t, err)
+
+	// 发现失败后不缓存结果，修正后可以重试
+	p.opts.Issuer = issuer.URL
+	_, err = p.AuthCodeURL(context.Background(), "secret")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,204 @@
+package revoker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// cacheTTL 定义吊销查询结果在内存中的缓存时间.
+// 多实例部署时，其他实例上发生的吊销最多延迟 cacheTTL 生效.
+const cacheTTL = 30 * time.Second
+
+// touchInterval 定义更新登录会话最近活动时间的最小间隔，避免每个请求都写数据库
+const touchInterval = time.Minute
+
+// entry 是一条缓存的查询结果
+type entry struct {
+	// revoked 表示 jti 是否已被吊销
+	revoked bool
+	// revokedAt 表示用户最近一次吊销所有令牌的时间
+	revokedAt time.Time
+	// expireAt 表示缓存过期时间
+	expireAt time.Time
+}
+
+// Revoker 负责吊销 JWT Token，并检查 Token 是否已被吊销.
+// 吊销记录持久化在数据库中，查询结果缓存在内存中，避免每个请求都查询数据库.
+// Revoker 同时负责记录登录会话的最近活动，更新时间同样保存在内存中用于节流.
+type Revoker struct {
+	store    store.RevokedTokenStore
+	sessions store.SessionStore
+
+	mu        sync.RWMutex
+	jtis      map[string]entry
+	users     map[string]entry
+	sids      map[string]entry
+	touched   map[string]time.Time
+	lastSweep time.Time
+	// now 便于测试时替换当前时间
+	now func() time.Time
+}
+
+// New 创建一个 Revoker 实例
+func New(store store.RevokedTokenStore, sessions store.SessionStore) *Revoker {
+	return &Revoker{
+		store:    store,
+		sessions: sessions,
+		jtis:     make(map[string]entry),
+		users:    make(map[string]entry),
+		sids:     make(map[string]entry),
+		touched:  make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// RevokeToken 吊销单个 token（例如注销当前登录）
+func (r *Revoker) RevokeToken(ctx context.Context, userID string, jti string) error {
+	now := r.now()
+	obj := &model.RevokedToken{JTI: jti, UserID: userID, RevokedAt: now, ExpiresAt: now.Add(token.Expiration())}
+	if err := r.store.Create(ctx, obj); err != nil {
+		return err
+	}
+
+	r.set(r.jtis, jti, entry{revoked: true})
+
+	// 顺便清理已过期的吊销记录，清理失败不影响本次吊销
+	_ = r.store.DeleteExpired(ctx)
+
+	return nil
+}
+
+// RevokeUser 吊销用户在当前时间之前签发的所有 token（例如注销所有设备、修改密码、删除账号）
+func (r *Revoker) RevokeUser(ctx context.Context, userID string) error {
+	now := r.now()
+	obj := &model.RevokedToken{UserID: userID, RevokedAt: now, ExpiresAt: now.Add(token.Expiration())}
+	if err := r.store.Create(ctx, obj); err != nil {
+		return err
+	}
+
+	// 数据库中的 revokedAt 为毫秒精度，缓存中保持一致
+	r.set(r.users, userID, entry{revokedAt: now.Truncate(time.Millisecond)})
+
+	return nil
+}
+
+// RevokeSession 吊销登录会话，该会话签发的所有 token 随即失效（例如用户在设备管理中移除某个设备）
+func (r *Revoker) RevokeSession(ctx context.Context, sessionID string) error {
+	if err := r.sessions.Revoke(ctx, where.F("sessionID", sessionID)); err != nil {
+		return err
+	}
+
+	r.set(r.sids, sessionID, entry{revoked: true})
+
+	return nil
+}
+
+// TouchSession 记录登录会话的最近活动时间及客户端 IP. 同一会话每 touchInterval 最多更新一次数据库，
+// 多实例部署时每个实例单独计时. 更新失败只记录日志，不影响本次请求
+func (r *Revoker) TouchSession(ctx context.Context, sessionID string, clientIP string) {
+	now := r.now()
+
+	r.mu.Lock()
+	if last, ok := r.touched[sessionID]; ok && now.Sub(last) < touchInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.touched[sessionID] = now
+	r.mu.Unlock()
+
+	if err := r.sessions.MarkActive(ctx, sessionID, clientIP, now); err != nil {
+		slog.WarnContext(ctx, "Failed to record session activity", "sessionID", sessionID, "err", err)
+	}
+}
+
+// IsRevoked 检查 token 是否已被吊销
+func (r *Revoker) IsRevoked(ctx context.Context, claims *token.Claims) (bool, error) {
+	if claims.SessionID != "" {
+		e, ok := r.get(r.sids, claims.SessionID)
+		if !ok {
+			revoked, err := r.sessions.IsRevoked(ctx, claims.SessionID)
+			if err != nil {
+				return false, err
+			}
+			e = entry{revoked: revoked}
+			r.set(r.sids, claims.SessionID, e)
+		}
+		if e.revoked {
+			return true, nil
+		}
+	}
+
+	if claims.ID != "" {
+		e, ok := r.get(r.jtis, claims.ID)
+		if !ok {
+			revoked, err := r.store.IsRevoked(ctx, claims.ID)
+			if err != nil {
+				return false, err
+			}
+			e = entry{revoked: revoked}
+			r.set(r.jtis, claims.ID, e)
+		}
+		if e.revoked {
+			return true, nil
+		}
+	}
+
+	e, ok := r.get(r.users, claims.Identity)
+	if !ok {
+		revokedAt, err := r.store.UserRevokedAt(ctx, claims.Identity)
+		if err != nil {
+			return false, err
+		}
+		e = entry{revokedAt: revokedAt}
+		r.set(r.users, claims.Identity, e)
+	}
+
+	return !e.revokedAt.IsZero() && claims.IssuedAt.Before(e.revokedAt), nil
+}
+
+// get 从缓存中读取未过期的查询结果
+func (r *Revoker) get(cache map[string]entry, key string) (entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := cache[key]
+	if !ok || r.now().After(e.expireAt) {
+		return entry{}, false
+	}
+
+	return e, true
+}
+
+// set 写入缓存，并定期清理已过期的缓存，防止内存无限增长
+func (r *Revoker) set(cache map[string]entry, key string, e entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	e.expireAt = now.Add(cacheTTL)
+	cache[key] = e
+
+	if now.Sub(r.lastSweep) < cacheTTL {
+		return
+	}
+	r.lastSweep = now
+	for _, c := range []map[string]entry{r.jtis, r.users, r.sids} {
+		for k, v := range c {
+			if now.After(v.expireAt) {
+				delete(c, k)
+			}
+		}
+	}
+	for k, v := range r.touched {
+		if now.Sub(v) >= touchInterval {
+			delete(r.touched, k)
+		}
+	}
+}
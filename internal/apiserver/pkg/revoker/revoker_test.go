@@ -0,0 +1,242 @@
+package revoker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRevokedTokenStore 是保存在内存中的 RevokedTokenStore，记录查询次数以便检查缓存
+type fakeRevokedTokenStore struct {
+	store.RevokedTokenStore
+
+	jtis    map[string]bool
+	users   map[string]time.Time
+	queries int
+}
+
+func (s *fakeRevokedTokenStore) Create(ctx context.Context, obj *model.RevokedToken) error {
+	if obj.JTI != "" {
+		s.jtis[obj.JTI] = true
+		return nil
+	}
+	// 与数据库一致，吊销时间只保留毫秒精度
+	s.users[obj.UserID] = obj.RevokedAt.Truncate(time.Millisecond)
+	return nil
+}
+
+func (s *fakeRevokedTokenStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	s.queries++
+	return s.jtis[jti], nil
+}
+
+func (s *fakeRevokedTokenStore) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	s.queries++
+	return s.users[userID], nil
+}
+
+func (s *fakeRevokedTokenStore) DeleteExpired(ctx context.Context) error { return nil }
+
+// fakeSessionStore 是保存在内存中的 SessionStore，记录查询次数以便检查缓存
+type fakeSessionStore struct {
+	store.SessionStore
+
+	revoked map[string]bool
+	queries int
+	touches int
+}
+
+func (s *fakeSessionStore) Revoke(ctx context.Context, opts *where.Options) error {
+	s.revoked[opts.Filters["sessionID"].(string)] = true
+	return nil
+}
+
+func (s *fakeSessionStore) MarkActive(ctx context.Context, sessionID string, clientIP string, activeAt time.Time) error {
+	s.touches++
+	return nil
+}
+
+func (s *fakeSessionStore) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	s.queries++
+	return s.revoked[sessionID], nil
+}
+
+// newTestRevoker 创建使用内存存储的 Revoker，返回的时间指针用于调整当前时间
+func newTestRevoker() (*Revoker, *fakeRevokedTokenStore, *fakeSessionStore, *time.Time) {
+	tokens := &fakeRevokedTokenStore{jtis: make(map[string]bool), users: make(map[string]time.Time)}
+	sessions := &fakeSessionStore{revoked: make(map[string]bool)}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 123456789, time.UTC)
+	r := New(tokens, sessions)
+	r.now = func() time.Time { return now }
+	return r, tokens, sessions, &now
+}
+
+func TestIsRevoked(t *testing.T) {
+	revokedAt := time.Date(2026, 1, 1, 0, 0, 0, 123000000, time.UTC)
+
+	tests := []struct {
+		name     string
+		jtis     []string
+		sessions []string
+		users    map[string]time.Time
+		claims   token.Claims
+		want     bool
+	}{
+		{
+			name:   "not revoked",
+			claims: token.Claims{Identity: "user-000001", ID: "jti-1", SessionID: "session-1", IssuedAt: revokedAt},
+		},
+		{
+			name:   "jti revoked",
+			jtis:   []string{"jti-1"},
+			claims: token.Claims{Identity: "user-000001", ID: "jti-1", SessionID: "session-1", IssuedAt: revokedAt},
+			want:   true,
+		},
+		{
+			name:   "other jti revoked",
+			jtis:   []string{"jti-2"},
+			claims: token.Claims{Identity: "user-000001", ID: "jti-1", SessionID: "session-1", IssuedAt: revokedAt},
+		},
+		{
+			name:     "session revoked",
+			sessions: []string{"session-1"},
+			claims:   token.Claims{Identity: "user-000001", ID: "jti-1", SessionID: "session-1", IssuedAt: revokedAt},
+			want:     true,
+		},
+		{
+			name:   "issued before user revocation",
+			users:  map[string]time.Time{"user-000001": revokedAt},
+			claims: token.Claims{Identity: "user-000001", ID: "jti-1", IssuedAt: revokedAt.Add(-time.Millisecond)},
+			want:   true,
+		},
+		{
+			// 吊销后在同一毫秒内签发的 token 仍然有效，例如修改密码后立即重新登录
+			name:   "issued in the same millisecond as user revocation",
+			users:  map[string]time.Time{"user-000001": revokedAt},
+			claims: token.Claims{Identity: "user-000001", ID: "jti-1", IssuedAt: revokedAt},
+		},
+		{
+			name:   "issued after user revocation",
+			users:  map[string]time.Time{"user-000001": revokedAt},
+			claims: token.Claims{Identity: "user-000001", ID: "jti-1", IssuedAt: revokedAt.Add(time.Millisecond)},
+		},
+		{
+			name:   "other user revoked",
+			users:  map[string]time.Time{"user-000002": revokedAt},
+			claims: token.Claims{Identity: "user-000001", ID: "jti-1", IssuedAt: revokedAt.Add(-time.Millisecond)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, tokens, sessions, _ := newTestRevoker()
+			for _, jti := range tt.jtis {
+				tokens.jtis[jti] = true
+			}
+			for _, sessionID := range tt.sessions {
+				sessions.revoked[sessionID] = true
+			}
+			for userID, at := range tt.users {
+				tokens.users[userID] = at
+			}
+
+			revoked, err := r.IsRevoked(context.Background(), &tt.claims)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, revoked)
+		})
+	}
+}
+
+func TestIsRevokedCache(t *testing.T) {
+	r, tokens, sessions, now := newTestRevoker()
+	ctx := context.Background()
+	claims := &token.Claims{Identity: "user-000001", ID: "jti-1", SessionID: "session-1", IssuedAt: now.Truncate(time.Millisecond)}
+
+	revoked, err := r.IsRevoked(ctx, claims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	assert.Equal(t, 2, tokens.queries)
+	assert.Equal(t, 1, sessions.queries)
+
+	// 缓存有效期内不再查询数据库，其他实例上发生的吊销暂时不可见
+	tokens.jtis["jti-1"] = true
+	*now = now.Add(cacheTTL)
+	revoked, err = r.IsRevoked(ctx, claims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	assert.Equal(t, 2, tokens.queries)
+	assert.Equal(t, 1, sessions.queries)
+
+	// 缓存过期后重新查询
+	*now = now.Add(time.Second)
+	revoked, err = r.IsRevoked(ctx, claims)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	assert.Equal(t, 3, tokens.queries)
+	assert.Equal(t, 2, sessions.queries)
+}
+
+func TestRevokeUpdatesCache(t *testing.T) {
+	r, tokens, sessions, now := newTestRevoker()
+	ctx := context.Background()
+	issuedAt := now.Truncate(time.Millisecond)
+	first := &token.Claims{Identity: "user-000001", ID: "jti-1", SessionID: "session-1", IssuedAt: issuedAt}
+	second := &token.Claims{Identity: "user-000001", ID: "jti-2", SessionID: "session-2", IssuedAt: issuedAt}
+
+	for _, claims := range []*token.Claims{first, second} {
+		revoked, err := r.IsRevoked(ctx, claims)
+		require.NoError(t, err)
+		require.False(t, revoked)
+	}
+	queries := tokens.queries + sessions.queries
+
+	// 本实例上的吊销立即生效，不需要等待缓存过期
+	require.NoError(t, r.RevokeToken(ctx, "user-000001", "jti-1"))
+	revoked, err := r.IsRevoked(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	require.NoError(t, r.RevokeSession(ctx, "session-2"))
+	revoked, err = r.IsRevoked(ctx, second)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	assert.Equal(t, queries, tokens.queries+sessions.queries)
+
+	// 吊销用户时缓存的吊销时间与数据库一样只保留毫秒精度，吊销后同一毫秒内签发的 token 仍然有效
+	*now = now.Add(time.Second)
+	require.NoError(t, r.RevokeUser(ctx, "user-000001"))
+	before := &token.Claims{Identity: "user-000001", IssuedAt: now.Truncate(time.Millisecond).Add(-time.Millisecond)}
+	after := &token.Claims{Identity: "user-000001", IssuedAt: now.Truncate(time.Millisecond)}
+	revoked, err = r.IsRevoked(ctx, before)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, err = r.IsRevoked(ctx, after)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	assert.Equal(t, tokens.users["user-000001"], now.Truncate(time.Millisecond))
+}
+
+func TestTouchSession(t *testing.T) {
+	r, _, sessions, now := newTestRevoker()
+	ctx := context.Background()
+
+	// 同一会话在 touchInterval 内只更新一次
+	r.TouchSession(ctx, "session-1", "192.0.2.1")
+	r.TouchSession(ctx, "session-1", "192.0.2.1")
+	assert.Equal(t, 1, sessions.touches)
+
+	// 不同会话分别计时
+	r.TouchSession(ctx, "session-2", "192.0.2.1")
+	assert.Equal(t, 2, sessions.touches)
+
+	*now = now.Add(touchInterval)
+	r.TouchSession(ctx, "session-1", "192.0.2.1")
+	assert.Equal(t, 3, sessions.touches)
+}
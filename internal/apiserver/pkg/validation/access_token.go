@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+// accessTokenScopes 定义个人访问令牌可以申请的权限范围
+var accessTokenScopes = []string{
+	known.ScopeUsersRead,
+	known.ScopeUsersWrite,
+	known.ScopePostsRead,
+	known.ScopePostsWrite,
+	known.ScopeReportsWrite,
+}
+
+func (v *Validator) ValidateCreateAccessTokenRequest(ctx context.Context, rq *v1.CreateAccessTokenRequest) error {
+	if rq.Name == "" {
+		return errors.New("Name cannot be empty")
+	}
+	if len(rq.Name) > 64 {
+		return errors.New("Name cannot exceed 64 characters")
+	}
+
+	if len(rq.Scopes) == 0 {
+		return errors.New("Scopes cannot be empty")
+	}
+	for i, scope := range rq.Scopes {
+		if !slices.Contains(accessTokenScopes, scope) {
+			return fmt.Errorf("Scope %q is not supported", scope)
+		}
+		if slices.Contains(rq.Scopes[:i], scope) {
+			return fmt.Errorf("Scope %q is duplicated", scope)
+		}
+	}
+
+	if rq.ExpiresAt != nil && !rq.ExpiresAt.After(time.Now()) {
+		return errors.New("ExpiresAt must be in the future")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateDeleteAccessTokenRequest(ctx context.Context, rq *v1.DeleteAccessTokenRequest) error {
+	return nil
+}
+
+func (v *Validator) ValidateListAccessTokenRequest(ctx context.Context, rq *v1.ListAccessTokenRequest) error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package validation
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+func (v *Validator) ValidateAddCollaboratorRequest(ctx context.Context, rq *v1.AddCollaboratorRequest) error {
+	if rq.UserID == "" {
+		return errors.New("UserID cannot be empty")
+	}
+
+	if rq.Role != known.PostRoleEditor && rq.Role != known.PostRoleViewer {
+		return errors.New("Role must be one of: editor, viewer")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateRemoveCollaboratorRequest(ctx context.Context, rq *v1.RemoveCollaboratorRequest) error {
+	if rq.UserID == "" {
+		return errors.New("UserID cannot be empty")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateListCollaboratorRequest(ctx context.Context, rq *v1.ListCollaboratorRequest) error {
+	return nil
+}
+
+func (v *Validator) ValidateListSharedPostRequest(ctx context.Context, rq *v1.ListSharedPostRequest) error {
+	return nil
+}
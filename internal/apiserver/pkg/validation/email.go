@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"context"
+	"errors"
+
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/auth"
+)
+
+func (v *Validator) ValidateVerifyEmailRequest(ctx context.Context, rq *v1.VerifyEmailRequest) error {
+	if rq.Token == "" {
+		return errors.New("Token cannot be empty")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateSendVerificationEmailRequest(ctx context.Context, rq *v1.SendVerificationEmailRequest) error {
+	return nil
+}
+
+func (v *Validator) ValidateForgotPasswordRequest(ctx context.Context, rq *v1.ForgotPasswordRequest) error {
+	if rq.Email == "" {
+		return errors.New("Email cannot be empty")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateResetPasswordRequest(ctx context.Context, rq *v1.ResetPasswordRequest) error {
+	if rq.Token == "" {
+		return errors.New("Token cannot be empty")
+	}
+	// 重置密码时用户由 token 确定，是否包含用户名由业务层校验
+	if err := auth.ValidatePassword(rq.NewPassword, ""); err != nil {
+		return err
+	}
+
+	return nil
+}
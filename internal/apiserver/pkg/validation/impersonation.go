@@ -0,0 +1,35 @@
+package validation
+
+import (
+	"context"
+	"errors"
+
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+func (v *Validator) ValidateCreateImpersonationRequest(ctx context.Context, rq *v1.CreateImpersonationRequest) error {
+	if rq.UserID == "" {
+		return errors.New("UserID cannot be empty")
+	}
+
+	if rq.Reason == "" {
+		return errors.New("Reason cannot be empty")
+	}
+	if len(rq.Reason) > 255 {
+		return errors.New("Reason cannot exceed 255 characters")
+	}
+
+	if rq.ExpiresIn < 0 {
+		return errors.New("ExpiresIn cannot be negative")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateListImpersonationRequest(ctx context.Context, rq *v1.ListImpersonationRequest) error {
+	return nil
+}
+
+func (v *Validator) ValidateDeleteImpersonationRequest(ctx context.Context, rq *v1.DeleteImpersonationRequest) error {
+	return nil
+}
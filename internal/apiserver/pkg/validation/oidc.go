@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"context"
+	"errors"
+
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+func (v *Validator) ValidateListOIDCProviderRequest(ctx context.Context, rq *v1.ListOIDCProviderRequest) error {
+	return nil
+}
+
+func (v *Validator) ValidateAuthorizeOIDCRequest(ctx context.Context, rq *v1.AuthorizeOIDCRequest) error {
+	if rq.Provider == "" {
+		return errors.New("Provider cannot be empty")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateOIDCLoginRequest(ctx context.Context, rq *v1.OIDCLoginRequest) error {
+	return validateOIDCCallback(rq.Provider, rq.Code, rq.State, rq.StateToken)
+}
+
+func (v *Validator) ValidateListExternalIdentityRequest(ctx context.Context, rq *v1.ListExternalIdentityRequest) error {
+	return nil
+}
+
+func (v *Validator) ValidateLinkExternalIdentityRequest(ctx context.Context, rq *v1.LinkExternalIdentityRequest) error {
+	return validateOIDCCallback(rq.Provider, rq.Code, rq.State, rq.StateToken)
+}
+
+func (v *Validator) ValidateUnlinkExternalIdentityRequest(ctx context.Context, rq *v1.UnlinkExternalIdentityRequest) error {
+	if rq.Provider == "" {
+		return errors.New("Provider cannot be empty")
+	}
+
+	return nil
+}
+
+// validateOIDCCallback 校验提供方回调后提交的参数
+func validateOIDCCallback(provider string, code string, state string, stateToken string) error {
+	if provider == "" {
+		return errors.New("Provider cannot be empty")
+	}
+	if code == "" {
+		return errors.New("Code cannot be empty")
+	}
+	if state == "" {
+		return errors.New("State cannot be empty")
+	}
+	if stateToken == "" {
+		return errors.New("StateToken cannot be empty")
+	}
+
+	return nil
+}
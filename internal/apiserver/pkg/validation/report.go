@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+func (v *Validator) ValidateCreateReportRequest(ctx context.Context, rq *v1.CreateReportRequest) error {
+	if rq.TargetType != known.ReportTargetPost && rq.TargetType != known.ReportTargetUser {
+		return errors.New("TargetType must be one of: post, user")
+	}
+
+	if rq.TargetID == "" {
+		return errors.New("TargetID cannot be empty")
+	}
+
+	if rq.Reason == "" {
+		return errors.New("Reason cannot be empty")
+	}
+	if len(rq.Reason) > 512 {
+		return errors.New("Reason cannot exceed 512 characters")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateListReportRequest(ctx context.Context, rq *v1.ListReportRequest) error {
+	return nil
+}
+
+func (v *Validator) ValidateModerateReportRequest(ctx context.Context, rq *v1.ModerateReportRequest) error {
+	switch rq.Action {
+	case known.ModerationActionHidePost, known.ModerationActionSuspendUser, known.ModerationActionDismiss:
+	default:
+		return errors.New("Action must be one of: hide_post, suspend_user, dismiss")
+	}
+
+	if len(rq.Note) > 512 {
+		return errors.New("Note cannot exceed 512 characters")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateListModerationActionRequest(ctx context.Context, rq *v1.ListModerationActionRequest) error {
+	return nil
+}
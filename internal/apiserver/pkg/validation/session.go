@@ -0,0 +1,15 @@
+package validation
+
+import (
+	"context"
+
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+func (v *Validator) ValidateDeleteSessionRequest(ctx context.Context, rq *v1.DeleteSessionRequest) error {
+	return nil
+}
+
+func (v *Validator) ValidateListSessionRequest(ctx context.Context, rq *v1.ListSessionRequest) error {
+	return nil
+}
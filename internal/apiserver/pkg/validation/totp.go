@@ -0,0 +1,48 @@
+package validation
+
+import (
+	"context"
+	"errors"
+
+	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+)
+
+func (v *Validator) ValidateLoginTOTPRequest(ctx context.Context, rq *v1.LoginTOTPRequest) error {
+	if rq.ChallengeToken == "" {
+		return errors.New("ChallengeToken cannot be empty")
+	}
+
+	if rq.Code == "" {
+		return errors.New("Code cannot be empty")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateEnrollTOTPRequest(ctx context.Context, rq *v1.EnrollTOTPRequest) error {
+	return nil
+}
+
+func (v *Validator) ValidateConfirmTOTPRequest(ctx context.Context, rq *v1.ConfirmTOTPRequest) error {
+	if len(rq.Code) != 6 {
+		return errors.New("Code must be 6 digits")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateDisableTOTPRequest(ctx context.Context, rq *v1.DisableTOTPRequest) error {
+	if rq.Code == "" {
+		return errors.New("Code cannot be empty")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateRegenerateRecoveryCodesRequest(ctx context.Context, rq *v1.RegenerateRecoveryCodesRequest) error {
+	if rq.Code == "" {
+		return errors.New("Code cannot be empty")
+	}
+
+	return nil
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
 	v1 "github.com/TobyIcetea/fastgo/pkg/api/apiserver/v1"
+	"github.com/TobyIcetea/fastgo/pkg/auth"
+	"github.com/onexstack/onexstack/pkg/store/where"
 )
 
 func (v *Validator) ValidateCreateUserRequest(ctx context.Context, rq *v1.CreateUserRequest) error {
@@ -20,8 +23,8 @@ func (v *Validator) ValidateCreateUserRequest(ctx context.Context, rq *v1.Create
 	if rq.Password == "" {
 		return errors.New("Password cannot be empty")
 	}
-	if len(rq.Password) < 8 || len(rq.Password) > 64 {
-		return errors.New("Password must be between 8 and 64 characters")
+	if err := auth.ValidatePassword(rq.Password, rq.Username); err != nil {
+		return err
 	}
 
 	// Validate nickname(if probided)
@@ -44,6 +47,14 @@ func (v *Validator) ValidateCreateUserRequest(ctx context.Context, rq *v1.Create
 	return nil
 }
 
+func (v *Validator) ValidateRefreshTokenRequest(ctx context.Context, rq *v1.RefreshTokenRequest) error {
+	if rq.RefreshToken == "" {
+		return errors.New("RefreshToken cannot be empty")
+	}
+
+	return nil
+}
+
 func (v *Validator) ValidateUpdateUserRequest(ctx context.Context, rq *v1.UpdateUserRequest) error {
 	return nil
 }
@@ -59,3 +70,29 @@ func (v *Validator) ValidateGetUserRequest(ctx context.Context, rq *v1.GetUserRe
 func (v *Validator) ValidateListUserRequest(ctx context.Context, rq *v1.ListUserRequest) error {
 	return nil
 }
+
+func (v *Validator) ValidateChangePasswordRequest(ctx context.Context, rq *v1.ChangePasswordRequest) error {
+	// 用户不存在时不校验密码中是否包含用户名，由业务层返回用户不存在
+	var username string
+	if userM, err := v.store.User().Get(ctx, where.F("userID", rq.UserID)); err == nil {
+		username = userM.Username
+	}
+
+	if err := auth.ValidatePassword(rq.NewPassword, username); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateUpdateUserRoleRequest(ctx context.Context, rq *v1.UpdateUserRoleRequest) error {
+	if rq.Role != known.RoleUser && rq.Role != known.RoleAdmin {
+		return errors.New("Role must be one of: user, admin")
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateUnlockUserRequest(ctx context.Context, rq *v1.UnlockUserRequest) error {
+	return nil
+}
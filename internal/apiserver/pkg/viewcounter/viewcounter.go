@@ -0,0 +1,151 @@
+package viewcounter
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+)
+
+// Counter 在内存中缓冲博文的浏览次数，并周期性地批量写入数据库，
+// 避免每次查询博文都写一次 MySQL.
+type Counter struct {
+	store store.PostCounterStore
+	opts  *genericoptions.ViewCounterOptions
+
+	mu sync.Mutex
+	// pending 保存尚未写入数据库的浏览次数增量
+	pending map[string]int64
+	// seen 记录 <postID, viewer> 最近一次被计数的时间，用于去重
+	seen map[string]time.Time
+	// lastSweep 是最近一次清理 seen 的时间
+	lastSweep time.Time
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+	// now 便于测试时替换当前时间
+	now func() time.Time
+}
+
+// New 创建一个 Counter 实例
+func New(store store.PostCounterStore, opts *genericoptions.ViewCounterOptions) *Counter {
+	return &Counter{
+		store:   store,
+		opts:    opts,
+		pending: make(map[string]int64),
+		seen:    make(map[string]time.Time),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+		now:     time.Now,
+	}
+}
+
+// Record 记录一次浏览. viewer 用于去重（通常为用户 ID 或客户端 IP），
+// 同一 viewer 在去重窗口内重复浏览同一篇博文只计数一次. viewer 为空时不去重.
+// 去重记录达到 DedupSize 上限后，新的 viewer 照常计数但不再去重，避免大量不同的 viewer 耗尽内存.
+func (c *Counter) Record(postID string, viewer string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if viewer != "" && c.opts.DedupWindow > 0 {
+		key := postID + "/" + viewer
+		now := c.now()
+		c.sweep(now)
+
+		last, ok := c.seen[key]
+		if ok && now.Sub(last) < c.opts.DedupWindow {
+			return
+		}
+		if ok || len(c.seen) < c.opts.DedupSize {
+			c.seen[key] = now
+		}
+	}
+
+	c.pending[postID]++
+}
+
+// Pending 返回指定博文尚未写入数据库的浏览次数
+func (c *Counter) Pending(postID string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pending[postID]
+}
+
+// Start 启动后台协程，按 FlushInterval 周期性地将缓冲的浏览次数写入数据库
+func (c *Counter) Start() {
+	go func() {
+		defer close(c.doneCh)
+
+		ticker := time.NewTicker(c.opts.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), c.opts.FlushInterval)
+				if err := c.Flush(ctx); err != nil {
+					slog.Error("Failed to flush post views", "err", err)
+				}
+				cancel()
+			case <-c.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止后台协程，并将剩余的浏览次数写入数据库. 用于服务优雅关闭.
+func (c *Counter) Stop(ctx context.Context) error {
+	close(c.stopCh)
+
+	select {
+	case <-c.doneCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return c.Flush(ctx)
+}
+
+// Flush 将缓冲的浏览次数写入数据库. 写入失败时，增量会被合并回缓冲区，等待下次重试.
+func (c *Counter) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = make(map[string]int64, len(batch))
+	c.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := c.store.IncrViews(ctx, batch); err != nil {
+		c.mu.Lock()
+		for postID, delta := range batch {
+			c.pending[postID] += delta
+		}
+		c.mu.Unlock()
+		return err
+	}
+
+	slog.Debug("Flushed post views to backend storage", "count", len(batch))
+
+	return nil
+}
+
+// sweep 每隔一个去重窗口清理一次已经超出去重窗口的记录，防止内存无限增长. 调用方需持有锁.
+func (c *Counter) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.opts.DedupWindow {
+		return
+	}
+	c.lastSweep = now
+
+	for key, last := range c.seen {
+		if now.Sub(last) >= c.opts.DedupWindow {
+			delete(c.seen, key)
+		}
+	}
+}
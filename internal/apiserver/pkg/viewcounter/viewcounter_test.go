@@ -0,0 +1,118 @@
+package viewcounter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStore 是 store.PostCounterStore 的内存实现，用于测试
+type fakeStore struct {
+	views map[string]int64
+	err   error
+}
+
+func (s *fakeStore) IncrViews(ctx context.Context, deltas map[string]int64) error {
+	if s.err != nil {
+		return s.err
+	}
+	for postID, delta := range deltas {
+		s.views[postID] += delta
+	}
+	return nil
+}
+
+func (s *fakeStore) Delete(ctx context.Context, opts *where.Options) error { return nil }
+
+func (s *fakeStore) Views(ctx context.Context, postIDs ...string) (map[string]int64, error) {
+	return s.views, nil
+}
+
+func newTestCounter(store *fakeStore) (*Counter, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(store, &genericoptions.ViewCounterOptions{FlushInterval: time.Second, DedupWindow: time.Minute, DedupSize: 3})
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCounter_RecordDedup(t *testing.T) {
+	c, now := newTestCounter(&fakeStore{views: map[string]int64{}})
+
+	// 同一访问者在去重窗口内只计数一次
+	c.Record("post-1", "user:a")
+	c.Record("post-1", "user:a")
+	assert.Equal(t, int64(1), c.Pending("post-1"))
+
+	// 不同访问者分别计数
+	c.Record("post-1", "ip:127.0.0.1")
+	assert.Equal(t, int64(2), c.Pending("post-1"))
+
+	// 超出去重窗口后再次计数
+	*now = now.Add(time.Minute)
+	c.Record("post-1", "user:a")
+	assert.Equal(t, int64(3), c.Pending("post-1"))
+
+	// 访问者为空时不去重
+	c.Record("post-2", "")
+	c.Record("post-2", "")
+	assert.Equal(t, int64(2), c.Pending("post-2"))
+}
+
+func TestCounter_DedupSize(t *testing.T) {
+	c, now := newTestCounter(&fakeStore{views: map[string]int64{}})
+
+	for _, viewer := range []string{"ip:10.0.0.1", "ip:10.0.0.2", "ip:10.0.0.3", "ip:10.0.0.4"} {
+		c.Record("post-1", viewer)
+	}
+	assert.Equal(t, int64(4), c.Pending("post-1"))
+	assert.Len(t, c.seen, 3)
+
+	// 达到上限后新的访问者不再去重，已记录的访问者仍然去重
+	c.Record("post-1", "ip:10.0.0.4")
+	c.Record("post-1", "ip:10.0.0.1")
+	assert.Equal(t, int64(5), c.Pending("post-1"))
+
+	// 超出去重窗口的记录在下一次计数时被清理，不依赖写入数据库
+	*now = now.Add(time.Minute)
+	c.Record("post-1", "ip:10.0.0.5")
+	c.Record("post-1", "ip:10.0.0.5")
+	assert.Equal(t, int64(6), c.Pending("post-1"))
+	assert.Len(t, c.seen, 1)
+}
+
+func TestCounter_Flush(t *testing.T) {
+	store := &fakeStore{views: map[string]int64{}}
+	c, _ := newTestCounter(store)
+
+	c.Record("post-1", "user:a")
+	c.Record("post-2", "user:a")
+
+	assert.NoError(t, c.Flush(context.Background()))
+	assert.Equal(t, map[string]int64{"post-1": 1, "post-2": 1}, store.views)
+	assert.Equal(t, int64(0), c.Pending("post-1"))
+
+	// 写入失败时，增量保留在缓冲区中等待重试
+	store.err = errors.New("db down")
+	c.Record("post-1", "user:b")
+	assert.Error(t, c.Flush(context.Background()))
+	assert.Equal(t, int64(1), c.Pending("post-1"))
+
+	store.err = nil
+	assert.NoError(t, c.Flush(context.Background()))
+	assert.Equal(t, int64(2), store.views["post-1"])
+}
+
+func TestCounter_StopFlushesPending(t *testing.T) {
+	store := &fakeStore{views: map[string]int64{}}
+	c, _ := newTestCounter(store)
+	c.Start()
+
+	c.Record("post-1", "user:a")
+	assert.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, int64(1), store.views["post-1"])
+}
@@ -12,42 +12,101 @@ import (
 
 	"github.com/TobyIcetea/fastgo/internal/apiserver/biz"
 	"github.com/TobyIcetea/fastgo/internal/apiserver/handler"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/contentfilter"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/lockout"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/mailer"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/mtls"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/oidc"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/revoker"
 	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/validation"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/viewcounter"
 	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
 	"github.com/TobyIcetea/fastgo/internal/pkg/core"
 	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/idempotency"
 	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/metrics"
 	mw "github.com/TobyIcetea/fastgo/internal/pkg/middleware"
+	"github.com/TobyIcetea/fastgo/internal/pkg/ratelimit"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/TobyIcetea/fastgo/pkg/auth"
 	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
 	"github.com/TobyIcetea/fastgo/pkg/token"
 	"github.com/gin-gonic/gin"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // Config 配置结构体，用于存储应用相关的配置
 // 不用 viper.Get，是因为这种方式能更加清晰的知道应用提供了哪些配置项
 type Config struct {
-	MySQLOptions *genericoptions.MySQLOptions
-	Addr         string
-	JWTKey       string
-	Expiration   time.Duration
+	MySQLOptions        *genericoptions.MySQLOptions
+	ViewCounterOptions  *genericoptions.ViewCounterOptions
+	ModerationOptions   *genericoptions.ModerationOptions
+	AuthzOptions        *genericoptions.AuthzOptions
+	MailOptions         *genericoptions.MailOptions
+	LockoutOptions      *genericoptions.LockoutOptions
+	PasswordOptions     *genericoptions.PasswordOptions
+	OIDCOptions         *genericoptions.OIDCOptions
+	MTLSOptions         *genericoptions.MTLSOptions
+	RateLimitOptions    *genericoptions.RateLimitOptions
+	MetricsOptions      *genericoptions.MetricsOptions
+	TracingOptions      *genericoptions.TracingOptions
+	IdempotencyOptions  *genericoptions.IdempotencyOptions
+	CompressionOptions  *genericoptions.CompressionOptions
+	RequestLimitOptions *genericoptions.RequestLimitOptions
+	JWTKeyOptions       *genericoptions.JWTKeyOptions
+	Addr                string
+	TrustedProxies      []string
+	JWTKey              string
+	Expiration          time.Duration
+	RefreshExpiration   time.Duration
 }
 
 // Server 定义了一个服务器结构体类型
 type Server struct {
 	cfg *Config
 	srv *http.Server
+	// mtlsSrv 是供内部服务使用客户端证书访问的 HTTPS Server，未开启 mTLS 时为 nil
+	mtlsSrv *http.Server
+	// metricsSrv 是暴露 Prometheus 指标的 HTTP Server，未开启时为 nil
+	metricsSrv *http.Server
+	// tracerProvider 负责导出链路追踪的 span，关闭服务时需要将缓冲的 span 全部导出
+	tracerProvider *sdktrace.TracerProvider
+	views          *viewcounter.Counter
 }
 
 // NewServer 根据配置创建服务器
 func (cfg *Config) NewServer() (*Server, error) {
-	// 初始化 token 包的签名密钥、认证 Key 及 Token 默认过期时间
-	token.Init(cfg.JWTKey, known.XUserID, cfg.Expiration)
+	// 初始化 token 包的签名密钥、认证 Key 及 Token、Refresh Token 默认过期时间
+	token.Init(cfg.JWTKey, known.XUserID, cfg.Expiration, cfg.RefreshExpiration)
+	// 配置了非对称签名密钥时，加载密钥并使用 RS256 或 EdDSA 签发 token
+	if err := cfg.loadJWTKeys(); err != nil {
+		return nil, err
+	}
+
+	// 设置新密码使用的哈希算法及注册、修改和重置密码时校验的密码规则
+	if err := cfg.initPassword(); err != nil {
+		return nil, err
+	}
+
+	// 初始化链路追踪，需要在创建任何 span 之前完成
+	tp, err := cfg.initTracing()
+	if err != nil {
+		return nil, err
+	}
 
 	// 创建 Gin 引擎
-	engine := gin.New()
+	engine, err := cfg.newEngine()
+	if err != nil {
+		return nil, err
+	}
 
-	// gin.Recovery() 中间件，用来捕获任何 panic，并恢复
-	mws := []gin.HandlerFunc{gin.Recovery(), mw.NoCache, mw.Cors, mw.RequestID()}
+	// gin.Recovery() 中间件，用来捕获任何 panic，并恢复. 链路追踪、访问日志和指标中间件放在 gin.Recovery() 之前，
+	// 以便 panic 恢复后返回的 500 响应也能被记录. 压缩中间件放在访问日志之后，访问日志记录压缩后的字节数.
+	// 默认禁止缓存响应，可以缓存的路由单独设置缓存策略
+	mws := []gin.HandlerFunc{mw.RequestID(), mw.Tracing(), mw.ClientIP(), mw.UserAgent(), mw.AccessLog(), mw.Metrics(), cfg.compress(), gin.Recovery(), mw.CacheControl(mw.CacheNoStore), mw.Cors}
 	engine.Use(mws...)
 
 	// 初始化数据库连接
@@ -55,19 +114,97 @@ func (cfg *Config) NewServer() (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	// 为每条 SQL 语句创建 span
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return nil, err
+	}
 	store := store.NewStore(db)
 
-	cfg.InstallRESTAPI(engine, store)
+	// 注册数据库连接池指标
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := metrics.RegisterDB(sqlDB); err != nil {
+		return nil, err
+	}
+
+	// 为配置中的用户授予管理员角色
+	if err := cfg.bootstrapAdmins(store); err != nil {
+		return nil, err
+	}
+
+	// 创建博文浏览计数器，浏览次数先缓冲在内存中，再周期性地写入数据库
+	views := viewcounter.New(store.PostCounter(), cfg.ViewCounterOptions)
+
+	// 创建内容过滤器，创建或更新博文时命中敏感内容会自动提交举报
+	filter, err := contentfilter.New(cfg.ModerationOptions.Keywords, cfg.ModerationOptions.Patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	// 创建 token 吊销器，用于注销登录以及修改密码、删除账号后吊销已签发的 token
+	revoker := revoker.New(store.RevokedToken(), store.Session())
+
+	// 创建邮件通知器，用于发送验证邮箱和重置密码的邮件
+	notifier, err := cfg.newNotifier()
+	if err != nil {
+		return nil, err
+	}
+
+	// 创建登录失败计数器，同一账号或客户端 IP 连续登录失败过多时临时锁定
+	lockout := lockout.New(cfg.LockoutOptions)
+
+	// 创建外部 OIDC 提供方，提供方的配置在第一次使用时通过发现文档加载
+	providers := oidc.New(cfg.OIDCOptions)
+
+	// 创建服务调用方认证器，将内部服务的 TLS 客户端证书映射为拥有独立权限范围的服务调用方
+	certs, err := mtls.New(cfg.MTLSOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// 创建限流器，限制单个客户端或用户的请求速率
+	limiter, err := cfg.newRateLimiter()
+	if err != nil {
+		return nil, err
+	}
+
+	// 创建幂等记录存储，保存携带 Idempotency-Key 的创建请求的响应
+	idempotency, err := cfg.newIdempotencyStore()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.InstallRESTAPI(engine, store, views, filter, revoker, notifier, lockout, providers, certs, limiter, idempotency)
 
 	// 创建 HTTP Server 实例
 	httpsrv := &http.Server{Addr: cfg.Addr, Handler: engine}
 
-	return &Server{cfg: cfg, srv: httpsrv}, nil
+	// 开启 mTLS 时，创建要求客户端证书的 HTTPS Server 实例，与 HTTP Server 共用路由
+	var mtlssrv *http.Server
+	if cfg.MTLSOptions.Enabled() {
+		tlsConfig, err := mtls.TLSConfig(cfg.MTLSOptions)
+		if err != nil {
+			return nil, err
+		}
+		mtlssrv = &http.Server{Addr: cfg.MTLSOptions.Addr, Handler: engine, TLSConfig: tlsConfig}
+	}
+
+	// 开启指标时，创建单独的 HTTP Server 暴露 /metrics，与 API 监听地址分开
+	var metricssrv *http.Server
+	if cfg.MetricsOptions != nil && cfg.MetricsOptions.Enabled() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricssrv = &http.Server{Addr: cfg.MetricsOptions.Addr, Handler: mux}
+	}
+
+	return &Server{cfg: cfg, srv: httpsrv, mtlsSrv: mtlssrv, metricsSrv: metricssrv, tracerProvider: tp, views: views}, nil
 
 }
 
 // 注册 API 路由。路由的路径和 HTTP 方法，严格遵循 REST 规范
-func (cfg *Config) InstallRESTAPI(engine *gin.Engine, store store.IStore) {
+func (cfg *Config) InstallRESTAPI(engine *gin.Engine, store store.IStore, views *viewcounter.Counter, filter *contentfilter.Filter, revoker *revoker.Revoker, notifier *mailer.Notifier, lockout *lockout.Lockout, providers *oidc.Providers, certs *mtls.Authenticator, limiter ratelimit.Limiter, idempotency idempotency.Store) {
 	// 注册 404 Handler
 	engine.NoRoute(func(c *gin.Context) {
 		core.WriteResponse(c, errorsx.ErrNotFound.WithMessage("Page not found"), nil)
@@ -78,41 +215,163 @@ func (cfg *Config) InstallRESTAPI(engine *gin.Engine, store store.IStore) {
 		core.WriteResponse(c, map[string]string{"Status": "ok"}, nil)
 	})
 
+	// 注册 JWKS 接口，公开验证 token 的公钥，其他服务可以据此验证本服务签发的 token
+	engine.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		core.WriteResponse(c, token.PublicJWKS(), nil)
+	})
+
 	// 创建核心业务处理器
-	handler := handler.NewHandler(biz.NewBiz(store), validation.NewValidator(store))
+	biz := biz.NewBiz(store, views, filter, revoker, notifier, cfg.MailOptions, lockout, providers)
+	handler := handler.NewHandler(biz, validation.NewValidator(store))
+
+	// 限流规则：auth 限制登录、注册等无需认证的接口，api 限制所有需要认证的接口，post-create 限制创建博客
+	authLimit := cfg.rateLimit(limiter, "auth")
+	apiLimit := cfg.rateLimit(limiter, "api")
+	postCreateLimit := cfg.rateLimit(limiter, "post-create")
+
+	// 创建资源的接口支持 Idempotency-Key，客户端超时重试时不会重复创建
+	idempotent := cfg.idempotent(idempotency)
+
+	// 博客和用户的查询接口允许客户端缓存响应，使用前通过 ETag 或 Last-Modified 确认，未变化时返回 304.
+	// 列表中的记录被删除、博客浏览次数变化时不会更新 UpdatedAt，客户端同时带有 If-None-Match 时以 ETag 为准
+	revalidate, conditional := mw.CacheControl(mw.CacheRevalidate), mw.ConditionalGet()
+
+	// 请求限制按路由分组配置请求体大小和处理时限，每个路由只加载一次，并且最先加载，
+	// 使后续的认证、限流等操作也受处理时限约束
+	authReq := cfg.requestLimit("auth")
 
 	// 注册用户登录和令牌刷新接口。这2个接口比较简单，所以没有 API 版本
-	engine.POST("/login", handler.Login)
-	// 注意：认证中间件要在 handler.RefreshToken 之前加载
-	engine.PUT("/refresh-token", mw.Authn(), handler.RefreshToken)
+	engine.POST("/login", authReq, authLimit, handler.Login)
+	// 开启了两步验证的用户，使用 /login 返回的挑战 token 和验证码完成登录
+	engine.POST("/login/totp", authReq, authLimit, handler.LoginTOTP)
+	// 刷新令牌使用 refresh token 认证，不需要加载认证中间件
+	engine.PUT("/refresh-token", authReq, authLimit, handler.RefreshToken)
+	// 忘记密码时，通过邮件中的链接重置密码
+	engine.POST("/password/forgot", authReq, authLimit, handler.ForgotPassword)
+	engine.POST("/password/reset", authReq, authLimit, handler.ResetPassword)
+	// 使用外部 OIDC 账号登录：先获取授权地址和状态 token，在提供方完成授权后提交授权码完成登录
+	engine.GET("/oidc/providers", authReq, handler.ListOIDCProvider)
+	engine.POST("/oidc/:provider/authorize", authReq, authLimit, handler.AuthorizeOIDCLogin)
+	engine.POST("/oidc/:provider/login", authReq, authLimit, handler.LoginOIDC)
+
+	// 认证中间件同时接受 JWT Token 和个人访问令牌. 个人访问令牌只能访问声明了对应权限范围的路由，
+	// 敏感操作使用 mw.SessionOnly() 禁止个人访问令牌访问
+	authn := mw.Authn(revoker, revoker, biz.AccessTokenV1(), nil)
+	authMiddlewares := []gin.HandlerFunc{authn, apiLimit}
+	sessionMiddlewares := []gin.HandlerFunc{authn, apiLimit, mw.SessionOnly()}
+	// 允许内部服务通过 mTLS 客户端证书访问的路由，服务调用方与个人访问令牌一样受权限范围限制
+	serviceMiddlewares := []gin.HandlerFunc{mw.Authn(revoker, revoker, biz.AccessTokenV1(), certs), apiLimit}
 
-	authMiddlewares := []gin.HandlerFunc{mw.Authn()}
+	// 注册注销接口，注销当前登录或所有登录
+	logout := engine.Group("", authReq)
+	logout.Use(sessionMiddlewares...)
+	logout.POST("/logout", handler.Logout)
+	logout.POST("/logout-all", mw.NoImpersonation(), handler.LogoutAll)
 
 	// 注册 v1 版本 API 路由分组
 	v1 := engine.Group("/v1")
 	{
 		// 用户相关路由
-		userv1 := v1.Group("/users")
+		userv1 := v1.Group("/users", cfg.requestLimit("users"))
 		{
 			// 创建用户。这里要注意：创建用户是不用进行认证和授权的
-			userv1.POST("", handler.CreateUser) // 创建用户
+			userv1.POST("", authLimit, idempotent, handler.CreateUser)  // 创建用户
+			userv1.POST("verify-email", authLimit, handler.VerifyEmail) // 验证邮箱，使用验证邮件中的 token 认证
 			userv1.Use(authMiddlewares...)
-			userv1.PUT(":userID/change-password", handler.ChangePassword) // 修改用户密码
-			userv1.PUT(":userID", handler.UpdateUser)                     // 更新用户信息
-			userv1.DELETE(":userID", handler.DeleteUser)                  // 删除用户
-			userv1.GET(":userID", handler.GetUser)                        // 查询用户详情
-			userv1.GET("", handler.ListUser)                              // 查询用户列表.
+			// 管理员可以操作任意用户，普通用户只能操作自己
+			userv1.PUT(":userID/change-password", mw.SessionOnly(), mw.NoImpersonation(), mw.Authz(), handler.ChangePassword)                                   // 修改用户密码
+			userv1.PUT(":userID", mw.RequireScopes(known.ScopeUsersWrite), mw.NoImpersonation(), mw.Authz(), handler.UpdateUser)                                // 更新用户信息
+			userv1.DELETE(":userID", mw.SessionOnly(), mw.NoImpersonation(), mw.Authz(), handler.DeleteUser)                                                    // 删除用户
+			userv1.GET(":userID", mw.RequireScopes(known.ScopeUsersRead), mw.Authz(), revalidate, conditional, handler.GetUser)                                 // 查询用户详情
+			userv1.POST(":userID/verification-email", mw.RequireScopes(known.ScopeUsersWrite), mw.NoImpersonation(), mw.Authz(), handler.SendVerificationEmail) // 重新发送验证邮件
+			// 以下接口只有管理员可以访问
+			userv1.GET("", mw.RequireScopes(known.ScopeUsersRead), mw.RequireRole(known.RoleAdmin), revalidate, conditional, handler.ListUser) // 查询用户列表.
+			userv1.PUT(":userID/role", mw.SessionOnly(), mw.RequireRole(known.RoleAdmin), handler.UpdateUserRole)                              // 修改用户角色
+			userv1.POST(":userID/unlock", mw.SessionOnly(), mw.RequireRole(known.RoleAdmin), handler.UnlockUser)                               // 解除账号锁定
+		}
+
+		// 个人访问令牌相关路由，只能使用登录获得的 token 管理，模拟登录期间不能管理
+		tokenv1 := v1.Group("/access-tokens", cfg.requestLimit("access-tokens"))
+		tokenv1.Use(append(sessionMiddlewares, mw.NoImpersonation())...)
+		{
+			tokenv1.POST("", handler.CreateAccessToken)           // 创建个人访问令牌
+			tokenv1.DELETE(":tokenID", handler.DeleteAccessToken) // 吊销个人访问令牌
+			tokenv1.GET("", handler.ListAccessToken)              // 查询个人访问令牌列表
+		}
+
+		// 登录会话（设备）相关路由，只能使用登录获得的 token 管理，模拟登录期间不能管理
+		sessionv1 := v1.Group("/sessions", cfg.requestLimit("sessions"))
+		sessionv1.Use(append(sessionMiddlewares, mw.NoImpersonation())...)
+		{
+			sessionv1.GET("", handler.ListSession)                // 查询登录会话列表
+			sessionv1.DELETE(":sessionID", handler.DeleteSession) // 吊销登录会话
+		}
+
+		// 外部账号关联相关路由，只能使用登录获得的 token 管理，模拟登录期间不能管理
+		identityv1 := v1.Group("/identities", cfg.requestLimit("identities"))
+		identityv1.Use(append(sessionMiddlewares, mw.NoImpersonation())...)
+		{
+			identityv1.GET("", handler.ListExternalIdentity)                          // 查询已关联的外部账号列表
+			identityv1.POST(":provider/authorize", handler.AuthorizeExternalIdentity) // 发起关联外部账号
+			identityv1.POST(":provider", handler.LinkExternalIdentity)                // 完成关联外部账号
+			identityv1.DELETE(":provider", handler.UnlinkExternalIdentity)            // 解除关联外部账号
+		}
+
+		// TOTP 两步验证相关路由，只能使用登录获得的 token 管理，模拟登录期间不能管理
+		totpv1 := v1.Group("/totp", cfg.requestLimit("totp"))
+		totpv1.Use(append(sessionMiddlewares, mw.NoImpersonation())...)
+		{
+			totpv1.POST("", handler.EnrollTOTP)                            // 开始设置两步验证
+			totpv1.POST("confirm", handler.ConfirmTOTP)                    // 确认开启两步验证
+			totpv1.DELETE("", handler.DisableTOTP)                         // 关闭两步验证
+			totpv1.POST("recovery-codes", handler.RegenerateRecoveryCodes) // 重新生成恢复码
 		}
 
-		// 博客相关路由
-		postv1 := v1.Group("/posts", authMiddlewares...)
+		// 管理员模拟登录相关路由，只有管理员可以访问. 模拟登录 token 的 act 声明中记录管理员身份，
+		// 模拟登录期间的请求都会记录审计日志
+		impersonationv1 := v1.Group("/impersonations", cfg.requestLimit("impersonations"))
+		impersonationv1.Use(append(sessionMiddlewares, mw.NoImpersonation(), mw.RequireRole(known.RoleAdmin))...)
 		{
+			impersonationv1.POST("", handler.CreateImpersonation)                   // 模拟登录指定用户
+			impersonationv1.GET("", handler.ListImpersonation)                      // 查询模拟登录记录
+			impersonationv1.DELETE(":impersonationID", handler.DeleteImpersonation) // 提前结束模拟登录
+		}
+
+		// 博客相关路由，内部服务可以使用客户端证书访问
+		postv1 := v1.Group("/posts", cfg.requestLimit("posts"))
+		postv1.Use(serviceMiddlewares...)
+		{
+			read, write := mw.RequireScopes(known.ScopePostsRead), mw.RequireScopes(known.ScopePostsWrite)
+
 			// 创建博客
-			postv1.POST("", handler.CreatePost)       // 创建博客
-			postv1.PUT(":postID", handler.UpdatePost) // 更新博客
-			postv1.DELETE("", handler.DeletePost)     // 删除博客
-			postv1.GET(":postID", handler.GetPost)    // 查询博客详情
-			postv1.GET("", handler.ListPost)          // 查询博客列表
+			// 重放的请求不会再次创建博客，因此幂等中间件放在创建博客的限流之前. 被限流的响应不会被保存，客户端可以使用同一个 key 重试
+			postv1.POST("", write, idempotent, postCreateLimit, handler.CreatePost)     // 创建博客
+			postv1.PUT(":postID", write, handler.UpdatePost)                            // 更新博客
+			postv1.DELETE("", write, handler.DeletePost)                                // 删除博客
+			postv1.GET(":postID", read, revalidate, conditional, handler.GetPost)       // 查询博客详情
+			postv1.GET("", read, revalidate, conditional, handler.ListPost)             // 查询博客列表
+			postv1.GET("shared", read, revalidate, conditional, handler.ListSharedPost) // 查询共享给我的博客列表
+
+			// 博客协作者相关路由
+			postv1.POST(":postID/collaborators", write, handler.AddCollaborator)              // 邀请协作者
+			postv1.DELETE(":postID/collaborators/:userID", write, handler.RemoveCollaborator) // 移除协作者
+			postv1.GET(":postID/collaborators", read, handler.ListCollaborator)               // 查询协作者列表
+		}
+
+		// 举报相关路由，任何登录用户都可以举报博客或用户，内部服务可以使用客户端证书提交举报
+		reportv1 := v1.Group("/reports", cfg.requestLimit("reports"))
+		reportv1.Use(serviceMiddlewares...)
+		{
+			reportv1.POST("", mw.RequireScopes(known.ScopeReportsWrite), handler.CreateReport) // 提交举报
+		}
+
+		// 内容审核相关路由，只有管理员可以访问
+		moderationv1 := v1.Group("/moderation", cfg.requestLimit("moderation"))
+		moderationv1.Use(append(sessionMiddlewares, mw.RequireRole(known.RoleAdmin))...)
+		{
+			moderationv1.GET("reports", handler.ListReport)                        // 查询审核队列
+			moderationv1.POST("reports/:reportID/actions", handler.ModerateReport) // 处理举报
+			moderationv1.GET("actions", handler.ListModerationAction)              // 查询审核操作记录
 		}
 	}
 }
@@ -122,6 +381,8 @@ func (s *Server) Run() error {
 	// 运行 HTTP 服务器
 	// 打印一条日志，用来提示 HTTP 服务已经起来，方便排错
 	slog.Info("Start to listening the incoming requests on http address", "addr", s.cfg.Addr)
+	// 启动博文浏览次数的周期性写入
+	s.views.Start()
 	go func() {
 		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			slog.Error(err.Error())
@@ -129,12 +390,49 @@ func (s *Server) Run() error {
 		}
 	}()
 
+	// 运行指标服务器
+	if s.metricsSrv != nil {
+		slog.Info("Start to serving metrics on http address", "addr", s.metricsSrv.Addr)
+		go func() {
+			if err := s.metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error(err.Error())
+				os.Exit(1)
+			}
+		}()
+	}
+
+	// 运行 mTLS 服务器，证书和私钥已经加载到 TLSConfig 中
+	if s.mtlsSrv != nil {
+		slog.Info("Start to listening the incoming requests on mtls address", "addr", s.mtlsSrv.Addr)
+		go func() {
+			if err := s.mtlsSrv.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error(err.Error())
+				os.Exit(1)
+			}
+		}()
+	}
+
 	// 创建一个 os.Signal 类型的 channel，用于接收系统信号
 	quit := make(chan os.Signal, 1)
 	// 当执行 kill 命令时（不带参数），默认会发送 syscall.SIGTERM 信号
 	// 使用 kill -2 命令会发送 syscall.SIGINT 信号（例如按 Ctrl+C 触发）
 	// 使用 kill -9 命令会发送 syscall.SIGKILL 信号，但 SIGKILL 信号无法被捕获，因此无需监听和处理
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	// 收到 SIGHUP 信号时重新加载 JWT 签名密钥，无需重启服务即可完成密钥轮换
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+	go func() {
+		for range reload {
+			if err := s.cfg.loadJWTKeys(); err != nil {
+				slog.Error("Failed to reload JWT keys, keep using the previous keys", "err", err)
+				continue
+			}
+			slog.Info("JWT keys reloaded")
+		}
+	}()
+
 	// 阻塞程序，等待从 quit channel 中接收到信号
 	<-quit
 
@@ -144,10 +442,43 @@ func (s *Server) Run() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// 先关闭依赖的服务，再关闭被依赖的服务
+	// 先关闭依赖的服务，再关闭被依赖的服务. 某个服务关闭失败时仍然继续关闭其余服务，最后返回所有错误
+	var errs []error
+
 	// 10 秒内优雅关闭服务（将未处理完的请求处理完再关闭服务），超过 10 秒就超时退出
 	if err := s.srv.Shutdown(ctx); err != nil {
 		slog.Error("Insecure Server forced to shutdown", "err", err)
+		errs = append(errs, err)
+	}
+	if s.mtlsSrv != nil {
+		if err := s.mtlsSrv.Shutdown(ctx); err != nil {
+			slog.Error("mTLS Server forced to shutdown", "err", err)
+			errs = append(errs, err)
+		}
+	}
+	if s.metricsSrv != nil {
+		if err := s.metricsSrv.Shutdown(ctx); err != nil {
+			slog.Error("Metrics Server forced to shutdown", "err", err)
+			errs = append(errs, err)
+		}
+	}
+
+	// HTTP 服务关闭后不会再产生新的浏览记录，此时将缓冲的浏览次数全部写入数据库.
+	// HTTP 服务关闭超时后 ctx 已经过期，因此使用单独的超时时间，保证缓冲的数据总能尝试写入
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer flushCancel()
+	if err := s.views.Stop(flushCtx); err != nil {
+		slog.Error("Failed to flush buffered post views", "err", err)
+		errs = append(errs, err)
+	}
+
+	// 所有请求处理完成后，将缓冲的 span 全部导出
+	if err := s.tracerProvider.Shutdown(flushCtx); err != nil {
+		slog.Error("Failed to shutdown tracer provider", "err", err)
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
 		return err
 	}
 
@@ -155,3 +486,194 @@ func (s *Server) Run() error {
 
 	return nil
 }
+
+// newEngine 创建 Gin 引擎. Gin 默认信任所有代理的 X-Forwarded-For 等请求头，客户端可以伪造请求头绕过按 IP 的限流和登录锁定，
+// 因此只信任配置的代理，未配置时客户端 IP 取连接的对端地址.
+func (cfg *Config) newEngine() (*gin.Engine, error) {
+	engine := gin.New()
+	if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
+	}
+
+	return engine, nil
+}
+
+// loadJWTKeys 从密钥目录中加载 JWT 签名密钥. 加载失败时保留之前的密钥.
+func (cfg *Config) loadJWTKeys() error {
+	if cfg.JWTKeyOptions == nil || !cfg.JWTKeyOptions.Enabled() {
+		return nil
+	}
+
+	ks, err := cfg.JWTKeyOptions.NewKeySet()
+	if err != nil {
+		return err
+	}
+	token.SetKeySet(ks)
+
+	return nil
+}
+
+// initTracing 根据配置创建 TracerProvider，并设置为全局的 TracerProvider 和 W3C Trace Context 传播器
+func (cfg *Config) initTracing() (*sdktrace.TracerProvider, error) {
+	opts := cfg.TracingOptions
+	if opts == nil {
+		opts = genericoptions.NewTracingOptions()
+	}
+
+	tp, err := opts.NewTracerProvider(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(tracing.Propagator())
+
+	return tp, nil
+}
+
+// initPassword 设置密码哈希配置并加载密码规则
+func (cfg *Config) initPassword() error {
+	if cfg.PasswordOptions == nil {
+		return nil
+	}
+
+	policy, err := cfg.PasswordOptions.NewPolicy()
+	if err != nil {
+		return err
+	}
+
+	auth.SetHashConfig(cfg.PasswordOptions.HashConfig())
+	auth.SetPasswordPolicy(policy)
+
+	return nil
+}
+
+// newRateLimiter 根据配置创建限流器
+func (cfg *Config) newRateLimiter() (ratelimit.Limiter, error) {
+	if cfg.RateLimitOptions == nil || cfg.RateLimitOptions.Backend != genericoptions.RateLimitBackendRedis {
+		return ratelimit.NewMemory(), nil
+	}
+
+	client, err := cfg.RateLimitOptions.Redis.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return ratelimit.NewRedis(client), nil
+}
+
+// rateLimit 返回名称为 name 的限流规则对应的中间件，未配置该规则时不限流
+func (cfg *Config) rateLimit(limiter ratelimit.Limiter, name string) gin.HandlerFunc {
+	if cfg.RateLimitOptions == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	rule, ok := cfg.RateLimitOptions.Rules[name]
+	if !ok {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	burst := rule.Burst
+	if burst == 0 {
+		burst = rule.Requests
+	}
+
+	key := mw.RateLimitByIP
+	switch rule.Key {
+	case genericoptions.RateLimitKeyUser:
+		key = mw.RateLimitByUser
+	case genericoptions.RateLimitKeyRoute:
+		key = mw.RateLimitByRoute
+	}
+
+	return mw.RateLimit(limiter, name, ratelimit.Every(rule.Requests, rule.Period, burst), key)
+}
+
+// newIdempotencyStore 根据配置创建幂等记录存储
+func (cfg *Config) newIdempotencyStore() (idempotency.Store, error) {
+	if cfg.IdempotencyOptions == nil || cfg.IdempotencyOptions.Backend != genericoptions.IdempotencyBackendRedis {
+		return idempotency.NewMemory(), nil
+	}
+
+	client, err := cfg.IdempotencyOptions.Redis.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return idempotency.NewRedis(client), nil
+}
+
+// idempotent 返回支持 Idempotency-Key 的中间件
+func (cfg *Config) idempotent(store idempotency.Store) gin.HandlerFunc {
+	opts := cfg.IdempotencyOptions
+	if opts == nil {
+		opts = genericoptions.NewIdempotencyOptions()
+	}
+
+	return mw.Idempotency(store, opts.TTL, opts.LockTimeout)
+}
+
+// compress 返回响应压缩中间件
+func (cfg *Config) compress() gin.HandlerFunc {
+	opts := cfg.CompressionOptions
+	if opts == nil {
+		opts = genericoptions.NewCompressionOptions()
+	}
+
+	return mw.Compress(opts.MinLength, opts.ContentTypes, opts.Encodings)
+}
+
+// requestLimit 返回名称为 group 的路由分组的请求体大小和处理时限中间件，未配置该分组时使用默认值
+func (cfg *Config) requestLimit(group string) gin.HandlerFunc {
+	opts := cfg.RequestLimitOptions
+	if opts == nil {
+		opts = genericoptions.NewRequestLimitOptions()
+	}
+
+	return mw.RequestLimit(opts.Group(group))
+}
+
+// newNotifier 根据配置创建邮件通知器
+func (cfg *Config) newNotifier() (*mailer.Notifier, error) {
+	templates, err := mailer.LoadTemplates(cfg.MailOptions.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var m mailer.Mailer
+	switch opts := cfg.MailOptions; opts.Driver {
+	case genericoptions.MailDriverSMTP:
+		m = mailer.NewSMTP(opts.SMTPHost, opts.SMTPPort, opts.SMTPUsername, opts.SMTPPassword, opts.From)
+	case genericoptions.MailDriverFile:
+		m = mailer.NewFile(opts.FileDir, opts.From)
+	default:
+		m = mailer.NewLog()
+	}
+
+	return mailer.NewNotifier(m, templates), nil
+}
+
+// bootstrapAdmins 为配置中的用户授予管理员角色，用户不存在时跳过
+func (cfg *Config) bootstrapAdmins(store store.IStore) error {
+	if cfg.AuthzOptions == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+	for _, userID := range cfg.AuthzOptions.Admins {
+		userM, err := store.User().Get(ctx, where.F("userID", userID))
+		if err != nil {
+			slog.Warn("Admin user not found, skip granting admin role", "userID", userID)
+			continue
+		}
+		if userM.Role == known.RoleAdmin {
+			continue
+		}
+
+		userM.Role = known.RoleAdmin
+		if err := store.User().Update(ctx, userM); err != nil {
+			return err
+		}
+		slog.Info("Granted admin role to user", "userID", userID)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package apiserver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/pkg/lockout"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store/storetest"
+	mw "github.com/TobyIcetea/fastgo/internal/pkg/middleware"
+	genericoptions "github.com/TobyIcetea/fastgo/pkg/options"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestConfig 创建注册路由所需的最小配置
+func newTestConfig() *Config {
+	return &Config{
+		ModerationOptions: &genericoptions.ModerationOptions{},
+		AuthzOptions:      &genericoptions.AuthzOptions{},
+		MailOptions:       genericoptions.NewMailOptions(),
+		LockoutOptions:    genericoptions.NewLockoutOptions(),
+		OIDCOptions:       genericoptions.NewOIDCOptions(),
+	}
+}
+
+// newTestEngine 创建注册了所有路由的 Gin 引擎，路由不访问的依赖传入 nil
+func newTestEngine() *gin.Engine {
+	engine := gin.New()
+	newTestConfig().InstallRESTAPI(engine, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	return engine
+}
+
+func TestJWKSRoute(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ks, err := token.NewKeySet("", &token.Key{ID: "2026-01", Method: jwt.SigningMethodEdDSA, Private: priv, Public: pub})
+	require.NoError(t, err)
+	token.SetKeySet(ks)
+	t.Cleanup(func() { token.SetKeySet(nil) })
+
+	// JWKS 接口无需认证即可访问
+	w := httptest.NewRecorder()
+	newTestEngine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var jwks token.JWKS
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "2026-01", jwks.Keys[0].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+}
+
+func TestTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		want    string
+	}{
+		// 默认不信任任何代理，伪造的 X-Forwarded-For 不会改变限流的 key
+		{name: "no trusted proxies", proxies: nil, want: "ip:1.2.3.4"},
+		{name: "untrusted proxy", proxies: []string{"10.0.0.0/8"}, want: "ip:1.2.3.4"},
+		{name: "trusted proxy", proxies: []string{"1.2.3.0/24"}, want: "ip:9.9.9.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{TrustedProxies: tt.proxies}
+			engine, err := cfg.newEngine()
+			require.NoError(t, err)
+			engine.Use(mw.ClientIP())
+			engine.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, mw.RateLimitByIP(c)) })
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			r.RemoteAddr = "1.2.3.4:5678"
+			r.Header.Set("X-Forwarded-For", "9.9.9.9")
+			engine.ServeHTTP(w, r)
+			assert.Equal(t, tt.want, w.Body.String())
+		})
+	}
+
+	// 非法的代理地址
+	_, err := (&Config{TrustedProxies: []string{"not-an-ip"}}).newEngine()
+	assert.Error(t, err)
+}
+
+func TestLoginLockoutForgedForwardedFor(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.LockoutOptions.IPMaxAttempts = 3
+	engine, err := cfg.newEngine()
+	require.NoError(t, err)
+	engine.Use(mw.ClientIP())
+	cfg.InstallRESTAPI(engine, storetest.New(), nil, nil, nil, nil, lockout.New(cfg.LockoutOptions), nil, nil, nil, nil)
+
+	login := func(i int) int {
+		body := fmt.Sprintf(`{"username":"attacker%d","password":"wrong-password"}`, i)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.RemoteAddr = "1.2.3.4:5678"
+		// 每次请求伪造不同的 X-Forwarded-For，试图绕过按客户端 IP 的锁定
+		r.Header.Set("X-Forwarded-For", fmt.Sprintf("9.9.9.%d", i))
+		engine.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login(0))
+	assert.Equal(t, http.StatusUnauthorized, login(1))
+	// 失败次数按连接的对端地址累计，伪造的请求头不影响锁定
+	assert.Equal(t, http.StatusTooManyRequests, login(2))
+	assert.Equal(t, http.StatusTooManyRequests, login(3))
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm"
+)
+
+// AccessTokenStore 定义了个人访问令牌模块在 store 层所实现的方法
+type AccessTokenStore interface {
+	Create(ctx context.Context, obj *model.AccessToken) error
+	Delete(ctx context.Context, opts *where.Options) error
+	Get(ctx context.Context, opts *where.Options) (*model.AccessToken, error)
+	List(ctx context.Context, opts *where.Options) (int64, []*model.AccessToken, error)
+
+	AccessTokenExpansion
+}
+
+// AccessTokenExpansion 定义了个人访问令牌操作的附加方法
+type AccessTokenExpansion interface {
+	// Touch 更新令牌的最后使用时间
+	Touch(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+// accessTokenStore 是 AccessTokenStore 接口的实现
+type accessTokenStore struct {
+	store *datastore
+}
+
+// 确保 accessTokenStore 实现了 AccessTokenStore 接口
+var _ AccessTokenStore = (*accessTokenStore)(nil)
+
+// newAccessTokenStore 创建 accessTokenStore 的实例
+func newAccessTokenStore(store *datastore) *accessTokenStore {
+	return &accessTokenStore{store}
+}
+
+// Create 插入一条个人访问令牌记录
+func (s *accessTokenStore) Create(ctx context.Context, obj *model.AccessToken) error {
+	ctx, span := tracing.Start(ctx, "AccessTokenStore.Create")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Create(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to insert access token into database", "err", err, "tokenID", obj.TokenID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete 根据条件删除个人访问令牌记录
+func (s *accessTokenStore) Delete(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "AccessTokenStore.Delete")
+	defer span.End()
+
+	err := s.store.DB(ctx, opts).Delete(new(model.AccessToken)).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(ctx, "Failed to delete access token from database", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Get 根据条件查询个人访问令牌记录
+func (s *accessTokenStore) Get(ctx context.Context, opts *where.Options) (*model.AccessToken, error) {
+	ctx, span := tracing.Start(ctx, "AccessTokenStore.Get")
+	defer span.End()
+
+	var obj model.AccessToken
+	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.ErrAccessTokenNotFound
+		}
+		slog.ErrorContext(ctx, "Failed to retrieve access token from database", "err", err, "conditions", opts)
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return &obj, nil
+}
+
+// List 返回个人访问令牌列表和总数
+// nolint: nonamedreturns
+func (s *accessTokenStore) List(ctx context.Context, opts *where.Options) (count int64, ret []*model.AccessToken, err error) {
+	ctx, span := tracing.Start(ctx, "AccessTokenStore.List")
+	defer span.End()
+
+	err = s.store.DB(ctx, opts).Order("id desc").Find(&ret).Offset(-1).Limit(-1).Count(&count).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list access tokens from database", "err", err, "conditions", opts)
+		err = errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+	return
+}
+
+// Touch 更新令牌的最后使用时间
+func (s *accessTokenStore) Touch(ctx context.Context, id int64, usedAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "AccessTokenStore.Touch")
+	defer span.End()
+
+	err := s.store.DB(ctx).Model(&model.AccessToken{}).Where("id = ?", id).Update("lastUsedAt", usedAt).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to update access token last used time", "err", err, "id", id)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
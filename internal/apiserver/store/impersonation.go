@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm"
+)
+
+// ImpersonationStore 定义了模拟登录审计模块在 store 层所实现的方法
+type ImpersonationStore interface {
+	Create(ctx context.Context, obj *model.Impersonation) error
+	Update(ctx context.Context, obj *model.Impersonation) error
+	Get(ctx context.Context, opts *where.Options) (*model.Impersonation, error)
+	List(ctx context.Context, opts *where.Options) (int64, []*model.Impersonation, error)
+
+	ImpersonationExpansion
+}
+
+// ImpersonationExpansion 定义了模拟登录审计操作的附加方法
+type ImpersonationExpansion interface{}
+
+// impersonationStore 是 ImpersonationStore 接口的实现
+type impersonationStore struct {
+	store *datastore
+}
+
+// 确保 impersonationStore 实现了 ImpersonationStore 接口
+var _ ImpersonationStore = (*impersonationStore)(nil)
+
+// newImpersonationStore 创建 impersonationStore 的实例
+func newImpersonationStore(store *datastore) *impersonationStore {
+	return &impersonationStore{store}
+}
+
+// Create 插入一条模拟登录记录
+func (s *impersonationStore) Create(ctx context.Context, obj *model.Impersonation) error {
+	ctx, span := tracing.Start(ctx, "ImpersonationStore.Create")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Create(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to insert impersonation into database", "err", err, "actorID", obj.ActorID, "userID", obj.UserID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Update 更新模拟登录记录
+func (s *impersonationStore) Update(ctx context.Context, obj *model.Impersonation) error {
+	ctx, span := tracing.Start(ctx, "ImpersonationStore.Update")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Save(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to update impersonation in database", "err", err, "impersonationID", obj.ImpersonationID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Get 根据条件查询模拟登录记录
+func (s *impersonationStore) Get(ctx context.Context, opts *where.Options) (*model.Impersonation, error) {
+	ctx, span := tracing.Start(ctx, "ImpersonationStore.Get")
+	defer span.End()
+
+	var obj model.Impersonation
+	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.ErrImpersonationNotFound
+		}
+		slog.ErrorContext(ctx, "Failed to retrieve impersonation from database", "err", err, "conditions", opts)
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return &obj, nil
+}
+
+// List 返回模拟登录记录列表和总数
+// nolint: nonamedreturns
+func (s *impersonationStore) List(ctx context.Context, opts *where.Options) (count int64, ret []*model.Impersonation, err error) {
+	ctx, span := tracing.Start(ctx, "ImpersonationStore.List")
+	defer span.End()
+
+	err = s.store.DB(ctx, opts).Order("id desc").Find(&ret).Offset(-1).Limit(-1).Count(&count).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list impersonations from database", "err", err, "conditions", opts)
+		err = errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+	return
+}
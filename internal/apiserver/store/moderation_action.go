@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// ModerationActionStore 定义了审核操作记录模块在 store 层所实现的方法.
+// 审核操作记录只允许追加，不允许修改和删除.
+type ModerationActionStore interface {
+	Create(ctx context.Context, obj *model.ModerationAction) error
+	List(ctx context.Context, opts *where.Options) (int64, []*model.ModerationAction, error)
+}
+
+// moderationActionStore 是 ModerationActionStore 接口的实现
+type moderationActionStore struct {
+	store *datastore
+}
+
+// 确保 moderationActionStore 实现了 ModerationActionStore 接口
+var _ ModerationActionStore = (*moderationActionStore)(nil)
+
+// newModerationActionStore 创建 moderationActionStore 的实例
+func newModerationActionStore(store *datastore) *moderationActionStore {
+	return &moderationActionStore{store}
+}
+
+// Create 插入一条审核操作记录
+func (s *moderationActionStore) Create(ctx context.Context, obj *model.ModerationAction) error {
+	ctx, span := tracing.Start(ctx, "ModerationActionStore.Create")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Create(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to insert moderation action into database", "err", err, "action", obj)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// List 返回审核操作记录列表和总数
+// nolint: nonamedreturns
+func (s *moderationActionStore) List(ctx context.Context, opts *where.Options) (count int64, ret []*model.ModerationAction, err error) {
+	ctx, span := tracing.Start(ctx, "ModerationActionStore.List")
+	defer span.End()
+
+	err = s.store.DB(ctx, opts).Order("id desc").Find(&ret).Offset(-1).Limit(-1).Count(&count).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list moderation actions from database", "err", err, "conditions", opts)
+		err = errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+	return
+}
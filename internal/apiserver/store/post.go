@@ -7,6 +7,7 @@ import (
 
 	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
 	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
 	"github.com/onexstack/onexstack/pkg/store/where"
 
 	"gorm.io/gorm"
@@ -41,8 +42,11 @@ func newPostStore(store *datastore) *postStore {
 
 // Create 插入一条帖子记录
 func (s *postStore) Create(ctx context.Context, obj *model.Post) error {
+	ctx, span := tracing.Start(ctx, "PostStore.Create")
+	defer span.End()
+
 	if err := s.store.DB(ctx).Create(&obj); err != nil {
-		slog.Error("Failed to insert post into database", "err", err, "post", obj)
+		slog.ErrorContext(ctx, "Failed to insert post into database", "err", err, "post", obj)
 		return errorsx.ErrDBWrite.WithMessage("Failed to insert post into database")
 	}
 
@@ -51,9 +55,12 @@ func (s *postStore) Create(ctx context.Context, obj *model.Post) error {
 
 // Update 更新帖子数据库记录
 func (s *postStore) Update(ctx context.Context, obj *model.Post) error {
+	ctx, span := tracing.Start(ctx, "PostStore.Update")
+	defer span.End()
+
 	if err := s.store.DB(ctx).Save(obj).Error; err != nil {
-		slog.Error("Failed to update post in database", "err", err, "post", obj)
-		return errorsx.ErrDBWrite.WithMessage(err.Error())
+		slog.ErrorContext(ctx, "Failed to update post in database", "err", err, "post", obj)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
 	}
 
 	return nil
@@ -61,10 +68,13 @@ func (s *postStore) Update(ctx context.Context, obj *model.Post) error {
 
 // Delete 根据条件删除帖子记录
 func (s *postStore) Delete(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "PostStore.Delete")
+	defer span.End()
+
 	err := s.store.DB(ctx, opts).Delete(new(model.Post)).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		slog.Error("Failed to delete post from database", "err", err, "conditions", opts)
-		return errorsx.ErrDBWrite.WithMessage(err.Error())
+		slog.ErrorContext(ctx, "Failed to delete post from database", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
 	}
 
 	return nil
@@ -72,13 +82,16 @@ func (s *postStore) Delete(ctx context.Context, opts *where.Options) error {
 
 // Get 根据条件查询帖子记录
 func (s *postStore) Get(ctx context.Context, opts *where.Options) (*model.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostStore.Get")
+	defer span.End()
+
 	var obj model.Post
 	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
-		slog.Error("Failed to retrieve from database", "err", err, "conditions", opts)
+		slog.ErrorContext(ctx, "Failed to retrieve from database", "err", err, "conditions", opts)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errorsx.ErrPostNotFound
 		}
-		return nil, errorsx.ErrDBRead.WithMessage(err.Error())
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
 	}
 
 	return &obj, nil
@@ -87,10 +100,13 @@ func (s *postStore) Get(ctx context.Context, opts *where.Options) (*model.Post,
 // List 返回帖子列表和总数
 // nolint: nonamedreturns
 func (s *postStore) List(ctx context.Context, opts *where.Options) (count int64, ret []*model.Post, err error) {
+	ctx, span := tracing.Start(ctx, "PostStore.List")
+	defer span.End()
+
 	err = s.store.DB(ctx, opts).Order("id desc").Find(&ret).Offset(-1).Limit(-1).Count(&count).Error
 	if err != nil {
-		slog.Error("Failed to list posts from database", "err", err, "conditions", opts)
-		err = errorsx.ErrDBRead.WithMessage(err.Error())
+		slog.ErrorContext(ctx, "Failed to list posts from database", "err", err, "conditions", opts)
+		err = errorsx.ErrDBRead.WithMessage("%s", err.Error())
 	}
 	return
 }
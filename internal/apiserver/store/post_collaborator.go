@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostCollaboratorStore 定义了博文协作者模块在 store 层所实现的方法
+type PostCollaboratorStore interface {
+	// Upsert 添加协作者，如果协作者已存在则更新其角色
+	Upsert(ctx context.Context, obj *model.PostCollaborator) error
+	Delete(ctx context.Context, opts *where.Options) error
+	Get(ctx context.Context, opts *where.Options) (*model.PostCollaborator, error)
+	List(ctx context.Context, opts *where.Options) (int64, []*model.PostCollaborator, error)
+}
+
+// postCollaboratorStore 是 PostCollaboratorStore 接口的实现
+type postCollaboratorStore struct {
+	store *datastore
+}
+
+// 确保 postCollaboratorStore 实现了 PostCollaboratorStore 接口
+var _ PostCollaboratorStore = (*postCollaboratorStore)(nil)
+
+// newPostCollaboratorStore 创建 postCollaboratorStore 的实例
+func newPostCollaboratorStore(store *datastore) *postCollaboratorStore {
+	return &postCollaboratorStore{store}
+}
+
+// Upsert 插入一条协作者记录，<postID, userID> 冲突时更新角色
+func (s *postCollaboratorStore) Upsert(ctx context.Context, obj *model.PostCollaborator) error {
+	ctx, span := tracing.Start(ctx, "PostCollaboratorStore.Upsert")
+	defer span.End()
+
+	err := s.store.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "postID"}, {Name: "userID"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updatedAt"}),
+	}).Create(obj).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to upsert post collaborator into database", "err", err, "collaborator", obj)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete 根据条件删除协作者记录
+func (s *postCollaboratorStore) Delete(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "PostCollaboratorStore.Delete")
+	defer span.End()
+
+	err := s.store.DB(ctx, opts).Delete(new(model.PostCollaborator)).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(ctx, "Failed to delete post collaborator from database", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Get 根据条件查询协作者记录
+func (s *postCollaboratorStore) Get(ctx context.Context, opts *where.Options) (*model.PostCollaborator, error) {
+	ctx, span := tracing.Start(ctx, "PostCollaboratorStore.Get")
+	defer span.End()
+
+	var obj model.PostCollaborator
+	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.ErrCollaboratorNotFound
+		}
+		slog.ErrorContext(ctx, "Failed to retrieve post collaborator from database", "err", err, "conditions", opts)
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return &obj, nil
+}
+
+// List 返回协作者列表和总数
+// nolint: nonamedreturns
+func (s *postCollaboratorStore) List(ctx context.Context, opts *where.Options) (count int64, ret []*model.PostCollaborator, err error) {
+	ctx, span := tracing.Start(ctx, "PostCollaboratorStore.List")
+	defer span.End()
+
+	err = s.store.DB(ctx, opts).Order("id desc").Find(&ret).Offset(-1).Limit(-1).Count(&count).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list post collaborators from database", "err", err, "conditions", opts)
+		err = errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+	return
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostCounterStore 定义了博文计数模块在 store 层所实现的方法
+type PostCounterStore interface {
+	// IncrViews 按博文 ID 批量累加浏览次数，记录不存在时自动创建，博文已删除时忽略
+	IncrViews(ctx context.Context, deltas map[string]int64) error
+	// Delete 根据条件删除计数记录
+	Delete(ctx context.Context, opts *where.Options) error
+	// Views 批量查询博文的浏览次数，返回 postID 到浏览次数的映射
+	Views(ctx context.Context, postIDs ...string) (map[string]int64, error)
+}
+
+// postCounterStore 是 PostCounterStore 接口的实现
+type postCounterStore struct {
+	store *datastore
+}
+
+// 确保 postCounterStore 实现了 PostCounterStore 接口
+var _ PostCounterStore = (*postCounterStore)(nil)
+
+// newPostCounterStore 创建 postCounterStore 的实例
+func newPostCounterStore(store *datastore) *postCounterStore {
+	return &postCounterStore{store}
+}
+
+// IncrViews 使用 upsert 语句累加浏览次数，避免先查后写带来的并发问题.
+// 浏览次数在内存中缓冲期间博文可能已被删除，只为仍然存在的博文累加，避免为已删除的博文重新创建计数记录
+func (s *postCounterStore) IncrViews(ctx context.Context, deltas map[string]int64) error {
+	ctx, span := tracing.Start(ctx, "PostCounterStore.IncrViews")
+	defer span.End()
+
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	postIDs := make([]string, 0, len(deltas))
+	for postID := range deltas {
+		postIDs = append(postIDs, postID)
+	}
+
+	var existing []string
+	if err := s.store.DB(ctx).Model(new(model.Post)).Where("postID IN ?", postIDs).Pluck("postID", &existing).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to retrieve posts from database", "err", err, "count", len(postIDs))
+		return errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	objs := make([]*model.PostCounter, 0, len(existing))
+	for _, postID := range existing {
+		objs = append(objs, &model.PostCounter{PostID: postID, ViewCount: deltas[postID]})
+	}
+
+	err := s.store.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "postID"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"viewCount": gorm.Expr("viewCount + VALUES(viewCount)"),
+			"updatedAt": gorm.Expr("VALUES(updatedAt)"),
+		}),
+	}).Create(&objs).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to increase post views in database", "err", err, "count", len(deltas))
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Views 批量查询博文浏览次数，没有计数记录的博文不会出现在返回值中
+func (s *postCounterStore) Views(ctx context.Context, postIDs ...string) (map[string]int64, error) {
+	ctx, span := tracing.Start(ctx, "PostCounterStore.Views")
+	defer span.End()
+
+	views := make(map[string]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return views, nil
+	}
+
+	var objs []*model.PostCounter
+	if err := s.store.DB(ctx).Where("postID IN ?", postIDs).Find(&objs).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to retrieve post views from database", "err", err, "postIDs", postIDs)
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	for _, obj := range objs {
+		views[obj.PostID] = obj.ViewCount
+	}
+
+	return views, nil
+}
+
+// Delete 根据条件删除计数记录
+func (s *postCounterStore) Delete(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "PostCounterStore.Delete")
+	defer span.End()
+
+	err := s.store.DB(ctx, opts).Delete(new(model.PostCounter)).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(ctx, "Failed to delete post counter from database", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
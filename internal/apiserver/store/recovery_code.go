@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeStore 定义了两步验证恢复码模块在 store 层所实现的方法
+type RecoveryCodeStore interface {
+	Create(ctx context.Context, objs []*model.RecoveryCode) error
+	Delete(ctx context.Context, opts *where.Options) error
+	Get(ctx context.Context, opts *where.Options) (*model.RecoveryCode, error)
+
+	RecoveryCodeExpansion
+}
+
+// RecoveryCodeExpansion 定义了两步验证恢复码操作的附加方法
+type RecoveryCodeExpansion interface {
+	// MarkUsed 将未使用过的恢复码标记为已使用. 恢复码已经被使用过时返回 false.
+	MarkUsed(ctx context.Context, id int64) (bool, error)
+}
+
+// recoveryCodeStore 是 RecoveryCodeStore 接口的实现
+type recoveryCodeStore struct {
+	store *datastore
+}
+
+// 确保 recoveryCodeStore 实现了 RecoveryCodeStore 接口
+var _ RecoveryCodeStore = (*recoveryCodeStore)(nil)
+
+// newRecoveryCodeStore 创建 recoveryCodeStore 的实例
+func newRecoveryCodeStore(store *datastore) *recoveryCodeStore {
+	return &recoveryCodeStore{store}
+}
+
+// Create 批量插入恢复码记录
+func (s *recoveryCodeStore) Create(ctx context.Context, objs []*model.RecoveryCode) error {
+	ctx, span := tracing.Start(ctx, "RecoveryCodeStore.Create")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Create(objs).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to insert recovery codes into database", "err", err)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete 根据条件删除恢复码记录
+func (s *recoveryCodeStore) Delete(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "RecoveryCodeStore.Delete")
+	defer span.End()
+
+	err := s.store.DB(ctx, opts).Delete(new(model.RecoveryCode)).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(ctx, "Failed to delete recovery codes from database", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Get 根据条件查询恢复码记录
+func (s *recoveryCodeStore) Get(ctx context.Context, opts *where.Options) (*model.RecoveryCode, error) {
+	ctx, span := tracing.Start(ctx, "RecoveryCodeStore.Get")
+	defer span.End()
+
+	var obj model.RecoveryCode
+	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.ErrTOTPCodeInvalid
+		}
+		slog.ErrorContext(ctx, "Failed to retrieve recovery code from database", "err", err)
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return &obj, nil
+}
+
+// MarkUsed 使用条件更新（usedAt IS NULL）实现原子的“检查并标记”
+func (s *recoveryCodeStore) MarkUsed(ctx context.Context, id int64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "RecoveryCodeStore.MarkUsed")
+	defer span.End()
+
+	result := s.store.DB(ctx).Model(new(model.RecoveryCode)).
+		Where("id = ? AND usedAt IS NULL", id).
+		Update("usedAt", time.Now())
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to mark recovery code as used", "err", result.Error, "id", id)
+		return false, errorsx.ErrDBWrite.WithMessage("%s", result.Error.Error())
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenStore 定义了刷新令牌模块在 store 层所实现的方法
+type RefreshTokenStore interface {
+	Create(ctx context.Context, obj *model.RefreshToken) error
+	Get(ctx context.Context, opts *where.Options) (*model.RefreshToken, error)
+
+	RefreshTokenExpansion
+}
+
+// RefreshTokenExpansion 定义了刷新令牌操作的附加方法
+type RefreshTokenExpansion interface {
+	// MarkUsed 将未使用过的令牌标记为已使用. 令牌已经被使用过时返回 false，
+	// 用于在并发刷新时保证同一个令牌只能被成功轮换一次.
+	MarkUsed(ctx context.Context, id int64) (bool, error)
+	// Revoke 吊销所有满足条件且尚未吊销的令牌
+	Revoke(ctx context.Context, opts *where.Options) error
+}
+
+// refreshTokenStore 是 RefreshTokenStore 接口的实现
+type refreshTokenStore struct {
+	store *datastore
+}
+
+// 确保 refreshTokenStore 实现了 RefreshTokenStore 接口
+var _ RefreshTokenStore = (*refreshTokenStore)(nil)
+
+// newRefreshTokenStore 创建 refreshTokenStore 的实例
+func newRefreshTokenStore(store *datastore) *refreshTokenStore {
+	return &refreshTokenStore{store}
+}
+
+// Create 插入一条刷新令牌记录
+func (s *refreshTokenStore) Create(ctx context.Context, obj *model.RefreshToken) error {
+	ctx, span := tracing.Start(ctx, "RefreshTokenStore.Create")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Create(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to insert refresh token into database", "err", err, "userID", obj.UserID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Get 根据条件查询刷新令牌记录
+func (s *refreshTokenStore) Get(ctx context.Context, opts *where.Options) (*model.RefreshToken, error) {
+	ctx, span := tracing.Start(ctx, "RefreshTokenStore.Get")
+	defer span.End()
+
+	var obj model.RefreshToken
+	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.ErrRefreshTokenInvalid
+		}
+		slog.ErrorContext(ctx, "Failed to retrieve refresh token from database", "err", err)
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return &obj, nil
+}
+
+// MarkUsed 使用条件更新（usedAt IS NULL）实现原子的“检查并标记”
+func (s *refreshTokenStore) MarkUsed(ctx context.Context, id int64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "RefreshTokenStore.MarkUsed")
+	defer span.End()
+
+	result := s.store.DB(ctx).Model(new(model.RefreshToken)).
+		Where("id = ? AND usedAt IS NULL", id).
+		Update("usedAt", time.Now())
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to mark refresh token as used", "err", result.Error, "id", id)
+		return false, errorsx.ErrDBWrite.WithMessage("%s", result.Error.Error())
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Revoke 吊销满足条件的令牌
+func (s *refreshTokenStore) Revoke(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "RefreshTokenStore.Revoke")
+	defer span.End()
+
+	err := s.store.DB(ctx, opts).Model(new(model.RefreshToken)).
+		Where("revokedAt IS NULL").
+		Update("revokedAt", time.Now()).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to revoke refresh tokens", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm"
+)
+
+// ReportStore 定义了举报模块在 store 层所实现的方法
+type ReportStore interface {
+	Create(ctx context.Context, obj *model.Report) error
+	Update(ctx context.Context, obj *model.Report) error
+	Get(ctx context.Context, opts *where.Options) (*model.Report, error)
+	List(ctx context.Context, opts *where.Options) (int64, []*model.Report, error)
+}
+
+// reportStore 是 ReportStore 接口的实现
+type reportStore struct {
+	store *datastore
+}
+
+// 确保 reportStore 实现了 ReportStore 接口
+var _ ReportStore = (*reportStore)(nil)
+
+// newReportStore 创建 reportStore 的实例
+func newReportStore(store *datastore) *reportStore {
+	return &reportStore{store}
+}
+
+// Create 插入一条举报记录
+func (s *reportStore) Create(ctx context.Context, obj *model.Report) error {
+	ctx, span := tracing.Start(ctx, "ReportStore.Create")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Create(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to insert report into database", "err", err, "report", obj)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Update 更新举报数据库记录
+func (s *reportStore) Update(ctx context.Context, obj *model.Report) error {
+	ctx, span := tracing.Start(ctx, "ReportStore.Update")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Save(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to update report in database", "err", err, "report", obj)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Get 根据条件查询举报记录
+func (s *reportStore) Get(ctx context.Context, opts *where.Options) (*model.Report, error) {
+	ctx, span := tracing.Start(ctx, "ReportStore.Get")
+	defer span.End()
+
+	var obj model.Report
+	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.ErrReportNotFound
+		}
+		slog.ErrorContext(ctx, "Failed to retrieve report from database", "err", err, "conditions", opts)
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return &obj, nil
+}
+
+// List 返回举报列表和总数
+// nolint: nonamedreturns
+func (s *reportStore) List(ctx context.Context, opts *where.Options) (count int64, ret []*model.Report, err error) {
+	ctx, span := tracing.Start(ctx, "ReportStore.List")
+	defer span.End()
+
+	err = s.store.DB(ctx, opts).Order("id desc").Find(&ret).Offset(-1).Limit(-1).Count(&count).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list reports from database", "err", err, "conditions", opts)
+		err = errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+	return
+}
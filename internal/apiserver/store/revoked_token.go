@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"gorm.io/gorm"
+)
+
+// RevokedTokenStore 定义了已吊销令牌模块在 store 层所实现的方法
+type RevokedTokenStore interface {
+	Create(ctx context.Context, obj *model.RevokedToken) error
+
+	RevokedTokenExpansion
+}
+
+// RevokedTokenExpansion 定义了已吊销令牌操作的附加方法
+type RevokedTokenExpansion interface {
+	// IsRevoked 查询指定 jti 的令牌是否已被吊销
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	// UserRevokedAt 查询用户最近一次吊销所有令牌的时间，从未吊销过时返回零值
+	UserRevokedAt(ctx context.Context, userID string) (time.Time, error)
+	// DeleteExpired 删除已过期的吊销记录
+	DeleteExpired(ctx context.Context) error
+}
+
+// revokedTokenStore 是 RevokedTokenStore 接口的实现
+type revokedTokenStore struct {
+	store *datastore
+}
+
+// 确保 revokedTokenStore 实现了 RevokedTokenStore 接口
+var _ RevokedTokenStore = (*revokedTokenStore)(nil)
+
+// newRevokedTokenStore 创建 revokedTokenStore 的实例
+func newRevokedTokenStore(store *datastore) *revokedTokenStore {
+	return &revokedTokenStore{store}
+}
+
+// Create 插入一条吊销记录
+func (s *revokedTokenStore) Create(ctx context.Context, obj *model.RevokedToken) error {
+	ctx, span := tracing.Start(ctx, "RevokedTokenStore.Create")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Create(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to insert revoked token into database", "err", err, "userID", obj.UserID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// IsRevoked 查询指定 jti 的令牌是否已被吊销
+func (s *revokedTokenStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "RevokedTokenStore.IsRevoked")
+	defer span.End()
+
+	var count int64
+	err := s.store.DB(ctx).Model(new(model.RevokedToken)).
+		Where("jti = ? AND expiresAt > ?", jti, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to query revoked token from database", "err", err, "jti", jti)
+		return false, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return count > 0, nil
+}
+
+// UserRevokedAt 查询用户最近一次吊销所有令牌的时间
+func (s *revokedTokenStore) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	ctx, span := tracing.Start(ctx, "RevokedTokenStore.UserRevokedAt")
+	defer span.End()
+
+	var obj model.RevokedToken
+	err := s.store.DB(ctx).
+		Where("userID = ? AND jti = '' AND expiresAt > ?", userID, time.Now()).
+		Order("revokedAt desc").
+		First(&obj).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		slog.ErrorContext(ctx, "Failed to query user token revocation from database", "err", err, "userID", userID)
+		return time.Time{}, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return obj.RevokedAt, nil
+}
+
+// DeleteExpired 删除已过期的吊销记录
+func (s *revokedTokenStore) DeleteExpired(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "RevokedTokenStore.DeleteExpired")
+	defer span.End()
+
+	err := s.store.DB(ctx).Where("expiresAt <= ?", time.Now()).Delete(new(model.RevokedToken)).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to delete expired revoked tokens from database", "err", err)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
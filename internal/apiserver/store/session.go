@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm"
+)
+
+// SessionStore 定义了登录会话模块在 store 层所实现的方法
+type SessionStore interface {
+	Create(ctx context.Context, obj *model.Session) error
+	Delete(ctx context.Context, opts *where.Options) error
+	Get(ctx context.Context, opts *where.Options) (*model.Session, error)
+	List(ctx context.Context, opts *where.Options) (int64, []*model.Session, error)
+
+	SessionExpansion
+}
+
+// SessionExpansion 定义了登录会话操作的附加方法
+type SessionExpansion interface {
+	// Touch 更新会话的最近活动时间、客户端 IP 及过期时间
+	Touch(ctx context.Context, sessionID string, clientIP string, activeAt time.Time, expiresAt time.Time) error
+	// MarkActive 更新会话的最近活动时间及客户端 IP
+	MarkActive(ctx context.Context, sessionID string, clientIP string, activeAt time.Time) error
+	// Revoke 吊销所有满足条件且尚未吊销的会话
+	Revoke(ctx context.Context, opts *where.Options) error
+	// IsRevoked 查询指定的会话是否已被吊销，会话不存在时返回 false
+	IsRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+// sessionStore 是 SessionStore 接口的实现
+type sessionStore struct {
+	store *datastore
+}
+
+// 确保 sessionStore 实现了 SessionStore 接口
+var _ SessionStore = (*sessionStore)(nil)
+
+// newSessionStore 创建 sessionStore 的实例
+func newSessionStore(store *datastore) *sessionStore {
+	return &sessionStore{store}
+}
+
+// Create 插入一条登录会话记录
+func (s *sessionStore) Create(ctx context.Context, obj *model.Session) error {
+	ctx, span := tracing.Start(ctx, "SessionStore.Create")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Create(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to insert session into database", "err", err, "userID", obj.UserID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete 根据条件删除登录会话记录
+func (s *sessionStore) Delete(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "SessionStore.Delete")
+	defer span.End()
+
+	err := s.store.DB(ctx, opts).Delete(new(model.Session)).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(ctx, "Failed to delete session from database", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Get 根据条件查询登录会话记录
+func (s *sessionStore) Get(ctx context.Context, opts *where.Options) (*model.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionStore.Get")
+	defer span.End()
+
+	var obj model.Session
+	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.ErrSessionNotFound
+		}
+		slog.ErrorContext(ctx, "Failed to retrieve session from database", "err", err, "conditions", opts)
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return &obj, nil
+}
+
+// List 返回登录会话列表和总数
+// nolint: nonamedreturns
+func (s *sessionStore) List(ctx context.Context, opts *where.Options) (count int64, ret []*model.Session, err error) {
+	ctx, span := tracing.Start(ctx, "SessionStore.List")
+	defer span.End()
+
+	err = s.store.DB(ctx, opts).Order("lastActiveAt desc").Find(&ret).Offset(-1).Limit(-1).Count(&count).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list sessions from database", "err", err, "conditions", opts)
+		err = errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+	return
+}
+
+// Touch 更新会话的最近活动时间、客户端 IP 及过期时间
+func (s *sessionStore) Touch(ctx context.Context, sessionID string, clientIP string, activeAt time.Time, expiresAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "SessionStore.Touch")
+	defer span.End()
+
+	err := s.store.DB(ctx).Model(new(model.Session)).
+		Where("sessionID = ?", sessionID).
+		Updates(map[string]any{"clientIP": clientIP, "lastActiveAt": activeAt, "expiresAt": expiresAt}).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to update session last active time", "err", err, "sessionID", sessionID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// MarkActive 更新会话的最近活动时间及客户端 IP
+func (s *sessionStore) MarkActive(ctx context.Context, sessionID string, clientIP string, activeAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "SessionStore.MarkActive")
+	defer span.End()
+
+	err := s.store.DB(ctx).Model(new(model.Session)).
+		Where("sessionID = ?", sessionID).
+		Updates(map[string]any{"clientIP": clientIP, "lastActiveAt": activeAt}).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to update session last active time", "err", err, "sessionID", sessionID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Revoke 吊销所有满足条件且尚未吊销的会话
+func (s *sessionStore) Revoke(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "SessionStore.Revoke")
+	defer span.End()
+
+	err := s.store.DB(ctx, opts).Model(new(model.Session)).
+		Where("revokedAt IS NULL").
+		Update("revokedAt", time.Now()).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to revoke sessions", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// IsRevoked 查询指定的会话是否已被吊销
+func (s *sessionStore) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "SessionStore.IsRevoked")
+	defer span.End()
+
+	var count int64
+	err := s.store.DB(ctx).Model(new(model.Session)).
+		Where("sessionID = ? AND revokedAt IS NOT NULL", sessionID).
+		Count(&count).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to query session revocation from database", "err", err, "sessionID", sessionID)
+		return false, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return count > 0, nil
+}
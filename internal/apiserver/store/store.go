@@ -22,6 +22,18 @@ type IStore interface {
 
 	User() UserStore
 	Post() PostStore
+	PostCounter() PostCounterStore
+	PostCollaborator() PostCollaboratorStore
+	Report() ReportStore
+	ModerationAction() ModerationActionStore
+	RefreshToken() RefreshTokenStore
+	RevokedToken() RevokedTokenStore
+	AccessToken() AccessTokenStore
+	UserTOTP() UserTOTPStore
+	RecoveryCode() RecoveryCodeStore
+	Session() SessionStore
+	ExternalIdentity() ExternalIdentityStore
+	Impersonation() ImpersonationStore
 }
 
 // transactionKey 用于在 context.Context 中存储事务上下文的键
@@ -57,6 +69,9 @@ func (store *datastore) DB(ctx context.Context, wheres ...where.Where) *gorm.DB
 		db = tx
 	}
 
+	// 传入 context，使 GORM 语句的 span 关联到当前请求的链路中，并在请求超过处理时限后取消查询
+	db = db.WithContext(ctx)
+
 	// 遍历所有传入的条件并逐一叠加到数据库查询对象上
 	for _, whr := range wheres {
 		db = whr.Where(db)
@@ -85,3 +100,63 @@ func (store *datastore) User() UserStore {
 func (store *datastore) Post() PostStore {
 	return newPostStore(store)
 }
+
+// PostCounter 返回一个实现了 PostCounterStore 接口的实例
+func (store *datastore) PostCounter() PostCounterStore {
+	return newPostCounterStore(store)
+}
+
+// PostCollaborator 返回一个实现了 PostCollaboratorStore 接口的实例
+func (store *datastore) PostCollaborator() PostCollaboratorStore {
+	return newPostCollaboratorStore(store)
+}
+
+// Report 返回一个实现了 ReportStore 接口的实例
+func (store *datastore) Report() ReportStore {
+	return newReportStore(store)
+}
+
+// ModerationAction 返回一个实现了 ModerationActionStore 接口的实例
+func (store *datastore) ModerationAction() ModerationActionStore {
+	return newModerationActionStore(store)
+}
+
+// RefreshToken 返回一个实现了 RefreshTokenStore 接口的实例
+func (store *datastore) RefreshToken() RefreshTokenStore {
+	return newRefreshTokenStore(store)
+}
+
+// RevokedToken 返回一个实现了 RevokedTokenStore 接口的实例
+func (store *datastore) RevokedToken() RevokedTokenStore {
+	return newRevokedTokenStore(store)
+}
+
+// AccessToken 返回一个实现了 AccessTokenStore 接口的实例
+func (store *datastore) AccessToken() AccessTokenStore {
+	return newAccessTokenStore(store)
+}
+
+// UserTOTP 返回一个实现了 UserTOTPStore 接口的实例
+func (store *datastore) UserTOTP() UserTOTPStore {
+	return newUserTOTPStore(store)
+}
+
+// RecoveryCode 返回一个实现了 RecoveryCodeStore 接口的实例
+func (store *datastore) RecoveryCode() RecoveryCodeStore {
+	return newRecoveryCodeStore(store)
+}
+
+// Session 返回一个实现了 SessionStore 接口的实例
+func (store *datastore) Session() SessionStore {
+	return newSessionStore(store)
+}
+
+// ExternalIdentity 返回一个实现了 ExternalIdentityStore 接口的实例
+func (store *datastore) ExternalIdentity() ExternalIdentityStore {
+	return newExternalIdentityStore(store)
+}
+
+// Impersonation 返回一个实现了 ImpersonationStore 接口的实例
+func (store *datastore) Impersonation() ImpersonationStore {
+	return newImpersonationStore(store)
+}
@@ -0,0 +1,654 @@
+// Package storetest 提供保存在内存中的 store.IStore 实现，供 biz 层的单元测试使用.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/apiserver/store"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/onexstack/onexstack/pkg/store/where"
+)
+
+// Store 是保存在内存中的 store.IStore，只实现 biz 层测试用到的方法，未实现的方法被调用时会 panic.
+// 查询只支持等值过滤条件和 IN 条件，忽略分页及其他子句. 事务中的方法返回错误时，丢弃事务中的修改.
+type Store struct {
+	store.IStore
+
+	// Errs 按 "资源.方法" 注入错误，例如 "User.Get"，对应的方法被调用时直接返回该错误
+	Errs map[string]error
+	// Opts 记录每个方法最近一次调用时传入的查询条件，例如 "Report.Get"
+	Opts map[string]*where.Options
+
+	Users              map[string]*model.User
+	Posts              []*model.Post
+	PostCollaborators  []*model.PostCollaborator
+	Reports            []*model.Report
+	ModerationActions  []*model.ModerationAction
+	RefreshTokens      []*model.RefreshToken
+	RevokedTokens      []*model.RevokedToken
+	AccessTokens       []*model.AccessToken
+	TOTPs              []*model.UserTOTP
+	RecoveryCodes      []*model.RecoveryCode
+	Sessions           []*model.Session
+	ExternalIdentities []*model.ExternalIdentity
+
+	seq int
+}
+
+// 确保 Store 实现了 store.IStore 接口
+var _ store.IStore = (*Store)(nil)
+
+// New 创建包含 users 的 Store
+func New(users ...*model.User) *Store {
+	s := &Store{Errs: make(map[string]error), Opts: make(map[string]*where.Options), Users: make(map[string]*model.User)}
+	for _, userM := range users {
+		s.Users[userM.UserID] = userM
+	}
+	return s
+}
+
+// NextID 返回一个带 prefix 前缀的新资源 ID，例如 post-000001
+func (s *Store) NextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%06d", prefix, s.seq)
+}
+
+// call 记录一次方法调用的查询条件，并返回为该方法注入的错误
+func (s *Store) call(method string, opts *where.Options) error {
+	if opts != nil {
+		s.Opts[method] = opts
+	}
+	return s.Errs[method]
+}
+
+// matches 判断 fields 是否满足 opts 中的所有过滤条件
+func matches(opts *where.Options, fields map[string]any) bool {
+	if opts == nil {
+		return true
+	}
+	for k, v := range opts.Filters {
+		field := fields[k.(string)]
+		if values, ok := v.([]string); ok {
+			if s, ok := field.(string); !ok || !slices.Contains(values, s) {
+				return false
+			}
+			continue
+		}
+		if field != v {
+			return false
+		}
+	}
+	return true
+}
+
+// filter 返回 objs 中满足 opts 过滤条件的记录
+func filter[T any](objs []*T, opts *where.Options, fields func(*T) map[string]any) []*T {
+	var ret []*T
+	for _, obj := range objs {
+		if matches(opts, fields(obj)) {
+			ret = append(ret, obj)
+		}
+	}
+	return ret
+}
+
+// first 返回 objs 中第一条满足 opts 过滤条件的记录的副本，没有满足条件的记录时返回 notFound
+func first[T any](objs []*T, opts *where.Options, fields func(*T) map[string]any, notFound error) (*T, error) {
+	for _, obj := range objs {
+		if matches(opts, fields(obj)) {
+			copied := *obj
+			return &copied, nil
+		}
+	}
+	return nil, notFound
+}
+
+// remove 删除 objs 中满足 opts 过滤条件的记录
+func remove[T any](objs []*T, opts *where.Options, fields func(*T) map[string]any) []*T {
+	return slices.DeleteFunc(objs, func(obj *T) bool { return matches(opts, fields(obj)) })
+}
+
+// clone 深拷贝 objs，用于事务回滚
+func clone[T any](objs []*T) []*T {
+	ret := make([]*T, 0, len(objs))
+	for _, obj := range objs {
+		copied := *obj
+		ret = append(ret, &copied)
+	}
+	return ret
+}
+
+// TX 在 fn 返回错误时丢弃事务中的修改. 回滚后记录会被替换为事务开始时的副本，测试需要重新从 Store 中读取
+func (s *Store) TX(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := *s
+	snapshot.Users = make(map[string]*model.User, len(s.Users))
+	for userID, userM := range s.Users {
+		copied := *userM
+		snapshot.Users[userID] = &copied
+	}
+	snapshot.Posts = clone(s.Posts)
+	snapshot.PostCollaborators = clone(s.PostCollaborators)
+	snapshot.Reports = clone(s.Reports)
+	snapshot.ModerationActions = clone(s.ModerationActions)
+	snapshot.RefreshTokens = clone(s.RefreshTokens)
+	snapshot.RevokedTokens = clone(s.RevokedTokens)
+	snapshot.AccessTokens = clone(s.AccessTokens)
+	snapshot.TOTPs = clone(s.TOTPs)
+	snapshot.RecoveryCodes = clone(s.RecoveryCodes)
+	snapshot.Sessions = clone(s.Sessions)
+	snapshot.ExternalIdentities = clone(s.ExternalIdentities)
+
+	if err := fn(ctx); err != nil {
+		// 注入的错误和查询条件不随事务回滚
+		snapshot.Errs, snapshot.Opts = s.Errs, s.Opts
+		*s = snapshot
+		return err
+	}
+	return nil
+}
+
+func (s *Store) User() store.UserStore                         { return &userStore{s: s} }
+func (s *Store) Post() store.PostStore                         { return &postStore{s: s} }
+func (s *Store) PostCounter() store.PostCounterStore           { return &postCounterStore{s: s} }
+func (s *Store) PostCollaborator() store.PostCollaboratorStore { return &postCollaboratorStore{s: s} }
+func (s *Store) Report() store.ReportStore                     { return &reportStore{s: s} }
+func (s *Store) ModerationAction() store.ModerationActionStore { return &moderationActionStore{s: s} }
+func (s *Store) RefreshToken() store.RefreshTokenStore         { return &refreshTokenStore{s: s} }
+func (s *Store) RevokedToken() store.RevokedTokenStore         { return &revokedTokenStore{s: s} }
+func (s *Store) AccessToken() store.AccessTokenStore           { return &accessTokenStore{s: s} }
+func (s *Store) UserTOTP() store.UserTOTPStore                 { return &userTOTPStore{s: s} }
+func (s *Store) RecoveryCode() store.RecoveryCodeStore         { return &recoveryCodeStore{s: s} }
+func (s *Store) Session() store.SessionStore                   { return &sessionStore{s: s} }
+func (s *Store) ExternalIdentity() store.ExternalIdentityStore { return &externalIdentityStore{s: s} }
+
+type userStore struct {
+	store.UserStore
+	s *Store
+}
+
+func userFields(userM *model.User) map[string]any {
+	return map[string]any{"userID": userM.UserID, "username": userM.Username, "email": userM.Email, "role": userM.Role, "status": userM.Status}
+}
+
+func (u *userStore) Create(ctx context.Context, obj *model.User) error {
+	if err := u.s.call("User.Create", nil); err != nil {
+		return err
+	}
+	if obj.UserID == "" {
+		obj.UserID = u.s.NextID("user")
+	}
+	u.s.Users[obj.UserID] = obj
+	return nil
+}
+
+func (u *userStore) Update(ctx context.Context, obj *model.User) error {
+	if err := u.s.call("User.Update", nil); err != nil {
+		return err
+	}
+	u.s.Users[obj.UserID] = obj
+	return nil
+}
+
+func (u *userStore) Delete(ctx context.Context, opts *where.Options) error {
+	if err := u.s.call("User.Delete", opts); err != nil {
+		return err
+	}
+	maps.DeleteFunc(u.s.Users, func(_ string, userM *model.User) bool { return matches(opts, userFields(userM)) })
+	return nil
+}
+
+func (u *userStore) Get(ctx context.Context, opts *where.Options) (*model.User, error) {
+	if err := u.s.call("User.Get", opts); err != nil {
+		return nil, err
+	}
+	return first(slices.Collect(maps.Values(u.s.Users)), opts, userFields, errorsx.ErrUserNotFound)
+}
+
+func (u *userStore) List(ctx context.Context, opts *where.Options) (int64, []*model.User, error) {
+	if err := u.s.call("User.List", opts); err != nil {
+		return 0, nil, err
+	}
+	ret := filter(slices.Collect(maps.Values(u.s.Users)), opts, userFields)
+	return int64(len(ret)), ret, nil
+}
+
+type postStore struct {
+	store.PostStore
+	s *Store
+}
+
+func postFields(postM *model.Post) map[string]any {
+	return map[string]any{"postID": postM.PostID, "userID": postM.UserID}
+}
+
+func (p *postStore) Create(ctx context.Context, obj *model.Post) error {
+	if err := p.s.call("Post.Create", nil); err != nil {
+		return err
+	}
+	obj.PostID = p.s.NextID("post")
+	p.s.Posts = append(p.s.Posts, obj)
+	return nil
+}
+
+func (p *postStore) Delete(ctx context.Context, opts *where.Options) error {
+	if err := p.s.call("Post.Delete", opts); err != nil {
+		return err
+	}
+	p.s.Posts = remove(p.s.Posts, opts, postFields)
+	return nil
+}
+
+func (p *postStore) Get(ctx context.Context, opts *where.Options) (*model.Post, error) {
+	if err := p.s.call("Post.Get", opts); err != nil {
+		return nil, err
+	}
+	return first(p.s.Posts, opts, postFields, errorsx.ErrPostNotFound)
+}
+
+func (p *postStore) List(ctx context.Context, opts *where.Options) (int64, []*model.Post, error) {
+	if err := p.s.call("Post.List", opts); err != nil {
+		return 0, nil, err
+	}
+	ret := filter(p.s.Posts, opts, postFields)
+	return int64(len(ret)), ret, nil
+}
+
+type postCounterStore struct {
+	store.PostCounterStore
+	s *Store
+}
+
+func (pc *postCounterStore) Delete(ctx context.Context, opts *where.Options) error {
+	return pc.s.call("PostCounter.Delete", opts)
+}
+
+func (pc *postCounterStore) Views(ctx context.Context, postIDs ...string) (map[string]int64, error) {
+	if err := pc.s.call("PostCounter.Views", nil); err != nil {
+		return nil, err
+	}
+	return map[string]int64{}, nil
+}
+
+type postCollaboratorStore struct {
+	store.PostCollaboratorStore
+	s *Store
+}
+
+func postCollaboratorFields(collaboratorM *model.PostCollaborator) map[string]any {
+	return map[string]any{"postID": collaboratorM.PostID, "userID": collaboratorM.UserID, "role": collaboratorM.Role}
+}
+
+func (pc *postCollaboratorStore) Delete(ctx context.Context, opts *where.Options) error {
+	if err := pc.s.call("PostCollaborator.Delete", opts); err != nil {
+		return err
+	}
+	pc.s.PostCollaborators = remove(pc.s.PostCollaborators, opts, postCollaboratorFields)
+	return nil
+}
+
+func (pc *postCollaboratorStore) Get(ctx context.Context, opts *where.Options) (*model.PostCollaborator, error) {
+	if err := pc.s.call("PostCollaborator.Get", opts); err != nil {
+		return nil, err
+	}
+	return first(pc.s.PostCollaborators, opts, postCollaboratorFields, errorsx.ErrCollaboratorNotFound)
+}
+
+type reportStore struct {
+	store.ReportStore
+	s *Store
+}
+
+func reportFields(reportM *model.Report) map[string]any {
+	return map[string]any{
+		"reportID":   reportM.ReportID,
+		"targetType": reportM.TargetType,
+		"targetID":   reportM.TargetID,
+		"source":     reportM.Source,
+		"status":     reportM.Status,
+	}
+}
+
+func (r *reportStore) Create(ctx context.Context, obj *model.Report) error {
+	if err := r.s.call("Report.Create", nil); err != nil {
+		return err
+	}
+	obj.ReportID = r.s.NextID("report")
+	r.s.Reports = append(r.s.Reports, obj)
+	return nil
+}
+
+func (r *reportStore) Update(ctx context.Context, obj *model.Report) error {
+	if err := r.s.call("Report.Update", nil); err != nil {
+		return err
+	}
+	for i, reportM := range r.s.Reports {
+		if reportM.ReportID == obj.ReportID {
+			r.s.Reports[i] = obj
+		}
+	}
+	return nil
+}
+
+func (r *reportStore) Get(ctx context.Context, opts *where.Options) (*model.Report, error) {
+	if err := r.s.call("Report.Get", opts); err != nil {
+		return nil, err
+	}
+	return first(r.s.Reports, opts, reportFields, errorsx.ErrReportNotFound)
+}
+
+type moderationActionStore struct {
+	store.ModerationActionStore
+	s *Store
+}
+
+func (m *moderationActionStore) Create(ctx context.Context, obj *model.ModerationAction) error {
+	if err := m.s.call("ModerationAction.Create", nil); err != nil {
+		return err
+	}
+	m.s.ModerationActions = append(m.s.ModerationActions, obj)
+	return nil
+}
+
+type refreshTokenStore struct {
+	store.RefreshTokenStore
+	s *Store
+}
+
+func refreshTokenFields(tokenM *model.RefreshToken) map[string]any {
+	return map[string]any{"userID": tokenM.UserID, "familyID": tokenM.FamilyID, "tokenHash": tokenM.TokenHash}
+}
+
+func (r *refreshTokenStore) Create(ctx context.Context, obj *model.RefreshToken) error {
+	if err := r.s.call("RefreshToken.Create", nil); err != nil {
+		return err
+	}
+	obj.ID = int64(len(r.s.RefreshTokens) + 1)
+	r.s.RefreshTokens = append(r.s.RefreshTokens, obj)
+	return nil
+}
+
+func (r *refreshTokenStore) Get(ctx context.Context, opts *where.Options) (*model.RefreshToken, error) {
+	if err := r.s.call("RefreshToken.Get", opts); err != nil {
+		return nil, err
+	}
+	return first(r.s.RefreshTokens, opts, refreshTokenFields, errorsx.ErrRefreshTokenInvalid)
+}
+
+func (r *refreshTokenStore) MarkUsed(ctx context.Context, id int64) (bool, error) {
+	if err := r.s.call("RefreshToken.MarkUsed", nil); err != nil {
+		return false, err
+	}
+	tokenM := r.s.RefreshTokens[id-1]
+	if tokenM.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	tokenM.UsedAt = &now
+	return true, nil
+}
+
+func (r *refreshTokenStore) Revoke(ctx context.Context, opts *where.Options) error {
+	if err := r.s.call("RefreshToken.Revoke", opts); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, tokenM := range filter(r.s.RefreshTokens, opts, refreshTokenFields) {
+		if tokenM.RevokedAt == nil {
+			tokenM.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+type revokedTokenStore struct {
+	store.RevokedTokenStore
+	s *Store
+}
+
+func (rt *revokedTokenStore) Create(ctx context.Context, obj *model.RevokedToken) error {
+	if err := rt.s.call("RevokedToken.Create", nil); err != nil {
+		return err
+	}
+	rt.s.RevokedTokens = append(rt.s.RevokedTokens, obj)
+	return nil
+}
+
+func (rt *revokedTokenStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	if err := rt.s.call("RevokedToken.IsRevoked", nil); err != nil {
+		return false, err
+	}
+	for _, tokenM := range rt.s.RevokedTokens {
+		if tokenM.JTI != "" && tokenM.JTI == jti {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (rt *revokedTokenStore) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	if err := rt.s.call("RevokedToken.UserRevokedAt", nil); err != nil {
+		return time.Time{}, err
+	}
+	var revokedAt time.Time
+	for _, tokenM := range rt.s.RevokedTokens {
+		if tokenM.JTI == "" && tokenM.UserID == userID && tokenM.RevokedAt.After(revokedAt) {
+			revokedAt = tokenM.RevokedAt.Truncate(time.Millisecond)
+		}
+	}
+	return revokedAt, nil
+}
+
+func (rt *revokedTokenStore) DeleteExpired(ctx context.Context) error {
+	return rt.s.call("RevokedToken.DeleteExpired", nil)
+}
+
+type accessTokenStore struct {
+	store.AccessTokenStore
+	s *Store
+}
+
+func accessTokenFields(tokenM *model.AccessToken) map[string]any {
+	return map[string]any{"userID": tokenM.UserID, "tokenID": tokenM.TokenID, "tokenHash": tokenM.TokenHash}
+}
+
+func (a *accessTokenStore) Create(ctx context.Context, obj *model.AccessToken) error {
+	if err := a.s.call("AccessToken.Create", nil); err != nil {
+		return err
+	}
+	obj.ID = int64(len(a.s.AccessTokens) + 1)
+	obj.TokenID = a.s.NextID("pat")
+	a.s.AccessTokens = append(a.s.AccessTokens, obj)
+	return nil
+}
+
+func (a *accessTokenStore) Delete(ctx context.Context, opts *where.Options) error {
+	if err := a.s.call("AccessToken.Delete", opts); err != nil {
+		return err
+	}
+	a.s.AccessTokens = remove(a.s.AccessTokens, opts, accessTokenFields)
+	return nil
+}
+
+func (a *accessTokenStore) Get(ctx context.Context, opts *where.Options) (*model.AccessToken, error) {
+	if err := a.s.call("AccessToken.Get", opts); err != nil {
+		return nil, err
+	}
+	return first(a.s.AccessTokens, opts, accessTokenFields, errorsx.ErrAccessTokenNotFound)
+}
+
+func (a *accessTokenStore) Touch(ctx context.Context, id int64, usedAt time.Time) error {
+	if err := a.s.call("AccessToken.Touch", nil); err != nil {
+		return err
+	}
+	for _, tokenM := range a.s.AccessTokens {
+		if tokenM.ID == id {
+			tokenM.LastUsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+type userTOTPStore struct {
+	store.UserTOTPStore
+	s *Store
+}
+
+func userTOTPFields(totpM *model.UserTOTP) map[string]any {
+	return map[string]any{"userID": totpM.UserID}
+}
+
+func (ut *userTOTPStore) Delete(ctx context.Context, opts *where.Options) error {
+	if err := ut.s.call("UserTOTP.Delete", opts); err != nil {
+		return err
+	}
+	ut.s.TOTPs = remove(ut.s.TOTPs, opts, userTOTPFields)
+	return nil
+}
+
+func (ut *userTOTPStore) Get(ctx context.Context, opts *where.Options) (*model.UserTOTP, error) {
+	if err := ut.s.call("UserTOTP.Get", opts); err != nil {
+		return nil, err
+	}
+	return first(ut.s.TOTPs, opts, userTOTPFields, errorsx.ErrTOTPNotEnabled)
+}
+
+type recoveryCodeStore struct {
+	store.RecoveryCodeStore
+	s *Store
+}
+
+func recoveryCodeFields(codeM *model.RecoveryCode) map[string]any {
+	return map[string]any{"userID": codeM.UserID, "codeHash": codeM.CodeHash}
+}
+
+func (rc *recoveryCodeStore) Delete(ctx context.Context, opts *where.Options) error {
+	if err := rc.s.call("RecoveryCode.Delete", opts); err != nil {
+		return err
+	}
+	rc.s.RecoveryCodes = remove(rc.s.RecoveryCodes, opts, recoveryCodeFields)
+	return nil
+}
+
+func (rc *recoveryCodeStore) Get(ctx context.Context, opts *where.Options) (*model.RecoveryCode, error) {
+	if err := rc.s.call("RecoveryCode.Get", opts); err != nil {
+		return nil, err
+	}
+	return first(rc.s.RecoveryCodes, opts, recoveryCodeFields, errorsx.ErrTOTPCodeInvalid)
+}
+
+func (rc *recoveryCodeStore) MarkUsed(ctx context.Context, id int64) (bool, error) {
+	if err := rc.s.call("RecoveryCode.MarkUsed", nil); err != nil {
+		return false, err
+	}
+	for _, codeM := range rc.s.RecoveryCodes {
+		if codeM.ID == id {
+			if codeM.UsedAt != nil {
+				return false, nil
+			}
+			now := time.Now()
+			codeM.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type sessionStore struct {
+	store.SessionStore
+	s *Store
+}
+
+func sessionFields(sessionM *model.Session) map[string]any {
+	return map[string]any{"sessionID": sessionM.SessionID, "userID": sessionM.UserID}
+}
+
+func (ss *sessionStore) Create(ctx context.Context, obj *model.Session) error {
+	if err := ss.s.call("Session.Create", nil); err != nil {
+		return err
+	}
+	obj.SessionID = ss.s.NextID("session")
+	ss.s.Sessions = append(ss.s.Sessions, obj)
+	return nil
+}
+
+func (ss *sessionStore) Delete(ctx context.Context, opts *where.Options) error {
+	if err := ss.s.call("Session.Delete", opts); err != nil {
+		return err
+	}
+	ss.s.Sessions = remove(ss.s.Sessions, opts, sessionFields)
+	return nil
+}
+
+func (ss *sessionStore) Touch(ctx context.Context, sessionID string, clientIP string, activeAt time.Time, expiresAt time.Time) error {
+	if err := ss.s.call("Session.Touch", nil); err != nil {
+		return err
+	}
+	for _, sessionM := range ss.s.Sessions {
+		if sessionM.SessionID == sessionID {
+			sessionM.ClientIP, sessionM.LastActiveAt, sessionM.ExpiresAt = clientIP, activeAt, expiresAt
+		}
+	}
+	return nil
+}
+
+func (ss *sessionStore) Revoke(ctx context.Context, opts *where.Options) error {
+	if err := ss.s.call("Session.Revoke", opts); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, sessionM := range filter(ss.s.Sessions, opts, sessionFields) {
+		if sessionM.RevokedAt == nil {
+			sessionM.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (ss *sessionStore) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	if err := ss.s.call("Session.IsRevoked", nil); err != nil {
+		return false, err
+	}
+	for _, sessionM := range ss.s.Sessions {
+		if sessionM.SessionID == sessionID {
+			return sessionM.RevokedAt != nil, nil
+		}
+	}
+	return false, nil
+}
+
+type externalIdentityStore struct {
+	store.ExternalIdentityStore
+	s *Store
+}
+
+func externalIdentityFields(identityM *model.ExternalIdentity) map[string]any {
+	return map[string]any{"userID": identityM.UserID, "provider": identityM.Provider, "subject": identityM.Subject}
+}
+
+func (e *externalIdentityStore) Delete(ctx context.Context, opts *where.Options) error {
+	if err := e.s.call("ExternalIdentity.Delete", opts); err != nil {
+		return err
+	}
+	e.s.ExternalIdentities = remove(e.s.ExternalIdentities, opts, externalIdentityFields)
+	return nil
+}
+
+func (e *externalIdentityStore) Get(ctx context.Context, opts *where.Options) (*model.ExternalIdentity, error) {
+	if err := e.s.call("ExternalIdentity.Get", opts); err != nil {
+		return nil, err
+	}
+	return first(e.s.ExternalIdentities, opts, externalIdentityFields, errorsx.ErrIdentityNotFound)
+}
+
+func (e *externalIdentityStore) List(ctx context.Context, opts *where.Options) (int64, []*model.ExternalIdentity, error) {
+	if err := e.s.call("ExternalIdentity.List", opts); err != nil {
+		return 0, nil, err
+	}
+	ret := filter(e.s.ExternalIdentities, opts, externalIdentityFields)
+	return int64(len(ret)), ret, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
 	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
 	"github.com/onexstack/onexstack/pkg/store/where"
 	"gorm.io/gorm"
 )
@@ -40,9 +41,12 @@ func newUserStore(store *datastore) *userStore {
 
 // Create 插入一条用户记录
 func (s *userStore) Create(ctx context.Context, obj *model.User) error {
+	ctx, span := tracing.Start(ctx, "UserStore.Create")
+	defer span.End()
+
 	if err := s.store.DB(ctx).Create(&obj).Error; err != nil {
-		slog.Error("Failed to insert user into database", "err", err, "user", obj)
-		return errorsx.ErrDBWrite.WithMessage(err.Error())
+		slog.ErrorContext(ctx, "Failed to insert user into database", "err", err, "user", obj)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
 	}
 
 	return nil
@@ -50,9 +54,12 @@ func (s *userStore) Create(ctx context.Context, obj *model.User) error {
 
 // Update 更新用户数据库记录
 func (s *userStore) Update(ctx context.Context, obj *model.User) error {
+	ctx, span := tracing.Start(ctx, "UserStore.Update")
+	defer span.End()
+
 	if err := s.store.DB(ctx).Save(obj).Error; err != nil {
-		slog.Error("Failed to update user in database", "err", err, "user", obj)
-		return errorsx.ErrDBWrite.WithMessage(err.Error())
+		slog.ErrorContext(ctx, "Failed to update user in database", "err", err, "user", obj)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
 	}
 
 	return nil
@@ -60,10 +67,13 @@ func (s *userStore) Update(ctx context.Context, obj *model.User) error {
 
 // Delete 根据条件删除用户记录
 func (s *userStore) Delete(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "UserStore.Delete")
+	defer span.End()
+
 	err := s.store.DB(ctx, opts).Delete(new(model.User)).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		slog.Error("Failed to delete user from database", "err", err, "conditions", opts)
-		return errorsx.ErrDBWrite.WithMessage(err.Error())
+		slog.ErrorContext(ctx, "Failed to delete user from database", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
 	}
 
 	return nil
@@ -71,12 +81,16 @@ func (s *userStore) Delete(ctx context.Context, opts *where.Options) error {
 
 // Get 根据条件查询用户记录
 func (s *userStore) Get(ctx context.Context, opts *where.Options) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.Get")
+	defer span.End()
+
 	var obj model.User
 	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
-		slog.Error("Failed to retrieve user from database", "err", err, "conditions", opts)
+		slog.ErrorContext(ctx, "Failed to retrieve user from database", "err", err, "conditions", opts)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errorsx.ErrDBRead.WithMessage(err.Error())
+			return nil, errorsx.ErrUserNotFound
 		}
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
 	}
 
 	return &obj, nil
@@ -85,10 +99,13 @@ func (s *userStore) Get(ctx context.Context, opts *where.Options) (*model.User,
 // List 返回用户列表和总数
 // nolint: nonamedreturns
 func (s *userStore) List(ctx context.Context, opts *where.Options) (count int64, ret []*model.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserStore.List")
+	defer span.End()
+
 	err = s.store.DB(ctx, opts).Order("id desc").Find(&ret).Offset(-1).Limit(-1).Count(&count).Error
 	if err != nil {
-		slog.Error("Failed to list users from database", "err", err, "conditions", opts)
-		err = errorsx.ErrDBRead.WithMessage(err.Error())
+		slog.ErrorContext(ctx, "Failed to list users from database", "err", err, "conditions", opts)
+		err = errorsx.ErrDBRead.WithMessage("%s", err.Error())
 	}
 
 	return
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/apiserver/model"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/onexstack/onexstack/pkg/store/where"
+	"gorm.io/gorm"
+)
+
+// UserTOTPStore 定义了 TOTP 两步验证模块在 store 层所实现的方法
+type UserTOTPStore interface {
+	Create(ctx context.Context, obj *model.UserTOTP) error
+	Update(ctx context.Context, obj *model.UserTOTP) error
+	Delete(ctx context.Context, opts *where.Options) error
+	Get(ctx context.Context, opts *where.Options) (*model.UserTOTP, error)
+
+	UserTOTPExpansion
+}
+
+// UserTOTPExpansion 定义了 TOTP 两步验证操作的附加方法
+type UserTOTPExpansion interface {
+	// Advance 将最近一次验证通过的时间步更新为 step. step 不大于已记录的时间步时返回 false，
+	// 用于保证同一个验证码只能使用一次.
+	Advance(ctx context.Context, id int64, step int64) (bool, error)
+}
+
+// userTOTPStore 是 UserTOTPStore 接口的实现
+type userTOTPStore struct {
+	store *datastore
+}
+
+// 确保 userTOTPStore 实现了 UserTOTPStore 接口
+var _ UserTOTPStore = (*userTOTPStore)(nil)
+
+// newUserTOTPStore 创建 userTOTPStore 的实例
+func newUserTOTPStore(store *datastore) *userTOTPStore {
+	return &userTOTPStore{store}
+}
+
+// Create 插入一条 TOTP 记录
+func (s *userTOTPStore) Create(ctx context.Context, obj *model.UserTOTP) error {
+	ctx, span := tracing.Start(ctx, "UserTOTPStore.Create")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Create(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to insert user totp into database", "err", err, "userID", obj.UserID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Update 更新 TOTP 记录
+func (s *userTOTPStore) Update(ctx context.Context, obj *model.UserTOTP) error {
+	ctx, span := tracing.Start(ctx, "UserTOTPStore.Update")
+	defer span.End()
+
+	if err := s.store.DB(ctx).Save(obj).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to update user totp in database", "err", err, "userID", obj.UserID)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Delete 根据条件删除 TOTP 记录
+func (s *userTOTPStore) Delete(ctx context.Context, opts *where.Options) error {
+	ctx, span := tracing.Start(ctx, "UserTOTPStore.Delete")
+	defer span.End()
+
+	err := s.store.DB(ctx, opts).Delete(new(model.UserTOTP)).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(ctx, "Failed to delete user totp from database", "err", err, "conditions", opts)
+		return errorsx.ErrDBWrite.WithMessage("%s", err.Error())
+	}
+
+	return nil
+}
+
+// Get 根据条件查询 TOTP 记录
+func (s *userTOTPStore) Get(ctx context.Context, opts *where.Options) (*model.UserTOTP, error) {
+	ctx, span := tracing.Start(ctx, "UserTOTPStore.Get")
+	defer span.End()
+
+	var obj model.UserTOTP
+	if err := s.store.DB(ctx, opts).First(&obj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorsx.ErrTOTPNotEnabled
+		}
+		slog.ErrorContext(ctx, "Failed to retrieve user totp from database", "err", err, "conditions", opts)
+		return nil, errorsx.ErrDBRead.WithMessage("%s", err.Error())
+	}
+
+	return &obj, nil
+}
+
+// Advance 使用条件更新（lastUsedStep < step）实现原子的“检查并标记”
+func (s *userTOTPStore) Advance(ctx context.Context, id int64, step int64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UserTOTPStore.Advance")
+	defer span.End()
+
+	result := s.store.DB(ctx).Model(new(model.UserTOTP)).
+		Where("id = ? AND lastUsedStep < ?", id, step).
+		Update("lastUsedStep", step)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to advance totp step", "err", result.Error, "id", id)
+		return false, errorsx.ErrDBWrite.WithMessage("%s", result.Error.Error())
+	}
+
+	return result.RowsAffected == 1, nil
+}
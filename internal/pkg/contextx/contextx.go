@@ -2,6 +2,8 @@ package contextx
 
 import (
 	"context"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // 定义用于上下文的键
@@ -12,6 +14,20 @@ type (
 	userIDKey struct{}
 	// usernameKey 定义用户名的上下文键.
 	usernameKey struct{}
+	// clientIPKey 定义客户端 IP 的上下文键.
+	clientIPKey struct{}
+	// tokenIDKey 定义 token 唯一标识（jti）的上下文键.
+	tokenIDKey struct{}
+	// roleKey 定义用户角色的上下文键.
+	roleKey struct{}
+	// scopesKey 定义个人访问令牌权限范围的上下文键.
+	scopesKey struct{}
+	// sessionIDKey 定义登录会话 ID 的上下文键.
+	sessionIDKey struct{}
+	// userAgentKey 定义客户端 User-Agent 的上下文键.
+	userAgentKey struct{}
+	// actorIDKey 定义模拟登录时实际操作者用户 ID 的上下文键.
+	actorIDKey struct{}
 )
 
 // WithRequestID 将请求 ID 存放到上下文中
@@ -46,3 +62,91 @@ func Username(ctx context.Context) string {
 	username, _ := ctx.Value(usernameKey{}).(string)
 	return username
 }
+
+// WithClientIP 将客户端 IP 存放到上下文中.
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, clientIP)
+}
+
+// ClientIP 从上下文中提取客户端 IP.
+func ClientIP(ctx context.Context) string {
+	clientIP, _ := ctx.Value(clientIPKey{}).(string)
+	return clientIP
+}
+
+// WithTokenID 将 token 唯一标识（jti）存放到上下文中.
+func WithTokenID(ctx context.Context, tokenID string) context.Context {
+	return context.WithValue(ctx, tokenIDKey{}, tokenID)
+}
+
+// TokenID 从上下文中提取 token 唯一标识（jti）.
+func TokenID(ctx context.Context) string {
+	tokenID, _ := ctx.Value(tokenIDKey{}).(string)
+	return tokenID
+}
+
+// WithRole 将用户角色存放到上下文中.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// Role 从上下文中提取用户角色.
+func Role(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey{}).(string)
+	return role
+}
+
+// WithScopes 将个人访问令牌的权限范围存放到上下文中.
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// Scopes 从上下文中提取个人访问令牌的权限范围.
+// 返回 nil 表示请求使用的是登录获得的 token，不受权限范围限制.
+func Scopes(ctx context.Context) []string {
+	scopes, _ := ctx.Value(scopesKey{}).([]string)
+	return scopes
+}
+
+// WithSessionID 将登录会话 ID 存放到上下文中
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// SessionID 从上下文中提取登录会话 ID
+func SessionID(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionIDKey{}).(string)
+	return sessionID
+}
+
+// WithUserAgent 将客户端 User-Agent 存放到上下文中
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, userAgentKey{}, userAgent)
+}
+
+// UserAgent 从上下文中提取客户端 User-Agent
+func UserAgent(ctx context.Context) string {
+	userAgent, _ := ctx.Value(userAgentKey{}).(string)
+	return userAgent
+}
+
+// WithActorID 将模拟登录时实际操作者（管理员）的用户 ID 存放到上下文中
+func WithActorID(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorIDKey{}, actorID)
+}
+
+// ActorID 从上下文中提取模拟登录时实际操作者的用户 ID. 返回空字符串表示当前请求不是模拟登录，
+// 否则 UserID 返回的是被模拟的用户 ID.
+func ActorID(ctx context.Context) string {
+	actorID, _ := ctx.Value(actorIDKey{}).(string)
+	return actorID
+}
+
+// TraceID 从上下文中提取当前 span 所属链路的 trace ID，上下文中没有有效的 span 时返回空字符串
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
@@ -0,0 +1,54 @@
+package contextx
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogAttrs 返回上下文中可用于日志的字段：请求 ID、trace ID、用户 ID 及已认证调用方的其他信息
+func LogAttrs(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+	if requestID := RequestID(ctx); requestID != "" {
+		attrs = append(attrs, slog.String("requestID", requestID))
+	}
+	if traceID := TraceID(ctx); traceID != "" {
+		attrs = append(attrs, slog.String("traceID", traceID))
+	}
+	if userID := UserID(ctx); userID != "" {
+		attrs = append(attrs, slog.String("userID", userID))
+	}
+	if p := PrincipalFrom(ctx); p != nil {
+		attrs = append(attrs, slog.Any("principal", p))
+	}
+	return attrs
+}
+
+// logHandler 包装 slog.Handler，为每条日志追加上下文中的请求 ID、trace ID、用户 ID 及已认证的调用方.
+// 只有使用 slog.InfoContext 等带 context 的函数输出的日志才能取到这些字段.
+type logHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler 创建一个为每条日志追加上下文字段的 slog.Handler
+func NewLogHandler(h slog.Handler) slog.Handler {
+	return &logHandler{Handler: h}
+}
+
+// Handle 实现 slog.Handler 接口
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := LogAttrs(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs 实现 slog.Handler 接口
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup 实现 slog.Handler 接口
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,37 @@
+package contextx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil)))
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	}))
+	ctx = WithPrincipal(ctx, &Principal{UserID: "user-000001", Username: "alice", Roles: []string{"user"}, AuthMethod: "jwt"})
+	logger.InfoContext(ctx, "hello")
+
+	out := buf.String()
+	assert.Contains(t, out, "requestID=req-1")
+	assert.Contains(t, out, "traceID=4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Contains(t, out, "userID=user-000001")
+	assert.NotContains(t, out, "principal.userID")
+	assert.Contains(t, out, "principal.username=alice")
+	assert.Contains(t, out, "principal.authMethod=jwt")
+	assert.NotContains(t, out, "principal.actorID")
+
+	assert.Equal(t, "alice", Username(ctx))
+	assert.Equal(t, "user", Role(ctx))
+	assert.Nil(t, Scopes(ctx))
+}
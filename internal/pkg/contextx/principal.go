@@ -0,0 +1,82 @@
+package contextx
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// principalKey 定义已认证调用方的上下文键.
+type principalKey struct{}
+
+// Principal 表示已通过认证的调用方，由认证中间件在请求开始时填充
+type Principal struct {
+	// UserID 是调用方的用户 ID. 模拟登录时为被模拟的用户 ID
+	UserID string
+	// Username 是调用方的用户名
+	Username string
+	// Roles 是调用方拥有的角色
+	Roles []string
+	// Scopes 是个人访问令牌的权限范围，nil 表示不受权限范围限制
+	Scopes []string
+	// AuthMethod 是调用方的认证方式，取值见 known.AuthMethodJWT 等
+	AuthMethod string
+	// TokenID 是请求所使用 token 的唯一标识（jti）
+	TokenID string
+	// SessionID 是签发 token 的登录会话 ID
+	SessionID string
+	// ActorID 是模拟登录时实际操作者的用户 ID，非模拟登录时为空
+	ActorID string
+}
+
+// HasRole 判断调用方是否拥有指定角色
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// LogValue 实现 slog.LogValuer 接口，只输出非空的字段. 用户 ID 由 LogAttrs 作为顶层字段输出，这里不再重复
+func (p *Principal) LogValue() slog.Value {
+	var attrs []slog.Attr
+	if p.Username != "" {
+		attrs = append(attrs, slog.String("username", p.Username))
+	}
+	if len(p.Roles) > 0 {
+		attrs = append(attrs, slog.Any("roles", p.Roles))
+	}
+	if p.AuthMethod != "" {
+		attrs = append(attrs, slog.String("authMethod", p.AuthMethod))
+	}
+	if p.TokenID != "" {
+		attrs = append(attrs, slog.String("tokenID", p.TokenID))
+	}
+	if p.SessionID != "" {
+		attrs = append(attrs, slog.String("sessionID", p.SessionID))
+	}
+	if p.ActorID != "" {
+		attrs = append(attrs, slog.String("actorID", p.ActorID))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// WithPrincipal 将已认证的调用方存放到上下文中，同时设置用户 ID、用户名、角色等单独的上下文值，
+// 以便只关心其中某一项的代码继续使用 UserID、Role 等函数读取.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	ctx = context.WithValue(ctx, principalKey{}, p)
+	ctx = WithUserID(ctx, p.UserID)
+	ctx = WithUsername(ctx, p.Username)
+	if len(p.Roles) > 0 {
+		ctx = WithRole(ctx, p.Roles[0])
+	}
+	if p.Scopes != nil {
+		ctx = WithScopes(ctx, p.Scopes)
+	}
+	ctx = WithTokenID(ctx, p.TokenID)
+	ctx = WithSessionID(ctx, p.SessionID)
+	return WithActorID(ctx, p.ActorID)
+}
+
+// PrincipalFrom 从上下文中提取已认证的调用方，未认证的请求返回 nil
+func PrincipalFrom(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
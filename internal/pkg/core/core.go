@@ -1,10 +1,18 @@
 package core
 
 import (
+	"context"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ErrorResponse 定义了错误响应的结构
@@ -22,6 +30,20 @@ func WriteResponse(c *gin.Context, data any, err error) {
 	if err != nil {
 		// 如果发生错误，生成错误响应
 		errx := errorsx.FromError(err) // 提取错误详细信息
+		// 请求超过处理时限后，数据库查询等操作被取消导致的服务端错误，返回超时错误而不是内部错误
+		if errx.Code >= http.StatusInternalServerError && (errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)) {
+			errx = errorsx.ErrRequestTimeout
+		}
+		// 在当前请求的 span 上记录错误原因，服务端错误同时记录为 span 的错误
+		span := trace.SpanFromContext(c.Request.Context())
+		span.SetAttributes(attribute.String("error.type", errx.Reason))
+		if errx.Code >= http.StatusInternalServerError {
+			tracing.RecordError(span, err)
+		}
+		// 错误附加了重试等待时间时（例如账号被临时锁定），通过 Retry-After 头告知客户端，单位为秒
+		if after, ok := errorsx.RetryAfter(err); ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(after.Seconds()))))
+		}
 		c.JSON(errx.Code, ErrorResponse{
 			Reason:  errx.Reason,
 			Message: errx.Message,
@@ -32,3 +54,11 @@ func WriteResponse(c *gin.Context, data any, err error) {
 	// 如果没有错误，返回成功响应
 	c.JSON(http.StatusOK, data)
 }
+
+// SetLastModified 设置响应的 Last-Modified 头，t 为零值时不设置. 需要在 WriteResponse 之前调用
+func SetLastModified(c *gin.Context, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	c.Header("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
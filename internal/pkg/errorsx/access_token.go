@@ -0,0 +1,11 @@
+package errorsx
+
+import "net/http"
+
+var (
+	// ErrAccessTokenNotFound 表示未找到指定的个人访问令牌
+	ErrAccessTokenNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.AccessTokenNotFound", Message: "Access token not found."}
+
+	// ErrAccessTokenNotAllowed 表示该接口不允许使用个人访问令牌访问，需要使用登录获得的 token
+	ErrAccessTokenNotAllowed = &ErrorX{Code: http.StatusForbidden, Reason: "PermissionDenied.AccessTokenNotAllowed", Message: "This operation cannot be performed with an access token."}
+)
@@ -28,6 +28,30 @@ var (
 	// ErrSignToken 表示签发 JWT Token 时出错.
 	ErrSignToken = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.SignToken", Message: "Error occurred while signing the JSON web token."}
 
+	// ErrTooManyRequests 表示请求过于频繁，触发了限流.
+	ErrTooManyRequests = &ErrorX{Code: http.StatusTooManyRequests, Reason: "ResourceExhausted.TooManyRequests", Message: "Too many requests, please try again later."}
+
+	// ErrRequestEntityTooLarge 表示请求体超过了接口允许的最大长度.
+	ErrRequestEntityTooLarge = &ErrorX{Code: http.StatusRequestEntityTooLarge, Reason: "InvalidArgument.RequestEntityTooLarge", Message: "Request body is too large."}
+
+	// ErrRequestTimeout 表示请求没有在接口允许的时间内处理完成.
+	ErrRequestTimeout = &ErrorX{Code: http.StatusGatewayTimeout, Reason: "DeadlineExceeded.RequestTimeout", Message: "Request timed out, please try again later."}
+
+	// ErrPermissionDenied 表示请求没有执行该操作的权限.
+	ErrPermissionDenied = &ErrorX{Code: http.StatusForbidden, Reason: "PermissionDenied", Message: "Permission denied."}
+
 	// ErrTokenInvalid 表示 JWT Token 格式无效.
 	ErrTokenInvalid = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.TokenInvalid", Message: "Token was invalid."}
+
+	// ErrTokenRevoked 表示 JWT Token 已被吊销（例如用户已注销或修改了密码）.
+	ErrTokenRevoked = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.TokenRevoked", Message: "Token has been revoked."}
+
+	// ErrScopeInsufficient 表示个人访问令牌没有访问该接口所需的权限范围.
+	ErrScopeInsufficient = &ErrorX{Code: http.StatusForbidden, Reason: "PermissionDenied.ScopeInsufficient", Message: "Access token does not have the required scope."}
+
+	// ErrRefreshTokenInvalid 表示 refresh token 不存在、已过期或已被吊销.
+	ErrRefreshTokenInvalid = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.RefreshTokenInvalid", Message: "Refresh token was invalid."}
+
+	// ErrRefreshTokenReused 表示已经轮换过的 refresh token 被再次使用，整个令牌族已被吊销.
+	ErrRefreshTokenReused = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.RefreshTokenReused", Message: "Refresh token was reused, please login again."}
 )
@@ -0,0 +1,11 @@
+package errorsx
+
+import "net/http"
+
+var (
+	// ErrEmailNotVerified 表示用户的电子邮箱尚未验证，不允许执行该操作
+	ErrEmailNotVerified = &ErrorX{Code: http.StatusForbidden, Reason: "PermissionDenied.EmailNotVerified", Message: "Email address has not been verified."}
+
+	// ErrEmailTokenInvalid 表示验证邮箱或重置密码的链接无效、已过期或已被使用
+	ErrEmailTokenInvalid = &ErrorX{Code: http.StatusBadRequest, Reason: "InvalidArgument.EmailTokenInvalid", Message: "The link is invalid or has expired."}
+)
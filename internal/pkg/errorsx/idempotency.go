@@ -0,0 +1,14 @@
+package errorsx
+
+import "net/http"
+
+var (
+	// ErrIdempotencyKeyInvalid 表示 Idempotency-Key 请求头的格式无效
+	ErrIdempotencyKeyInvalid = &ErrorX{Code: http.StatusBadRequest, Reason: "InvalidArgument.IdempotencyKeyInvalid", Message: "Idempotency-Key must be between 1 and 255 characters."}
+
+	// ErrIdempotencyKeyReused 表示同一个 Idempotency-Key 被用于内容不同的请求
+	ErrIdempotencyKeyReused = &ErrorX{Code: http.StatusConflict, Reason: "Conflict.IdempotencyKeyReused", Message: "Idempotency-Key has already been used with a different request."}
+
+	// ErrIdempotencyRequestInProgress 表示使用同一个 Idempotency-Key 的请求仍在处理中
+	ErrIdempotencyRequestInProgress = &ErrorX{Code: http.StatusConflict, Reason: "Conflict.IdempotencyRequestInProgress", Message: "A request with the same Idempotency-Key is still being processed."}
+)
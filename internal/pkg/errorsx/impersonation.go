@@ -0,0 +1,14 @@
+package errorsx
+
+import "net/http"
+
+var (
+	// ErrImpersonationNotFound 表示未找到指定的模拟登录记录
+	ErrImpersonationNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.ImpersonationNotFound", Message: "Impersonation not found."}
+
+	// ErrImpersonationNotAllowed 表示不能模拟该用户（例如模拟自己或其他管理员）
+	ErrImpersonationNotAllowed = &ErrorX{Code: http.StatusForbidden, Reason: "PermissionDenied.ImpersonationNotAllowed", Message: "This user cannot be impersonated."}
+
+	// ErrImpersonating 表示该操作不允许在模拟登录期间执行
+	ErrImpersonating = &ErrorX{Code: http.StatusForbidden, Reason: "PermissionDenied.Impersonating", Message: "This operation cannot be performed while impersonating another user."}
+)
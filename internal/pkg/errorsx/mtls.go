@@ -0,0 +1,8 @@
+package errorsx
+
+import "net/http"
+
+var (
+	// ErrClientCertUnknown 表示客户端证书虽然通过了校验，但没有映射到任何服务调用方
+	ErrClientCertUnknown = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.ClientCertificateUnknown", Message: "The client certificate is not mapped to any service."}
+)
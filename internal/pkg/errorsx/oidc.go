@@ -0,0 +1,26 @@
+package errorsx
+
+import "net/http"
+
+var (
+	// ErrOIDCProviderNotFound 表示未配置指定的外部 OIDC 提供方
+	ErrOIDCProviderNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.OIDCProviderNotFound", Message: "OIDC provider not found."}
+
+	// ErrOIDCUnavailable 表示外部 OIDC 提供方暂时不可用
+	ErrOIDCUnavailable = &ErrorX{Code: http.StatusServiceUnavailable, Reason: "Unavailable.OIDCProvider", Message: "OIDC provider is unavailable, please try again later."}
+
+	// ErrOIDCLoginFailed 表示外部登录的状态 token 无效、授权码无效或 ID Token 校验失败
+	ErrOIDCLoginFailed = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.OIDCLoginFailed", Message: "External login failed, please try again."}
+
+	// ErrIdentityNotLinked 表示外部账号未关联本地账号，且该提供方不允许自动创建账号
+	ErrIdentityNotLinked = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.IdentityNotLinked", Message: "External account is not linked to any user."}
+
+	// ErrIdentityAlreadyLinked 表示外部账号已关联其他用户，或当前用户已关联该提供方的其他外部账号
+	ErrIdentityAlreadyLinked = &ErrorX{Code: http.StatusConflict, Reason: "AlreadyExist.IdentityAlreadyLinked", Message: "External account is already linked."}
+
+	// ErrIdentityNotFound 表示当前用户未关联指定提供方的外部账号
+	ErrIdentityNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.IdentityNotFound", Message: "External identity not found."}
+
+	// ErrLastIdentity 表示没有设置密码的用户不能解除唯一的外部账号关联，否则将无法再登录
+	ErrLastIdentity = &ErrorX{Code: http.StatusBadRequest, Reason: "FailedPrecondition.LastIdentity", Message: "Cannot unlink the only sign-in method, please set a password first."}
+)
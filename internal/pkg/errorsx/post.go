@@ -2,5 +2,13 @@ package errorsx
 
 import "net/http"
 
-// ErrPostNotFound 表示未找到指定的博客
-var ErrPostNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.PostNotFound", Message: "Post not found."}
+var (
+	// ErrPostNotFound 表示未找到指定的博客
+	ErrPostNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.PostNotFound", Message: "Post not found."}
+
+	// ErrCollaboratorNotFound 表示未找到指定的博客协作者
+	ErrCollaboratorNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.CollaboratorNotFound", Message: "Post collaborator not found."}
+
+	// ErrPostPermissionDenied 表示当前用户对博客没有执行该操作的权限
+	ErrPostPermissionDenied = &ErrorX{Code: http.StatusForbidden, Reason: "PermissionDenied.PostPermissionDenied", Message: "Permission denied on the post."}
+)
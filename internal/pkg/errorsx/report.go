@@ -0,0 +1,14 @@
+package errorsx
+
+import "net/http"
+
+var (
+	// ErrReportNotFound 表示未找到指定的举报
+	ErrReportNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.ReportNotFound", Message: "Report not found."}
+
+	// ErrReportAlreadyHandled 表示举报已经被处理过，不能重复处理
+	ErrReportAlreadyHandled = &ErrorX{Code: http.StatusConflict, Reason: "Conflict.ReportAlreadyHandled", Message: "Report has already been handled."}
+
+	// ErrModerationActionInvalid 表示审核操作与举报对象不匹配
+	ErrModerationActionInvalid = &ErrorX{Code: http.StatusBadRequest, Reason: "InvalidArgument.ModerationActionInvalid", Message: "Moderation action is not applicable to the report target."}
+)
@@ -0,0 +1,37 @@
+package errorsx
+
+import (
+	"errors"
+	"time"
+)
+
+// retryAfterError 为错误附加客户端可以重试的等待时间，响应时写入 Retry-After 头
+type retryAfterError struct {
+	err   *ErrorX
+	after time.Duration
+}
+
+// WithRetryAfter 为 err 附加重试等待时间 after. 返回的错误仍然可以通过 errors.Is 与 err 比较.
+func WithRetryAfter(err *ErrorX, after time.Duration) error {
+	return &retryAfterError{err: err, after: after}
+}
+
+// Error 实现 error 接口中的 `Error` 方法
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap 返回被包装的 *ErrorX
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfter 返回错误中附加的重试等待时间
+func RetryAfter(err error) (time.Duration, bool) {
+	var e *retryAfterError
+	if errors.As(err, &e) {
+		return e.after, true
+	}
+
+	return 0, false
+}
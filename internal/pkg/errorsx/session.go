@@ -0,0 +1,8 @@
+package errorsx
+
+import "net/http"
+
+var (
+	// ErrSessionNotFound 表示未找到指定的登录会话
+	ErrSessionNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.SessionNotFound", Message: "Session not found."}
+)
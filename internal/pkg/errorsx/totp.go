@@ -0,0 +1,17 @@
+package errorsx
+
+import "net/http"
+
+var (
+	// ErrTOTPNotEnabled 表示用户尚未开启或尚未开始设置两步验证
+	ErrTOTPNotEnabled = &ErrorX{Code: http.StatusBadRequest, Reason: "FailedPrecondition.TOTPNotEnabled", Message: "Two-factor authentication is not enabled."}
+
+	// ErrTOTPAlreadyEnabled 表示用户已经开启了两步验证
+	ErrTOTPAlreadyEnabled = &ErrorX{Code: http.StatusConflict, Reason: "AlreadyExist.TOTPAlreadyEnabled", Message: "Two-factor authentication is already enabled."}
+
+	// ErrTOTPCodeInvalid 表示 TOTP 验证码或恢复码错误、已过期或已被使用
+	ErrTOTPCodeInvalid = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.TOTPCodeInvalid", Message: "Verification code is invalid."}
+
+	// ErrChallengeInvalid 表示两步验证登录的挑战 token 无效或已过期，需要重新登录
+	ErrChallengeInvalid = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.ChallengeInvalid", Message: "Login challenge was invalid or has expired, please login again."}
+)
@@ -22,4 +22,16 @@ var (
 
 	// ErrUserNotFound 表示未找到指定的用户
 	ErrUserNotFound = &ErrorX{Code: http.StatusNotFound, Reason: "NotFound.UserNotFound", Message: "User not found."}
+
+	// ErrLoginFailed 表示用户名或密码错误. 登录时不区分用户不存在和密码错误，避免泄露用户名是否已注册
+	ErrLoginFailed = &ErrorX{Code: http.StatusUnauthorized, Reason: "Unauthenticated.LoginFailed", Message: "Username or password is incorrect."}
+
+	// ErrAccountLocked 表示登录失败次数过多，账号或客户端被临时锁定，响应中的 Retry-After 头为需要等待的秒数
+	ErrAccountLocked = &ErrorX{Code: http.StatusTooManyRequests, Reason: "ResourceExhausted.AccountLocked", Message: "Too many failed login attempts, please try again later."}
+
+	// ErrUserSuspended 表示用户已被管理员封禁
+	ErrUserSuspended = &ErrorX{Code: http.StatusForbidden, Reason: "PermissionDenied.UserSuspended", Message: "User has been suspended."}
+
+	// ErrLastAdmin 表示修改角色或删除用户后系统中将没有任何管理员
+	ErrLastAdmin = &ErrorX{Code: http.StatusConflict, Reason: "Conflict.LastAdmin", Message: "Cannot demote or delete the last administrator."}
 )
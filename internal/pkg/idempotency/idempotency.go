@@ -0,0 +1,34 @@
+// Package idempotency 保存携带 Idempotency-Key 的请求的指纹和响应，支持内存和 Redis 两种存储.
+// 同一个 key 的第一次请求处理期间，key 处于锁定状态，并发的重复请求会被拒绝；处理完成后保存响应，
+// 之后的重复请求直接返回保存的响应. 内存存储只在单个实例内生效，多实例部署时应使用 Redis 存储.
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record 是一个 key 对应的请求记录
+type Record struct {
+	// Fingerprint 是请求的指纹，key 相同但指纹不同的请求视为误用
+	Fingerprint string `json:"fingerprint"`
+	// Completed 表示第一次请求是否已经处理完成，为 false 时表示仍在处理中
+	Completed bool `json:"completed"`
+	// Status 是保存的响应状态码
+	Status int `json:"status,omitempty"`
+	// ContentType 是保存的响应 Content-Type
+	ContentType string `json:"contentType,omitempty"`
+	// Body 是保存的响应体
+	Body []byte `json:"body,omitempty"`
+}
+
+// Store 定义保存请求记录的存储
+type Store interface {
+	// Reserve 在 key 不存在时保存一条处理中的记录，lockTimeout 后自动过期，返回 nil.
+	// key 已存在时不做修改，返回已有的记录
+	Reserve(ctx context.Context, key string, fingerprint string, lockTimeout time.Duration) (*Record, error)
+	// Complete 保存处理完成的记录，ttl 后过期
+	Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error
+	// Release 删除 key 对应的记录，使客户端可以使用同一个 key 重试
+	Release(ctx context.Context, key string) error
+}
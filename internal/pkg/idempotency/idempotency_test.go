@@ -0,0 +1,65 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore 使用可控的时钟验证存储的行为
+func testStore(t *testing.T, s Store, advance func(time.Duration)) {
+	ctx := context.Background()
+
+	record, err := s.Reserve(ctx, "k", "fp", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	// 第一次请求处理期间，重复请求取得处理中的记录
+	record, err = s.Reserve(ctx, "k", "other", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "fp", record.Fingerprint)
+	assert.False(t, record.Completed)
+
+	// 处理完成后，重复请求取得保存的响应
+	require.NoError(t, s.Complete(ctx, "k", &Record{Fingerprint: "fp", Completed: true, Status: 200, ContentType: "application/json", Body: []byte(`{"postID":"post-000001"}`)}, time.Hour))
+	record, err = s.Reserve(ctx, "k", "fp", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.True(t, record.Completed)
+	assert.Equal(t, 200, record.Status)
+	assert.Equal(t, `{"postID":"post-000001"}`, string(record.Body))
+
+	// 记录过期后可以重新使用 key
+	advance(2 * time.Hour)
+	record, err = s.Reserve(ctx, "k", "fp", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	// 释放后可以立即重试
+	require.NoError(t, s.Release(ctx, "k"))
+	record, err = s.Reserve(ctx, "k", "fp", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func TestMemory(t *testing.T) {
+	now := time.Now()
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	testStore(t, m, func(d time.Duration) { now = now.Add(d) })
+}
+
+func TestRedis(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	testStore(t, NewRedis(client), s.FastForward)
+}
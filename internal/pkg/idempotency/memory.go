@@ -0,0 +1,82 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 定义清理过期记录的周期
+const sweepInterval = time.Minute
+
+// entry 是一条带有过期时间的记录
+type entry struct {
+	record   Record
+	expireAt time.Time
+}
+
+// Memory 是基于内存的存储，记录只在当前实例内生效
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+	// now 返回当前时间，便于测试
+	now func() time.Time
+}
+
+// 确保 Memory 实现了 Store 接口
+var _ Store = (*Memory)(nil)
+
+// NewMemory 创建一个基于内存的存储
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]*entry), now: time.Now}
+}
+
+// Reserve 实现 Store 接口中的 Reserve 方法
+func (m *Memory) Reserve(ctx context.Context, key string, fingerprint string, lockTimeout time.Duration) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if e, ok := m.entries[key]; ok && now.Before(e.expireAt) {
+		record := e.record
+		return &record, nil
+	}
+
+	m.entries[key] = &entry{record: Record{Fingerprint: fingerprint}, expireAt: now.Add(lockTimeout)}
+	return nil, nil
+}
+
+// Complete 实现 Store 接口中的 Complete 方法
+func (m *Memory) Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = &entry{record: *record, expireAt: m.now().Add(ttl)}
+	return nil
+}
+
+// Release 实现 Store 接口中的 Release 方法
+func (m *Memory) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+// sweep 周期性地删除过期的记录，避免内存无限增长
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, e := range m.entries {
+		if !now.Before(e.expireAt) {
+			delete(m.entries, key)
+		}
+	}
+}
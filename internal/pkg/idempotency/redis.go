@@ -0,0 +1,78 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// reserveScript 在 Redis 中原子地检查 key 是否存在：存在时返回已有的记录，不存在时保存处理中的记录
+var reserveScript = redis.NewScript(`
+local record = redis.call('GET', KEYS[1])
+if record then
+  return record
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return false
+`)
+
+// RedisClient 定义 Redis 存储使用的 Redis 命令
+type RedisClient interface {
+	redis.Scripter
+	Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// Redis 是基于 Redis 的存储，多个实例共享记录
+type Redis struct {
+	client RedisClient
+}
+
+// 确保 Redis 实现了 Store 接口
+var _ Store = (*Redis)(nil)
+
+// NewRedis 创建一个基于 Redis 的存储
+func NewRedis(client RedisClient) *Redis {
+	return &Redis{client: client}
+}
+
+// Reserve 实现 Store 接口中的 Reserve 方法
+func (r *Redis) Reserve(ctx context.Context, key string, fingerprint string, lockTimeout time.Duration) (*Record, error) {
+	data, err := json.Marshal(&Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := reserveScript.Run(ctx, r.client, []string{key}, data, lockTimeout.Milliseconds()).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record Record
+	if err := json.Unmarshal([]byte(existing), &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Complete 实现 Store 接口中的 Complete 方法
+func (r *Redis) Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, key, data, ttl).Err()
+}
+
+// Release 实现 Store 接口中的 Release 方法
+func (r *Redis) Release(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
@@ -6,6 +6,9 @@ const (
 
 	// XUserID 用来定义上下文的键，代表请求用户 ID. UserID 整个用户生命周期唯一.
 	XUserID = "x-user-id"
+
+	// XTraceID 用来定义响应头的键，代表请求所属链路的 trace ID
+	XTraceID = "x-trace-id"
 )
 
 // 定义其他常量
@@ -15,3 +18,95 @@ const (
 	// 根据场景需求，可以调整该值大小
 	MaxErrGroupConcurrency = 1000
 )
+
+// 定义博文协作者角色
+const (
+	// PostRoleOwner 表示博文作者. 作者不会记录在协作者表中，拥有博文的全部权限
+	PostRoleOwner = "owner"
+	// PostRoleEditor 表示可以查看和更新博文的协作者
+	PostRoleEditor = "editor"
+	// PostRoleViewer 表示只能查看博文的协作者
+	PostRoleViewer = "viewer"
+)
+
+// 定义用户角色
+const (
+	// RoleUser 表示普通用户，只能管理自己的账号
+	RoleUser = "user"
+	// RoleAdmin 表示管理员，可以管理所有用户的账号，并负责内容审核
+	RoleAdmin = "admin"
+	// RoleService 表示通过 TLS 客户端证书认证的内部服务，只能访问其权限范围允许的接口
+	RoleService = "service"
+)
+
+// 定义调用方的认证方式
+const (
+	// AuthMethodJWT 表示使用登录获得的 JWT Token 认证
+	AuthMethodJWT = "jwt"
+	// AuthMethodPAT 表示使用个人访问令牌认证
+	AuthMethodPAT = "pat"
+	// AuthMethodMTLS 表示使用 TLS 客户端证书认证
+	AuthMethodMTLS = "mtls"
+)
+
+// 定义登录方式，用于登录指标
+const (
+	// LoginMethodPassword 表示使用用户名和密码登录
+	LoginMethodPassword = "password"
+	// LoginMethodTOTP 表示完成两步验证登录
+	LoginMethodTOTP = "totp"
+	// LoginMethodOIDC 表示使用外部 OIDC 账号登录
+	LoginMethodOIDC = "oidc"
+)
+
+// 定义用户状态
+const (
+	// UserStatusActive 表示用户状态正常
+	UserStatusActive = "active"
+	// UserStatusSuspended 表示用户已被管理员封禁，无法登录
+	UserStatusSuspended = "suspended"
+)
+
+// 定义内容审核相关常量
+const (
+	// ReportTargetPost 表示被举报的对象是博文
+	ReportTargetPost = "post"
+	// ReportTargetUser 表示被举报的对象是用户
+	ReportTargetUser = "user"
+
+	// ReportSourceUser 表示由用户提交的举报
+	ReportSourceUser = "user"
+	// ReportSourceFilter 表示由关键词/正则过滤器自动提交的举报
+	ReportSourceFilter = "filter"
+
+	// ReportStatusPending 表示举报待处理
+	ReportStatusPending = "pending"
+	// ReportStatusResolved 表示举报已处理（已隐藏博文或封禁用户）
+	ReportStatusResolved = "resolved"
+	// ReportStatusDismissed 表示举报已被驳回
+	ReportStatusDismissed = "dismissed"
+
+	// ModerationActionHidePost 表示隐藏博文
+	ModerationActionHidePost = "hide_post"
+	// ModerationActionSuspendUser 表示封禁用户
+	ModerationActionSuspendUser = "suspend_user"
+	// ModerationActionDismiss 表示驳回举报
+	ModerationActionDismiss = "dismiss"
+)
+
+// 定义个人访问令牌的权限范围
+const (
+	// ScopeUsersRead 表示可以查询用户信息
+	ScopeUsersRead = "users:read"
+	// ScopeUsersWrite 表示可以更新用户信息
+	ScopeUsersWrite = "users:write"
+	// ScopePostsRead 表示可以查询博文
+	ScopePostsRead = "posts:read"
+	// ScopePostsWrite 表示可以创建、更新、删除博文以及管理博文协作者
+	ScopePostsWrite = "posts:write"
+	// ScopeReportsWrite 表示可以提交举报
+	ScopeReportsWrite = "reports:write"
+
+	// AccessTokenPrefix 是个人访问令牌的前缀，用于与 JWT Token 区分
+	AccessTokenPrefix = "fgp_"
+)
@@ -0,0 +1,109 @@
+// Package metrics 定义 fg-apiserver 暴露给 Prometheus 的指标，包括 HTTP 请求、数据库连接池、
+// Go 运行时及登录、创建博客等业务指标. 所有指标注册在独立的 Registry 中，通过 Handler 暴露.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/pkg/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 是所有指标名称的前缀
+const namespace = "fastgo"
+
+// 登录结果
+const (
+	// LoginSuccess 表示登录成功并签发了 token
+	LoginSuccess = "success"
+	// LoginFailure 表示登录失败
+	LoginFailure = "failure"
+	// LoginTwoFactorRequired 表示第一步认证通过，等待完成两步验证
+	LoginTwoFactorRequired = "two_factor_required"
+)
+
+// registry 保存 fg-apiserver 的所有指标
+var registry = prometheus.NewRegistry()
+
+var (
+	// httpRequests 统计 HTTP 请求数，按请求方法、路由模板和状态码区分
+	httpRequests = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	// httpDuration 统计 HTTP 请求耗时，按请求方法、路由模板和状态码区分
+	httpDuration = promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latencies in seconds by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// logins 统计登录次数，按登录方式和登录结果区分
+	logins = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Total number of login attempts by method and result.",
+	}, []string{"method", "result"})
+
+	// postsCreated 统计创建的博客数
+	postsCreated = promauto.With(registry).NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "posts_created_total",
+		Help:      "Total number of posts created.",
+	})
+
+	// buildInfo 以标签的形式暴露构建信息，值恒为 1
+	buildInfo = promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "build_info",
+		Help:      "Build information of fg-apiserver, the value is always 1.",
+	}, []string{"git_version", "git_commit", "git_tree_state", "build_date", "go_version", "platform"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	info := version.Get()
+	buildInfo.WithLabelValues(info.GitVersion, info.GitCommit, info.GitTreeState, info.BuildDate, info.GoVersion, info.Platform).Set(1)
+}
+
+// RegisterDB 注册数据库连接池指标，应在创建数据库连接后调用一次
+func RegisterDB(db *sql.DB) error {
+	return registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// ObserveHTTPRequest 记录一次 HTTP 请求. route 应为路由模板（例如 /v1/posts/:postID），避免标签基数过大
+func ObserveHTTPRequest(method string, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveLogin 记录一次登录，method 为登录方式（例如 password、totp、oidc），result 为登录结果
+func ObserveLogin(method string, result string) {
+	logins.WithLabelValues(method, result).Inc()
+}
+
+// ObservePostCreated 记录创建了一篇博客
+func ObservePostCreated() {
+	postsCreated.Inc()
+}
+
+// Handler 返回暴露所有指标的 HTTP Handler
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
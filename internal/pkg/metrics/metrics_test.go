@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	ObserveHTTPRequest("GET", "/v1/posts/:postID", 200, 10*time.Millisecond)
+	ObserveLogin("password", LoginFailure)
+	ObservePostCreated()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	out := string(body)
+	assert.Contains(t, out, `fastgo_http_requests_total{method="GET",route="/v1/posts/:postID",status="200"} 1`)
+	assert.Contains(t, out, `fastgo_http_request_duration_seconds_count{method="GET",route="/v1/posts/:postID",status="200"} 1`)
+	assert.Contains(t, out, `fastgo_logins_total{method="password",result="failure"} 1`)
+	assert.Contains(t, out, "fastgo_posts_created_total 1")
+	assert.Contains(t, out, "fastgo_build_info{")
+	assert.Contains(t, out, "go_goroutines")
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog 是访问日志中间件，请求处理完成后记录一条访问日志，包括请求方法、路由模板、状态码、
+// 响应字节数、耗时和客户端 IP. 请求 ID 和用户 ID 由 contextx.NewLogHandler 从 context 中追加.
+// 5xx 响应记录为 Error 级别，4xx 响应记录为 Warn 级别. 需要在 RequestID 和 ClientIP 中间件之后加载.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		// c.Request 在认证中间件中被替换为携带用户信息的请求，这里读取的是处理完成后的 context
+		ctx := c.Request.Context()
+		slog.LogAttrs(ctx, level, "HTTP request",
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("clientIP", contextx.ClientIP(ctx)),
+		)
+	}
+}
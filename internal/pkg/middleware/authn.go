@@ -1,31 +1,136 @@
 package middleware
 
 import (
+	"context"
+	"crypto/x509"
+	"strings"
+
 	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
 	"github.com/TobyIcetea/fastgo/internal/pkg/core"
 	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
 	"github.com/TobyIcetea/fastgo/pkg/token"
 	"github.com/gin-gonic/gin"
 )
 
-// Authn 是认证中间件，用来从 gin.Context 中提取 token 并验证 token 是否合法，
-// 如果合法则将 token 中的 sub 作为<用户名>存放在 gin.Context 的 XUsernameKey 键中.
-func Authn() gin.HandlerFunc {
+// TokenRevoker 用于检查 token 是否已被吊销.
+type TokenRevoker interface {
+	IsRevoked(ctx context.Context, claims *token.Claims) (bool, error)
+}
+
+// SessionToucher 用于记录登录会话的最近活动，实现需要自行节流，避免每个请求都写数据库.
+type SessionToucher interface {
+	TouchSession(ctx context.Context, sessionID string, clientIP string)
+}
+
+// AccessTokenVerifier 用于校验个人访问令牌，返回令牌所属的调用方，调用方的 Scopes 为令牌的权限范围.
+type AccessTokenVerifier interface {
+	Verify(ctx context.Context, plain string) (*contextx.Principal, error)
+}
+
+// ClientCertVerifier 用于将已通过校验的 TLS 客户端证书映射为服务调用方.
+type ClientCertVerifier interface {
+	Verify(cert *x509.Certificate) (*contextx.Principal, error)
+}
+
+// Authn 是认证中间件，用来从 gin.Context 中提取 token 并验证 token 是否合法且未被吊销，
+// 如果合法则将调用方（contextx.Principal，包括用户 ID、用户名、角色、认证方式和 token ID 等）存放在请求的 context 中.
+// 模拟登录 token 还会将实际操作者的用户 ID 存放在 context 中，并为每个请求记录审计日志.
+// 除 JWT Token 外，也接受以 known.AccessTokenPrefix 开头的个人访问令牌，此时会将令牌的权限范围存放在 context 中.
+// sessions 不为 nil 时，使用 JWT Token 的请求会更新其登录会话的最近活动时间.
+// certs 不为 nil 时，请求未携带 Authorization 头但携带了已通过校验的客户端证书（mTLS）时，使用证书认证服务调用方；
+// 为 nil 时不接受客户端证书认证.
+func Authn(revoker TokenRevoker, sessions SessionToucher, verifier AccessTokenVerifier, certs ClientCertVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// TLS 客户端证书
+		if certs != nil && c.GetHeader("Authorization") == "" && c.Request.TLS != nil && len(c.Request.TLS.VerifiedChains) > 0 {
+			principal, err := certs.Verify(c.Request.TLS.VerifiedChains[0][0])
+			if err != nil {
+				core.WriteResponse(c, nil, err)
+				c.Abort()
+				return
+			}
+
+			c.Request = c.Request.WithContext(contextx.WithPrincipal(c.Request.Context(), principal))
+
+			c.Next()
+			return
+		}
+
+		// 个人访问令牌
+		if plain, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && strings.HasPrefix(plain, known.AccessTokenPrefix) {
+			principal, err := verifier.Verify(c.Request.Context(), plain)
+			if err != nil {
+				core.WriteResponse(c, nil, err)
+				c.Abort()
+				return
+			}
+
+			c.Request = c.Request.WithContext(contextx.WithPrincipal(c.Request.Context(), principal))
+
+			c.Next()
+			return
+		}
+
 		// 解析 JWT Token
-		userID, err := token.ParseRequest(c)
+		claims, err := token.ParseRequest(c)
+		if err != nil {
+			core.WriteResponse(c, nil, errorsx.ErrTokenInvalid)
+			c.Abort()
+			return
+		}
+
+		// 检查 token 是否已被吊销（注销、修改密码、移除登录会话等）
+		revoked, err := revoker.IsRevoked(c.Request.Context(), claims)
 		if err != nil {
-			core.WriteResponse(c, errorsx.ErrTokenInvalid, nil)
+			core.WriteResponse(c, nil, err)
+			c.Abort()
+			return
+		}
+		if revoked {
+			core.WriteResponse(c, nil, errorsx.ErrTokenRevoked)
 			c.Abort()
 			return
 		}
 
-		// 将用户ID和用户名注入到上下文中
-		ctx := contextx.WithUserID(c.Request.Context(), userID)
-		c.Request = c.Request.WithContext(ctx)
+		// 模拟登录 token 还需要检查实际操作者的 token 是否已被全部吊销（例如管理员修改了密码）
+		if claims.Actor != "" {
+			revoked, err := revoker.IsRevoked(c.Request.Context(), &token.Claims{Identity: claims.Actor, IssuedAt: claims.IssuedAt})
+			if err != nil {
+				core.WriteResponse(c, nil, err)
+				c.Abort()
+				return
+			}
+			if revoked {
+				core.WriteResponse(c, nil, errorsx.ErrTokenRevoked)
+				c.Abort()
+				return
+			}
+		}
+
+		// 记录登录会话的最近活动时间. 模拟登录 token 不属于任何登录会话
+		if sessions != nil && claims.SessionID != "" {
+			sessions.TouchSession(c.Request.Context(), claims.SessionID, contextx.ClientIP(c.Request.Context()))
+		}
+
+		// 将调用方注入到上下文中
+		principal := &contextx.Principal{
+			UserID:     claims.Identity,
+			Username:   claims.Username,
+			Roles:      []string{claims.Role},
+			AuthMethod: known.AuthMethodJWT,
+			TokenID:    claims.ID,
+			SessionID:  claims.SessionID,
+			ActorID:    claims.Actor,
+		}
+		c.Request = c.Request.WithContext(contextx.WithPrincipal(c.Request.Context(), principal))
 
 		// 继续后续的操作
 		c.Next()
 
+		// 模拟登录期间的每个请求都记录审计日志
+		if claims.Actor != "" {
+			auditImpersonation(c)
+		}
 	}
 }
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVerifier 把令牌值映射为调用方
+type fakeVerifier map[string]*contextx.Principal
+
+func (v fakeVerifier) Verify(ctx context.Context, plain string) (*contextx.Principal, error) {
+	if p, ok := v[plain]; ok {
+		return p, nil
+	}
+	return nil, errorsx.ErrTokenInvalid
+}
+
+// fakeRevoker 记录被吊销的 jti、用户及登录会话的活动
+type fakeRevoker struct {
+	jtis    map[string]bool
+	users   map[string]bool
+	touches []string
+}
+
+func (r *fakeRevoker) IsRevoked(ctx context.Context, claims *token.Claims) (bool, error) {
+	return r.jtis[claims.ID] || r.users[claims.Identity], nil
+}
+
+func (r *fakeRevoker) TouchSession(ctx context.Context, sessionID string, clientIP string) {
+	r.touches = append(r.touches, sessionID+"@"+clientIP)
+}
+
+func TestAuthnSessionActivity(t *testing.T) {
+	revoker := &fakeRevoker{}
+	verifier := fakeVerifier{
+		known.AccessTokenPrefix + "read": {UserID: "user-000001", Roles: []string{known.RoleUser}, Scopes: []string{known.ScopePostsRead}, AuthMethod: known.AuthMethodPAT},
+	}
+	engine := gin.New()
+	engine.Use(ClientIP(), Authn(revoker, revoker, verifier, nil))
+	engine.GET("/posts", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	get := func(authorization string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+		r.RemoteAddr = "192.0.2.1:12345"
+		r.Header.Set("Authorization", authorization)
+		engine.ServeHTTP(w, r)
+		return w
+	}
+
+	tokenStr, _, err := token.Sign(token.Claims{Identity: "user-000001", Username: "alice", Role: known.RoleUser, SessionID: "session-000001"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, get("Bearer "+tokenStr).Code)
+	assert.Equal(t, []string{"session-000001@192.0.2.1"}, revoker.touches)
+
+	// 个人访问令牌和模拟登录 token 不属于任何登录会话
+	assert.Equal(t, http.StatusOK, get("Bearer "+known.AccessTokenPrefix+"read").Code)
+	impersonation, _, err := token.SignImpersonation(token.Claims{Identity: "user-000001", Username: "alice", Role: known.RoleUser, Actor: "user-000002", ID: "imp-000001"}, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, get("Bearer "+impersonation).Code)
+	assert.Len(t, revoker.touches, 1)
+}
+
+func TestAuthnAccessTokenScopes(t *testing.T) {
+	verifier := fakeVerifier{
+		known.AccessTokenPrefix + "read": {UserID: "user-000001", Roles: []string{known.RoleUser}, Scopes: []string{known.ScopePostsRead}, AuthMethod: known.AuthMethodPAT},
+		known.AccessTokenPrefix + "none": {UserID: "user-000001", Roles: []string{known.RoleUser}, Scopes: []string{}, AuthMethod: known.AuthMethodPAT},
+	}
+	engine := gin.New()
+	engine.Use(Authn(nil, nil, verifier, nil))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	engine.GET("/posts", RequireScopes(known.ScopePostsRead), ok)
+	engine.POST("/posts", RequireScopes(known.ScopePostsWrite), ok)
+	engine.POST("/change-password", SessionOnly(), ok)
+
+	do := func(method string, path string, plain string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, nil)
+		r.Header.Set("Authorization", "Bearer "+plain)
+		engine.ServeHTTP(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		plain  string
+		want   int
+		reason string
+	}{
+		{name: "scope granted", method: http.MethodGet, path: "/posts", plain: "read", want: http.StatusOK},
+		{name: "scope missing", method: http.MethodPost, path: "/posts", plain: "read", want: http.StatusForbidden, reason: errorsx.ErrScopeInsufficient.Reason},
+		{name: "no scopes", method: http.MethodGet, path: "/posts", plain: "none", want: http.StatusForbidden, reason: errorsx.ErrScopeInsufficient.Reason},
+		{name: "session only", method: http.MethodPost, path: "/change-password", plain: "read", want: http.StatusForbidden, reason: errorsx.ErrAccessTokenNotAllowed.Reason},
+		{name: "invalid token", method: http.MethodGet, path: "/posts", plain: "unknown", want: http.StatusUnauthorized, reason: errorsx.ErrTokenInvalid.Reason},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.method, tt.path, known.AccessTokenPrefix+tt.plain)
+			assert.Equal(t, tt.want, w.Code)
+			if tt.reason != "" {
+				assert.Contains(t, w.Body.String(), tt.reason)
+			}
+		})
+	}
+}
+
+func TestAuthnImpersonation(t *testing.T) {
+	revoker := &fakeRevoker{jtis: make(map[string]bool), users: make(map[string]bool)}
+	engine := gin.New()
+	engine.Use(Authn(revoker, nil, nil, nil))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	engine.GET("/v1/posts", ok)
+	engine.PUT("/v1/users/:userID/change-password", NoImpersonation(), ok)
+	engine.PUT("/v1/users/:userID", NoImpersonation(), ok)
+	engine.POST("/v1/users/:userID/verification-email", NoImpersonation(), ok)
+	engine.DELETE("/v1/users/:userID", NoImpersonation(), ok)
+
+	do := func(method string, path string, tokenStr string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, nil)
+		r.Header.Set("Authorization", "Bearer "+tokenStr)
+		engine.ServeHTTP(w, r)
+		return w
+	}
+
+	// user-000001 模拟 user-000002 登录
+	impersonation, _, err := token.SignImpersonation(token.Claims{Identity: "user-000002", Username: "bob", Role: known.RoleUser, Actor: "user-000001", ID: "imp-000001"}, time.Hour)
+	assert.NoError(t, err)
+	own, _, err := token.Sign(token.Claims{Identity: "user-000002", Username: "bob", Role: known.RoleUser, SessionID: "session-000001"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		tokenStr string
+		want     int
+		reason   string
+	}{
+		{name: "impersonated read", method: http.MethodGet, path: "/v1/posts", tokenStr: impersonation, want: http.StatusOK},
+		{name: "impersonated change password", method: http.MethodPut, path: "/v1/users/user-000002/change-password", tokenStr: impersonation, want: http.StatusForbidden, reason: errorsx.ErrImpersonating.Reason},
+		{name: "impersonated delete", method: http.MethodDelete, path: "/v1/users/user-000002", tokenStr: impersonation, want: http.StatusForbidden, reason: errorsx.ErrImpersonating.Reason},
+		// 模拟登录期间不能修改邮箱或发送验证邮件，避免通过修改邮箱接管账号
+		{name: "impersonated update", method: http.MethodPut, path: "/v1/users/user-000002", tokenStr: impersonation, want: http.StatusForbidden, reason: errorsx.ErrImpersonating.Reason},
+		{name: "impersonated verification email", method: http.MethodPost, path: "/v1/users/user-000002/verification-email", tokenStr: impersonation, want: http.StatusForbidden, reason: errorsx.ErrImpersonating.Reason},
+		{name: "own change password", method: http.MethodPut, path: "/v1/users/user-000002/change-password", tokenStr: own, want: http.StatusOK},
+		{name: "own delete", method: http.MethodDelete, path: "/v1/users/user-000002", tokenStr: own, want: http.StatusOK},
+		{name: "own update", method: http.MethodPut, path: "/v1/users/user-000002", tokenStr: own, want: http.StatusOK},
+		{name: "own verification email", method: http.MethodPost, path: "/v1/users/user-000002/verification-email", tokenStr: own, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.method, tt.path, tt.tokenStr)
+			assert.Equal(t, tt.want, w.Code)
+			if tt.reason != "" {
+				assert.Contains(t, w.Body.String(), tt.reason)
+			}
+		})
+	}
+
+	// 吊销实际操作者的 token 后，其签发的模拟登录 token 随之失效，被模拟用户本人的 token 不受影响
+	revoker.users["user-000001"] = true
+	w := do(http.MethodGet, "/v1/posts", impersonation)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), errorsx.ErrTokenRevoked.Reason)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/posts", own).Code)
+}
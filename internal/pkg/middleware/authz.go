@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/gin-gonic/gin"
+)
+
+// Authz 是授权中间件，用于保护路径中带有 :userID 参数的用户资源.
+// 管理员可以访问任意用户的资源，普通用户只能访问自己的资源. 需要在 Authn 中间件之后加载.
+func Authz() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if contextx.Role(ctx) != known.RoleAdmin && c.Param("userID") != contextx.UserID(ctx) {
+			core.WriteResponse(c, nil, errorsx.ErrPermissionDenied)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole 是授权中间件，只允许拥有 roles 中任一角色的用户访问后续的 handler.
+// 需要在 Authn 中间件之后加载.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(roles, contextx.Role(c.Request.Context())) {
+			core.WriteResponse(c, nil, errorsx.ErrPermissionDenied)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
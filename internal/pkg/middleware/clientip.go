@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIP 是一个 Gin 中间件，用来将客户端 IP 注入到请求的 context 中，
+// 方便 biz 等不感知 HTTP 的层级使用（例如：按 IP 去重、限流等）.
+func ClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := contextx.WithClientIP(c.Request.Context(), c.ClientIP())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// encoders 定义支持的压缩算法，键与 Content-Encoding 的取值相同
+var encoders = map[string]func(dst *bytes.Buffer, src []byte) error{
+	"gzip": encodeGzip,
+	"br":   encodeBrotli,
+	"zstd": encodeZstd,
+}
+
+var (
+	gzipWriters   = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+	brotliWriters = sync.Pool{New: func() any { return brotli.NewWriter(nil) }}
+	// zstdEncoder 只使用 EncodeAll 压缩完整的响应体，可以并发使用
+	zstdEncoder = sync.OnceValue(func() *zstd.Encoder {
+		enc, _ := zstd.NewWriter(nil)
+		return enc
+	})
+)
+
+// Compress 是响应压缩中间件，根据请求的 Accept-Encoding 选择 encodings 中的压缩算法压缩响应体.
+// 客户端同等接受多种算法时按 encodings 的顺序选择. 只压缩类型在 contentTypes 中、且不小于 minLength 字节的响应，
+// 已经设置了 Content-Encoding 的响应不会被再次压缩. 可能被压缩的响应会设置 Vary: Accept-Encoding.
+// 压缩后的响应的 ETag 会加上压缩算法后缀，以区分不同编码的表示，客户端带回的 If-None-Match 在交给后续处理前去掉后缀.
+func Compress(minLength int, contentTypes []string, encodings []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(encodings) == 0 || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), encodings)
+		tags := stripETagEncodings(c.Request, encodings)
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		header := c.Writer.Header()
+		body := w.body.Bytes()
+		status := c.Writer.Status()
+
+		switch {
+		case status == http.StatusNotModified:
+			// 304 响应的 ETag 需要与客户端缓存的表示一致
+			header.Add("Vary", "Accept-Encoding")
+			if tag, ok := tags[header.Get("ETag")]; ok {
+				header.Set("ETag", tag)
+			}
+		case compressible(header, contentTypes):
+			header.Add("Vary", "Accept-Encoding")
+			if encoding == "" || len(body) < minLength {
+				break
+			}
+
+			var buf bytes.Buffer
+			if err := encoders[encoding](&buf, body); err != nil {
+				break
+			}
+			body = buf.Bytes()
+			header.Set("Content-Encoding", encoding)
+			header.Del("Content-Length")
+			if etag := header.Get("ETag"); strings.HasSuffix(etag, `"`) {
+				header.Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+encoding+`"`)
+			}
+		}
+
+		if len(body) > 0 {
+			_, _ = c.Writer.Write(body)
+		}
+	}
+}
+
+// negotiateEncoding 根据 Accept-Encoding 从 encodings 中选择客户端最优先接受的压缩算法，
+// 权重相同时按 encodings 的顺序选择. 客户端不接受任何压缩算法时返回空字符串
+func negotiateEncoding(accept string, encodings []string) string {
+	if accept == "" {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if k, v, ok := strings.Cut(strings.TrimSpace(param), "="); ok && k == "q" {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range encodings {
+		q, ok := weights[encoding]
+		if !ok {
+			q, ok = weights["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+
+	return best
+}
+
+// stripETagEncodings 去掉 If-None-Match 中 ETag 的压缩算法后缀，返回去掉后缀的 ETag 到原始 ETag 的映射
+func stripETagEncodings(r *http.Request, encodings []string) map[string]string {
+	inm := r.Header.Get("If-None-Match")
+	if inm == "" {
+		return nil
+	}
+
+	tags := make(map[string]string)
+	parts := strings.Split(inm, ",")
+	for i, part := range parts {
+		tag := strings.TrimSpace(part)
+		for _, encoding := range encodings {
+			if stripped, ok := strings.CutSuffix(tag, "-"+encoding+`"`); ok {
+				// 客户端可能以弱 ETag 的形式带回，按强 ETag 记录，与 ConditionalGet 生成的 ETag 对应
+				tags[strings.TrimPrefix(stripped, "W/")+`"`] = strings.TrimPrefix(tag, "W/")
+				parts[i] = stripped + `"`
+				break
+			}
+		}
+	}
+	r.Header.Set("If-None-Match", strings.Join(parts, ","))
+
+	return tags
+}
+
+// compressible 判断响应的类型是否需要压缩. 已经设置了 Content-Encoding 的响应不再压缩
+func compressible(header http.Header, contentTypes []string) bool {
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(contentTypes, mediaType)
+}
+
+// encodeGzip 使用 gzip 压缩 src
+func encodeGzip(dst *bytes.Buffer, src []byte) error {
+	zw := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(zw)
+
+	zw.Reset(dst)
+	return writeAndClose(zw, src)
+}
+
+// encodeBrotli 使用 brotli 压缩 src
+func encodeBrotli(dst *bytes.Buffer, src []byte) error {
+	bw := brotliWriters.Get().(*brotli.Writer)
+	defer brotliWriters.Put(bw)
+
+	bw.Reset(dst)
+	return writeAndClose(bw, src)
+}
+
+// encodeZstd 使用 zstd 压缩 src
+func encodeZstd(dst *bytes.Buffer, src []byte) error {
+	dst.Write(zstdEncoder().EncodeAll(src, nil))
+	return nil
+}
+
+// writeAndClose 将 src 写入 w 并关闭 w，使压缩后的数据全部写出
+func writeAndClose(w io.WriteCloser, src []byte) error {
+	if _, err := w.Write(src); err != nil {
+		return err
+	}
+	return w.Close()
+}
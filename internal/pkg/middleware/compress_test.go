@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	encodings := []string{"zstd", "br", "gzip"}
+
+	assert.Equal(t, "", negotiateEncoding("", encodings))
+	assert.Equal(t, "gzip", negotiateEncoding("gzip, deflate", encodings))
+	assert.Equal(t, "zstd", negotiateEncoding("gzip, br, zstd", encodings))
+	assert.Equal(t, "br", negotiateEncoding("gzip;q=0.5, br", encodings))
+	assert.Equal(t, "zstd", negotiateEncoding("*", encodings))
+	assert.Equal(t, "gzip", negotiateEncoding("*;q=0.1, gzip", encodings))
+	assert.Equal(t, "", negotiateEncoding("gzip;q=0, identity", encodings))
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"title":"fastgo"}`, 100)
+	engine := gin.New()
+	engine.Use(Compress(1024, []string{"application/json"}, []string{"zstd", "br", "gzip"}))
+	engine.GET("/large", ConditionalGet(), func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(large)) })
+	engine.GET("/small", func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(`{}`)) })
+	engine.GET("/image", func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(large)) })
+
+	get := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		engine.ServeHTTP(w, r)
+		return w
+	}
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"br":   func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		"zstd": func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+	}
+	for encoding, decode := range decoders {
+		w := get("/large", map[string]string{"Accept-Encoding": encoding})
+		assert.Equal(t, encoding, w.Header().Get("Content-Encoding"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+		assert.True(t, strings.HasSuffix(w.Header().Get("ETag"), "-"+encoding+`"`))
+
+		r, err := decode(bytes.NewReader(w.Body.Bytes()))
+		require.NoError(t, err)
+		body, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+
+		// 带回压缩后的 ETag 时返回 304，ETag 与客户端缓存的表示一致
+		etag := w.Header().Get("ETag")
+		w = get("/large", map[string]string{"Accept-Encoding": encoding, "If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	}
+
+	w := get("/small", map[string]string{"Accept-Encoding": "gzip"})
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+	assert.Equal(t, `{}`, w.Body.String())
+
+	w = get("/image", map[string]string{"Accept-Encoding": "gzip"})
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Empty(t, w.Header().Values("Vary"))
+	assert.Equal(t, large, w.Body.String())
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter 缓冲响应体，由使用它的中间件决定最终写入的内容，例如 ConditionalGet 决定写入完整的响应还是 304
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 实现 http.ResponseWriter 接口
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// WriteString 实现 gin.ResponseWriter 接口
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// WriteHeaderNow 实现 gin.ResponseWriter 接口. 状态码已由 WriteHeader 记录，推迟到写入完整响应时再发送，
+// 以便外层中间件在此之前仍然可以修改响应头
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+// ConditionalGet 是条件请求中间件，根据响应体生成强 ETag，并处理 If-None-Match 和 If-Modified-Since 请求头：
+// 客户端缓存的版本仍然有效时返回 304，不返回响应体. Last-Modified 由 Handler 通过 core.SetLastModified 设置.
+// 只处理状态码为 200 的 GET 请求，需要与 CacheControl(CacheRevalidate) 一起使用.
+func ConditionalGet() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if c.Writer.Status() != http.StatusOK {
+			_, _ = c.Writer.Write(w.body.Bytes())
+			return
+		}
+
+		// 响应因调用方而异，缓存需要按 Authorization 请求头区分
+		header := c.Writer.Header()
+		header.Add("Vary", "Authorization")
+		etag := strongETag(w.body.Bytes())
+		header.Set("ETag", etag)
+
+		if notModified(c.Request, etag, header.Get("Last-Modified")) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+
+		_, _ = c.Writer.Write(w.body.Bytes())
+	}
+}
+
+// strongETag 根据响应体生成强 ETag，响应体完全相同时 ETag 相同
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified 判断客户端缓存的版本是否仍然有效. 按照 RFC 9110，请求携带 If-None-Match 时忽略 If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			// If-None-Match 使用弱比较，忽略 W/ 前缀
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+
+	return !modified.Truncate(time.Second).After(since)
+}
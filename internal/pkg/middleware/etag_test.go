@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotModified(t *testing.T) {
+	const etag = `"abc"`
+	lastModified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		header       map[string]string
+		lastModified string
+		want         bool
+	}{
+		{name: "no condition", want: false},
+		{name: "etag match", header: map[string]string{"If-None-Match": `"abc"`}, want: true},
+		{name: "weak etag match", header: map[string]string{"If-None-Match": `W/"abc"`}, want: true},
+		{name: "etag in list", header: map[string]string{"If-None-Match": `"xyz", "abc"`}, want: true},
+		{name: "wildcard", header: map[string]string{"If-None-Match": "*"}, want: true},
+		{name: "etag mismatch", header: map[string]string{"If-None-Match": `"xyz"`}, want: false},
+		{
+			// 携带 If-None-Match 时忽略 If-Modified-Since
+			name: "etag mismatch ignores if-modified-since",
+			header: map[string]string{
+				"If-None-Match":     `"xyz"`,
+				"If-Modified-Since": lastModified.Format(http.TimeFormat),
+			},
+			lastModified: lastModified.Format(http.TimeFormat),
+			want:         false,
+		},
+		{
+			name:         "not modified since",
+			header:       map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			lastModified: lastModified.Format(http.TimeFormat),
+			want:         true,
+		},
+		{
+			name:         "modified since",
+			header:       map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)},
+			lastModified: lastModified.Format(http.TimeFormat),
+			want:         false,
+		},
+		{
+			name:         "no last-modified",
+			header:       map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			lastModified: "",
+			want:         false,
+		},
+		{
+			name:         "invalid if-modified-since",
+			header:       map[string]string{"If-Modified-Since": "yesterday"},
+			lastModified: lastModified.Format(http.TimeFormat),
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			assert.Equal(t, tt.want, notModified(r, etag, tt.lastModified))
+		})
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	body := `{"title":"fastgo"}`
+	lastModified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Format(http.TimeFormat)
+	engine := gin.New()
+	engine.Use(ConditionalGet())
+	engine.GET("/post", func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(body)) })
+	engine.GET("/posts/:postID", func(c *gin.Context) {
+		c.Header("Last-Modified", lastModified)
+		c.Data(http.StatusOK, "application/json", []byte(body))
+	})
+	engine.GET("/user", func(c *gin.Context) {
+		c.Header("Last-Modified", lastModified)
+		c.Data(http.StatusOK, "application/json", []byte(body))
+	})
+	engine.GET("/missing", func(c *gin.Context) { c.Data(http.StatusNotFound, "application/json", []byte(`{}`)) })
+	engine.POST("/post", func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(body)) })
+
+	do := func(method string, path string, header map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, nil)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		engine.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do(http.MethodGet, "/post", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	assert.Contains(t, w.Header().Values("Vary"), "Authorization")
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, strongETag([]byte(body)), etag)
+
+	// 带回相同的 ETag 时返回 304，不返回响应体
+	w = do(http.MethodGet, "/post", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Type"))
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	// 没有设置 Last-Modified 时忽略 If-Modified-Since
+	w = do(http.MethodGet, "/post", map[string]string{"If-Modified-Since": lastModified})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = do(http.MethodGet, "/user", map[string]string{"If-Modified-Since": lastModified})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// 博客详情设置了 Last-Modified，未修改时返回 304
+	w = do(http.MethodGet, "/posts/post-000001", map[string]string{"If-Modified-Since": lastModified})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, lastModified, w.Header().Get("Last-Modified"))
+
+	// 博客在 If-Modified-Since 之后被修改时返回完整响应
+	earlier := time.Date(2026, 1, 2, 3, 4, 4, 0, time.UTC).Format(http.TimeFormat)
+	w = do(http.MethodGet, "/posts/post-000001", map[string]string{"If-Modified-Since": earlier})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	assert.Equal(t, lastModified, w.Header().Get("Last-Modified"))
+
+	// 同时带有 If-None-Match 时以 ETag 为准，例如浏览次数变化但 UpdatedAt 未变化
+	w = do(http.MethodGet, "/posts/post-000001", map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": lastModified})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+
+	// 只处理状态码为 200 的 GET 请求
+	w = do(http.MethodGet, "/missing", map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	w = do(http.MethodPost, "/post", map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"))
+}
+
+func TestConditionalGetCompressed(t *testing.T) {
+	body := strings.Repeat(`{"title":"fastgo"}`, 100)
+	engine := gin.New()
+	engine.Use(Compress(1024, []string{"application/json"}, []string{"gzip"}))
+	engine.GET("/posts", ConditionalGet(), func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(body)) })
+
+	get := func(header map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		engine.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get(map[string]string{"Accept-Encoding": "gzip"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, strings.TrimSuffix(strongETag([]byte(body)), `"`)+`-gzip"`, etag)
+
+	// 压缩后的 ETag 带回时同样可以命中，作为弱 ETag 或列表中的一项时也是如此
+	for _, inm := range []string{etag, "W/" + etag, `"xyz", ` + etag} {
+		w = get(map[string]string{"Accept-Encoding": "gzip", "If-None-Match": inm})
+		assert.Equal(t, http.StatusNotModified, w.Code, inm)
+		assert.Equal(t, etag, w.Header().Get("ETag"), inm)
+		assert.Empty(t, w.Body.String(), inm)
+	}
+
+	// 响应体变化后 ETag 不再匹配
+	body = strings.Repeat(`{"title":"changed"}`, 100)
+	w = get(map[string]string{"Accept-Encoding": "gzip", "If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
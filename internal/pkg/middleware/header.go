@@ -1,18 +1,25 @@
 package middleware
 
 import (
-	"net/http"
-	"time"
-
 	"github.com/gin-gonic/gin"
 )
 
-// NoCache 是一个 Gin 中间件，用来禁止客户端缓存 HTTP 请求的返回结果
-func NoCache(c *gin.Context) {
-	c.Header("Cache-Control", "no-cache, no-store, max-age=0, must-revalidate, value")
-	c.Header("Expires", "Thu, 01 Jan 1970 00:00:00 GMT")
-	c.Header("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
-	c.Next()
+// 常用的缓存策略
+const (
+	// CacheNoStore 禁止客户端和代理缓存响应，用于包含敏感信息或每次都可能变化的响应
+	CacheNoStore = "no-store"
+	// CacheRevalidate 允许客户端缓存响应，但每次使用前需要通过 ETag 或 Last-Modified 向服务端确认，
+	// 不允许代理等共享缓存保存
+	CacheRevalidate = "private, no-cache"
+)
+
+// CacheControl 是一个 Gin 中间件，用来设置响应的缓存策略. 同一请求中后加载的 CacheControl 会覆盖先加载的，
+// 因此可以全局设置默认的缓存策略，再为个别路由设置不同的缓存策略
+func CacheControl(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", policy)
+		c.Next()
+	}
 }
 
 // Cors 是一个 Gin 中间件，用来设置 options 请求的返回头，然后退出中间件链，并结束请求（浏览器跨域设置）
@@ -22,7 +29,7 @@ func Cors(c *gin.Context) {
 	} else {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,HEAD,OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "authorization, origin, content-type, accept")
+		c.Header("Access-Control-Allow-Headers", "authorization, origin, content-type, accept, idempotency-key, if-none-match, if-modified-since")
 		c.Header("Allow", "HEAD,GET,POST,PUT,PATCH,DELETE,OPTIONS")
 		c.Header("Content-Type", "application/json")
 		c.AbortWithStatus(200)
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/idempotency"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// idempotencyKeyHeader 是客户端传入幂等 key 的请求头
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader 是标记响应为重放的响应头
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength 是幂等 key 的最大长度
+	maxIdempotencyKeyLength = 255
+)
+
+// bodyRecorder 在写入响应的同时保存响应体
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 实现 http.ResponseWriter 接口
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString 实现 gin.ResponseWriter 接口
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency 是幂等中间件，使客户端可以通过 Idempotency-Key 请求头安全地重试创建资源的请求.
+// 同一个调用方使用同一个 key 重复请求同一个接口时，直接返回第一次请求的响应，并设置 Idempotent-Replayed 头；
+// key 相同但请求体不同时返回 409；第一次请求仍在处理中时，并发的重复请求返回 409.
+// 5xx 以及 408、425、429 等可重试的响应不会被保存，客户端可以使用同一个 key 重试. 未携带 Idempotency-Key 的请求不受影响，
+// 存储出错时放行请求. 需要在 ClientIP 和 Authn 中间件之后加载，以便按用户或客户端 IP 区分 key.
+func Idempotency(store idempotency.Store, ttl time.Duration, lockTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			core.WriteResponse(c, nil, errorsx.ErrIdempotencyKeyInvalid)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			core.WriteResponse(c, nil, errorsx.ErrBind)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// key 按调用方和接口隔离，不同用户或不同接口使用相同的 key 互不影响.
+		// 未认证的调用方（例如注册用户）按客户端 IP 隔离，避免无关的客户端使用相同的 key 时相互影响
+		ctx := c.Request.Context()
+		caller := contextx.UserID(ctx)
+		if caller == "" {
+			caller = "anonymous:" + contextx.ClientIP(ctx)
+		}
+		route := c.Request.Method + " " + c.FullPath()
+		storeKey := "idempotency:" + caller + ":" + route + ":" + key
+		fingerprint := requestFingerprint(route, body)
+
+		record, err := store.Reserve(ctx, storeKey, fingerprint, lockTimeout)
+		if err != nil {
+			slog.WarnContext(ctx, "Idempotency store unavailable, processing request without idempotency", "err", err)
+			c.Next()
+			return
+		}
+		if record != nil {
+			switch {
+			case record.Fingerprint != fingerprint:
+				core.WriteResponse(c, nil, errorsx.ErrIdempotencyKeyReused)
+			case !record.Completed:
+				core.WriteResponse(c, nil, errorsx.ErrIdempotencyRequestInProgress)
+			default:
+				c.Header(idempotentReplayedHeader, "true")
+				c.Data(record.Status, record.ContentType, record.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		// 处理请求期间发生 panic 或返回可重试的响应时释放 key，使客户端可以重试
+		completed := false
+		defer func() {
+			if !completed {
+				if err := store.Release(context.WithoutCancel(ctx), storeKey); err != nil {
+					slog.WarnContext(ctx, "Failed to release idempotency key", "err", err)
+				}
+			}
+		}()
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		c.Writer = recorder.ResponseWriter
+
+		status := c.Writer.Status()
+		if retryable(status) {
+			return
+		}
+
+		completed = true
+		if err := store.Complete(context.WithoutCancel(ctx), storeKey, &idempotency.Record{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}, ttl); err != nil {
+			slog.WarnContext(ctx, "Failed to save idempotent response", "err", err)
+		}
+	}
+}
+
+// retryable 判断响应是否可以重试. 可重试的响应（例如被限流）不是请求的最终结果，不应保存
+func retryable(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return status >= http.StatusInternalServerError
+}
+
+// requestFingerprint 根据请求的接口和请求体计算请求的指纹
+func requestFingerprint(route string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(route))
+	h.Write([]byte{'\n'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
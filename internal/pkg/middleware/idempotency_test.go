@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/idempotency"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyAnonymousCallers(t *testing.T) {
+	created := 0
+	engine := gin.New()
+	engine.Use(ClientIP(), Idempotency(idempotency.NewMemory(), time.Hour, time.Minute))
+	engine.POST("/users", func(c *gin.Context) {
+		created++
+		c.JSON(http.StatusOK, gin.H{"count": created})
+	})
+
+	post := func(ip string, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.RemoteAddr = ip + ":12345"
+		req.Header.Set("Idempotency-Key", "1")
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	// 同一个客户端重试时重放第一次的响应
+	w := post("192.0.2.1", `{"username":"alice"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = post("192.0.2.1", `{"username":"alice"}`)
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 1, created)
+
+	// 其他客户端使用相同的 key 时互不影响
+	w = post("192.0.2.2", `{"username":"bob"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 2, created)
+}
+
+func TestIdempotencyRetryableResponse(t *testing.T) {
+	limited := true
+	engine := gin.New()
+	engine.Use(ClientIP(), Idempotency(idempotency.NewMemory(), time.Hour, time.Minute))
+	engine.POST("/posts", func(c *gin.Context) {
+		// 第一次请求被限流，之后的请求正常处理
+		if limited {
+			limited = false
+			c.JSON(http.StatusTooManyRequests, gin.H{"reason": "ResourceExhausted.TooManyRequests"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"postID": "post-000001"})
+	})
+
+	post := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(`{"title":"fastgo"}`))
+		req.Header.Set("Idempotency-Key", "1")
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	// 被限流的响应不会被保存，客户端使用同一个 key 重试时重新处理请求
+	w := post()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	w = post()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+
+	// 成功的响应被保存，之后的重试直接重放
+	w = post()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+}
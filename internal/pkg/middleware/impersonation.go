@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/gin-gonic/gin"
+)
+
+// NoImpersonation 禁止在模拟登录期间访问，用于修改密码、修改邮箱、删除账号、管理令牌和两步验证等
+// 只应由账号本人执行的操作. 需要在 Authn 中间件之后加载.
+func NoImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if contextx.ActorID(c.Request.Context()) != "" {
+			core.WriteResponse(c, nil, errorsx.ErrImpersonating)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// auditImpersonation 记录模拟登录期间的请求，日志中同时包含实际操作者和被模拟用户的 ID
+func auditImpersonation(c *gin.Context) {
+	ctx := c.Request.Context()
+	slog.InfoContext(ctx, "Impersonated request",
+		"requestID", contextx.RequestID(ctx),
+		"actorID", contextx.ActorID(ctx),
+		"userID", contextx.UserID(ctx),
+		"impersonationID", contextx.TokenID(ctx),
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"status", c.Writer.Status(),
+		"clientIP", contextx.ClientIP(ctx),
+	)
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute 是未匹配任何路由的请求使用的路由标签，避免将任意请求路径作为标签值
+const unmatchedRoute = "unmatched"
+
+// Metrics 是指标中间件，记录每个请求的次数和耗时，按请求方法、路由模板和状态码区分.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKeyFunc 返回请求所属的限流维度，维度相同的请求共享同一个令牌桶.
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIP 按客户端 IP 限流. 需要在 ClientIP 中间件之后加载.
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + contextx.ClientIP(c.Request.Context())
+}
+
+// RateLimitByUser 按用户限流，未认证的请求按客户端 IP 限流. 需要在 Authn 中间件之后加载.
+func RateLimitByUser(c *gin.Context) string {
+	if userID := contextx.UserID(c.Request.Context()); userID != "" {
+		return "user:" + userID
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByRoute 按路由限流，同一路由的所有请求共享一个令牌桶.
+func RateLimitByRoute(c *gin.Context) string {
+	return "route:" + c.Request.Method + " " + c.FullPath()
+}
+
+// RateLimit 是限流中间件，使用令牌桶算法限制同一维度的请求速率. name 是限流规则的名称，
+// 不同规则的令牌桶相互独立. 响应中会返回 RateLimit-Limit、RateLimit-Remaining 和 RateLimit-Reset 头，
+// 超出限制时返回 429 及 Retry-After 头. 限流器出错时放行请求，避免限流存储故障导致服务不可用.
+func RateLimit(limiter ratelimit.Limiter, name string, limit ratelimit.Limit, key RateLimitKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r, err := limiter.Allow(c.Request.Context(), "ratelimit:"+name+":"+key(c), limit)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "Rate limiter unavailable, allowing request", "rule", name, "err", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(r.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(r.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(r.ResetAfter)))
+
+		if !r.Allowed {
+			core.WriteResponse(c, nil, errorsx.WithRetryAfter(errorsx.ErrTooManyRequests, r.RetryAfter))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ceilSeconds 将时长向上取整为秒
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLimit 是限制请求体大小和请求处理时限的中间件. 请求体超过 maxBodySize 字节时返回 413；
+// 请求的 context 在 timeout 后到期，使用该 context 的数据库查询等操作会被取消，
+// 到期导致的服务端错误由 core.WriteResponse 转换为 504. 同一个请求只应加载一次.
+func RequestLimit(maxBodySize int64, timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBodySize {
+			core.WriteResponse(c, nil, errorsx.ErrRequestEntityTooLarge)
+			c.Abort()
+			return
+		}
+
+		// 未声明 Content-Length 的请求（例如分块传输）需要读取后才能知道请求体的大小，
+		// 最多读取 maxBodySize+1 个字节，避免读取任意大的请求体
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBodySize+1))
+			if err != nil {
+				core.WriteResponse(c, nil, errorsx.ErrBind)
+				c.Abort()
+				return
+			}
+			if int64(len(body)) > maxBodySize {
+				core.WriteResponse(c, nil, errorsx.ErrRequestEntityTooLarge)
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
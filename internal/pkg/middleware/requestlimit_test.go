@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLimit(t *testing.T) {
+	engine := gin.New()
+	engine.Use(RequestLimit(16, 20*time.Millisecond))
+	engine.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		c.String(http.StatusOK, string(body))
+	})
+	engine.GET("/slow", func(c *gin.Context) {
+		// 模拟被取消的慢查询
+		<-c.Request.Context().Done()
+		core.WriteResponse(c, nil, fmt.Errorf("query: %w", c.Request.Context().Err()))
+	})
+
+	post := func(body string, chunked bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body))
+		if chunked {
+			req.ContentLength = -1
+		}
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("hello", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+
+	w = post(strings.Repeat("a", 17), false)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "InvalidArgument.RequestEntityTooLarge")
+
+	w = post(strings.Repeat("a", 17), true)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), "DeadlineExceeded.RequestTimeout")
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/core"
+	"github.com/TobyIcetea/fastgo/internal/pkg/errorsx"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScopes 声明路由需要的权限范围. 使用个人访问令牌访问时，令牌必须拥有 scopes 中的所有权限范围；
+// 使用登录获得的 token 访问时不受限制. 需要在 Authn 中间件之后加载.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := contextx.Scopes(c.Request.Context())
+		if granted == nil {
+			c.Next()
+			return
+		}
+
+		for _, scope := range scopes {
+			if !slices.Contains(granted, scope) {
+				core.WriteResponse(c, nil, errorsx.ErrScopeInsufficient)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// SessionOnly 禁止使用个人访问令牌访问，只允许使用登录获得的 token 访问.
+// 用于管理令牌、修改密码、注销登录等敏感操作. 需要在 Authn 中间件之后加载.
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if contextx.Scopes(c.Request.Context()) != nil {
+			core.WriteResponse(c, nil, errorsx.ErrAccessTokenNotAllowed)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/TobyIcetea/fastgo/internal/pkg/known"
+	"github.com/TobyIcetea/fastgo/internal/pkg/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing 是链路追踪中间件，从请求头的 traceparent 中恢复上游的链路，为每个请求创建一个 span，
+// 并通过 `x-trace-id` 响应头返回 trace ID. 需要在 AccessLog 中间件之前加载，以便访问日志中包含 trace ID.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		// span 名称使用路由模板，避免将任意请求路径作为 span 名称
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		ctx, span := tracing.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("code.function.name", c.HandlerName()),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		if traceID := contextx.TraceID(ctx); traceID != "" {
+			c.Writer.Header().Set(known.XTraceID, traceID)
+		}
+
+		c.Next()
+
+		// c.Request 在认证中间件中被替换为携带用户信息的请求，这里读取的是处理完成后的 context
+		ctx = c.Request.Context()
+		status := c.Writer.Status()
+		span.SetAttributes(
+			attribute.Int("http.response.status_code", status),
+			attribute.String("client.address", contextx.ClientIP(ctx)),
+		)
+		if userID := contextx.UserID(ctx); userID != "" {
+			span.SetAttributes(attribute.String("enduser.id", userID))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
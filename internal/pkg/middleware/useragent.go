@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/TobyIcetea/fastgo/internal/pkg/contextx"
+	"github.com/gin-gonic/gin"
+)
+
+// maxUserAgentLength 定义注入到 context 中的 User-Agent 的最大长度，过长的部分会被截断
+const maxUserAgentLength = 512
+
+// UserAgent 是一个 Gin 中间件，用来将客户端 User-Agent 注入到请求的 context 中，
+// 方便 biz 层记录登录会话的设备信息.
+func UserAgent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userAgent := c.Request.UserAgent()
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = userAgent[:maxUserAgentLength]
+		}
+
+		ctx := contextx.WithUserAgent(c.Request.Context(), userAgent)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 定义清理已装满令牌桶的周期，已装满的桶与不存在的桶等价
+const sweepInterval = time.Minute
+
+// bucket 是一个令牌桶的状态
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// Memory 是基于内存的限流器，限流状态只在当前实例内生效
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	// now 返回当前时间，便于测试
+	now func() time.Time
+}
+
+// 确保 Memory 实现了 Limiter 接口
+var _ Limiter = (*Memory)(nil)
+
+// NewMemory 创建一个基于内存的限流器
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Allow 实现 Limiter 接口中的 Allow 方法
+func (m *Memory) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		m.buckets[key] = b
+	}
+
+	tokens, r := take(limit, b.tokens, now.Sub(b.last))
+	b.limit, b.tokens, b.last = limit, tokens, now
+
+	return r, nil
+}
+
+// sweep 周期性地删除已经重新装满的令牌桶，避免内存无限增长
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, b := range m.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package ratelimit 实现基于令牌桶算法的限流器，支持内存和 Redis 两种存储.
+// 内存存储只在单个实例内生效，多实例部署时应使用 Redis 存储共享限流状态.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit 定义一个令牌桶：桶的容量为 Burst，每秒补充 Rate 个令牌，每个请求消耗一个令牌
+type Limit struct {
+	// Rate 是每秒补充的令牌数
+	Rate float64
+	// Burst 是桶的容量，即允许的最大突发请求数
+	Burst int
+}
+
+// Every 返回在 period 内平均允许 requests 个请求、最多突发 burst 个请求的 Limit
+func Every(requests int, period time.Duration, burst int) Limit {
+	return Limit{Rate: float64(requests) / period.Seconds(), Burst: burst}
+}
+
+// Result 是一次限流检查的结果
+type Result struct {
+	// Allowed 表示请求是否被允许
+	Allowed bool
+	// Limit 是桶的容量
+	Limit int
+	// Remaining 是本次请求之后桶中剩余的完整令牌数
+	Remaining int
+	// ResetAfter 是桶重新装满所需的时间
+	ResetAfter time.Duration
+	// RetryAfter 是请求被拒绝时，距离下一个令牌可用的时间
+	RetryAfter time.Duration
+}
+
+// Limiter 定义限流器，检查 key 对应的令牌桶中是否还有令牌，有则消耗一个
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// take 根据桶中上一次的令牌数 tokens 和距离上一次的时间 elapsed，尝试消耗一个令牌，
+// 返回消耗之后的令牌数及检查结果
+func take(limit Limit, tokens float64, elapsed time.Duration) (float64, *Result) {
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+
+	return tokens, newResult(limit, tokens, allowed)
+}
+
+// newResult 根据桶中当前的令牌数生成检查结果
+func newResult(limit Limit, tokens float64, allowed bool) *Result {
+	r := &Result{
+		Allowed:    allowed,
+		Limit:      limit.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		r.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return r
+}
+
+// seconds 将秒数转换为 time.Duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
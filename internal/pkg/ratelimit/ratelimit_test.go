@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLimiter 使用可控的时钟验证令牌桶的行为
+func testLimiter(t *testing.T, l Limiter, advance func(time.Duration)) {
+	ctx := context.Background()
+	limit := Every(1, time.Second, 3)
+
+	for i := 2; i >= 0; i-- {
+		r, err := l.Allow(ctx, "k", limit)
+		require.NoError(t, err)
+		assert.True(t, r.Allowed)
+		assert.Equal(t, 3, r.Limit)
+		assert.Equal(t, i, r.Remaining)
+	}
+
+	r, err := l.Allow(ctx, "k", limit)
+	require.NoError(t, err)
+	assert.False(t, r.Allowed)
+	assert.Equal(t, time.Second, r.RetryAfter)
+	assert.Equal(t, 3*time.Second, r.ResetAfter)
+
+	// 其他 key 不受影响
+	r, err = l.Allow(ctx, "other", limit)
+	require.NoError(t, err)
+	assert.True(t, r.Allowed)
+
+	advance(1500 * time.Millisecond)
+	r, err = l.Allow(ctx, "k", limit)
+	require.NoError(t, err)
+	assert.True(t, r.Allowed)
+	assert.Equal(t, 0, r.Remaining)
+
+	r, err = l.Allow(ctx, "k", limit)
+	require.NoError(t, err)
+	assert.False(t, r.Allowed)
+	assert.Equal(t, 500*time.Millisecond, r.RetryAfter)
+}
+
+func TestMemory(t *testing.T) {
+	now := time.Now()
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	testLimiter(t, m, func(d time.Duration) { now = now.Add(d) })
+}
+
+func TestRedis(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	now := time.Now()
+	r := NewRedis(client)
+	r.now = func() time.Time { return now }
+
+	testLimiter(t, r, func(d time.Duration) { now = now.Add(d) })
+	assert.True(t, s.Exists("k"))
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// script 在 Redis 中原子地执行一次令牌桶检查. 令牌桶保存在 Hash 中，
+// 过期时间设置为桶重新装满所需的时间，装满的桶与不存在的桶等价.
+// 令牌数以字符串返回，避免 Lua 数字转换为 Redis 整数时丢失小数部分.
+var script = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// Redis 是基于 Redis 的限流器，多个实例共享限流状态
+type Redis struct {
+	client redis.Scripter
+	// now 返回当前时间，便于测试. 使用各实例的本地时间，实例之间的时钟偏差会影响令牌的补充速度
+	now func() time.Time
+}
+
+// 确保 Redis 实现了 Limiter 接口
+var _ Limiter = (*Redis)(nil)
+
+// NewRedis 创建一个基于 Redis 的限流器
+func NewRedis(client redis.Scripter) *Redis {
+	return &Redis{client: client, now: time.Now}
+}
+
+// Allow 实现 Limiter 接口中的 Allow 方法
+func (r *Redis) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	now := r.now().UnixMilli()
+	values, err := script.Run(ctx, r.client, []string{key}, limit.Rate, limit.Burst, now).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, _ := values[0].(int64)
+	str, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(str, 64)
+	if err != nil || math.IsNaN(tokens) {
+		tokens = 0
+	}
+
+	return newResult(limit, tokens, allowed == 1), nil
+}
@@ -13,6 +13,14 @@ const (
 	UserID ResourceID = "user"
 	// PostID 定义博文资源标识符
 	PostID ResourceID = "post"
+	// ReportID 定义举报资源标识符
+	ReportID ResourceID = "report"
+	// AccessTokenID 定义个人访问令牌资源标识符
+	AccessTokenID ResourceID = "pat"
+	// SessionID 定义登录会话资源标识符
+	SessionID ResourceID = "ses"
+	// ImpersonationID 定义模拟登录资源标识符
+	ImpersonationID ResourceID = "imp"
 )
 
 // string 将资源标识符转换为字符串
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey 是 span 在 gorm.Statement 中的保存键
+const gormSpanKey = "tracing:span"
+
+// gormPlugin 为每条 GORM 语句创建一个 span，记录 SQL 语句、表名和影响的行数.
+// 只有通过 db.WithContext 传入了 context 的语句才能关联到请求的链路中
+type gormPlugin struct{}
+
+// 确保 gormPlugin 实现了 gorm.Plugin 接口
+var _ gorm.Plugin = (*gormPlugin)(nil)
+
+// NewGormPlugin 创建为 GORM 语句创建 span 的插件，使用 db.Use 注册
+func NewGormPlugin() gorm.Plugin {
+	return &gormPlugin{}
+}
+
+// Name 实现 gorm.Plugin 接口
+func (p *gormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize 实现 gorm.Plugin 接口，在各类语句执行前后注册回调
+func (p *gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, proc := range processors {
+		if err := proc.before("tracing:before_"+proc.operation, before(proc.operation)); err != nil {
+			return err
+		}
+		if err := proc.after("tracing:after_"+proc.operation, after); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// before 在语句执行前创建 span
+func before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			return
+		}
+		_, span := Start(ctx, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system.name", "mysql"), attribute.String("db.operation.name", operation)),
+		)
+		db.Statement.Settings.Store(gormSpanKey, span)
+	}
+}
+
+// after 在语句执行后记录语句信息并结束 span
+func after(db *gorm.DB) {
+	v, ok := db.Statement.Settings.LoadAndDelete(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := v.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.query.text", db.Statement.SQL.String()),
+		attribute.String("db.collection.name", db.Statement.Table),
+		attribute.Int64("db.response.returned_rows", db.Statement.RowsAffected),
+	)
+	// 记录不存在属于正常的查询结果，不作为错误记录
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		RecordError(span, db.Error)
+	}
+}
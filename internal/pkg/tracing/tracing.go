@@ -0,0 +1,40 @@
+// Package tracing 封装 fg-apiserver 使用的 OpenTelemetry 链路追踪，包括创建 span 的辅助函数
+// 以及为每条 GORM 语句创建 span 的插件. TracerProvider 由 options.TracingOptions 创建.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 是 fg-apiserver 创建的 span 所属的 instrumentation scope
+const instrumentationName = "github.com/TobyIcetea/fastgo"
+
+// Propagator 返回用于在 HTTP 头中传递链路信息的 W3C Trace Context 传播器
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// Tracer 返回 fg-apiserver 使用的 Tracer. 每次调用时从全局 TracerProvider 获取，
+// 因此在 otel.SetTracerProvider 之前创建的 span 不会被导出
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start 创建一个 span，返回携带该 span 的 context. 调用方需要调用 span.End() 结束 span
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// RecordError 记录 err 并将 span 的状态设置为 Error，err 为 nil 时不做任何操作
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
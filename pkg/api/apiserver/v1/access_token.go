@@ -0,0 +1,65 @@
+package v1
+
+import "time"
+
+// AccessToken 表示个人访问令牌信息，不包含令牌值
+type AccessToken struct {
+	// tokenID 表示令牌 ID
+	TokenID string `json:"tokenID"`
+	// name 表示令牌名称
+	Name string `json:"name"`
+	// scopes 表示令牌权限范围
+	Scopes []string `json:"scopes"`
+	// expiresAt 表示令牌过期时间，为空表示永不过期
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// lastUsedAt 表示令牌最后使用时间
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	// createAt 表示令牌创建时间
+	CreateAt time.Time `json:"createAt"`
+}
+
+// CreateAccessTokenRequest 表示创建个人访问令牌请求
+type CreateAccessTokenRequest struct {
+	// name 表示令牌名称
+	Name string `json:"name"`
+	// scopes 表示令牌权限范围，例如 posts:read、posts:write
+	Scopes []string `json:"scopes"`
+	// expiresAt 表示可选的令牌过期时间，为空表示永不过期
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// CreateAccessTokenResponse 表示创建个人访问令牌响应
+type CreateAccessTokenResponse struct {
+	// tokenID 表示新创建的令牌 ID
+	TokenID string `json:"tokenID"`
+	// token 表示令牌值，只在创建时返回一次
+	Token string `json:"token"`
+	// expiresAt 表示令牌过期时间
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// DeleteAccessTokenRequest 表示吊销个人访问令牌请求
+type DeleteAccessTokenRequest struct {
+	// tokenID 表示要吊销的令牌 ID，对应 {tokenID}
+	TokenID string `json:"tokenID" uri:"tokenID"`
+}
+
+// DeleteAccessTokenResponse 表示吊销个人访问令牌响应
+type DeleteAccessTokenResponse struct {
+}
+
+// ListAccessTokenRequest 表示获取个人访问令牌列表请求
+type ListAccessTokenRequest struct {
+	// offset 表示偏移量
+	Offset int64 `json:"offset" form:"offset"`
+	// limit 表示每页数量
+	Limit int64 `json:"limit" form:"limit"`
+}
+
+// ListAccessTokenResponse 表示获取个人访问令牌列表响应
+type ListAccessTokenResponse struct {
+	// totalCount 表示令牌总数
+	TotalCount int64 `json:"totalCount"`
+	// accessTokens 表示令牌列表
+	AccessTokens []*AccessToken `json:"accessTokens"`
+}
@@ -0,0 +1,71 @@
+package v1
+
+import "time"
+
+// Collaborator 表示博客协作者
+type Collaborator struct {
+	// postID 表示博文 ID
+	PostID string `json:"postID"`
+	// userID 表示协作者的用户 ID
+	UserID string `json:"userID"`
+	// role 表示协作者角色，可选值：editor、viewer
+	Role string `json:"role"`
+	// createAt 表示协作者添加时间
+	CreateAt time.Time `json:"createAt"`
+}
+
+// AddCollaboratorRequest 表示添加（邀请）协作者请求
+type AddCollaboratorRequest struct {
+	// postID 表示博文 ID，对应 {postID}
+	PostID string `json:"postID" uri:"postID"`
+	// userID 表示被邀请的用户 ID
+	UserID string `json:"userID"`
+	// role 表示协作者角色，可选值：editor、viewer
+	Role string `json:"role"`
+}
+
+// AddCollaboratorResponse 表示添加协作者响应
+type AddCollaboratorResponse struct {
+}
+
+// RemoveCollaboratorRequest 表示移除协作者请求
+type RemoveCollaboratorRequest struct {
+	// postID 表示博文 ID，对应 {postID}
+	PostID string `json:"postID" uri:"postID"`
+	// userID 表示要移除的协作者用户 ID，对应 {userID}
+	UserID string `json:"userID" uri:"userID"`
+}
+
+// RemoveCollaboratorResponse 表示移除协作者响应
+type RemoveCollaboratorResponse struct {
+}
+
+// ListCollaboratorRequest 表示获取协作者列表请求
+type ListCollaboratorRequest struct {
+	// postID 表示博文 ID，对应 {postID}
+	PostID string `json:"postID" uri:"postID"`
+}
+
+// ListCollaboratorResponse 表示获取协作者列表响应
+type ListCollaboratorResponse struct {
+	// totalCount 表示协作者总数
+	TotalCount int64 `json:"totalCount"`
+	// collaborators 表示协作者列表
+	Collaborators []*Collaborator `json:"collaborators"`
+}
+
+// ListSharedPostRequest 表示获取共享给我的博客列表请求
+type ListSharedPostRequest struct {
+	// offset 表示偏移量
+	Offset int64 `json:"offset" form:"offset"`
+	// limit 表示每页数量
+	Limit int64 `json:"limit" form:"limit"`
+}
+
+// ListSharedPostResponse 表示获取共享给我的博客列表响应
+type ListSharedPostResponse struct {
+	// totalCount 表示共享给我的博客总数
+	TotalCount int64 `json:"totalCount"`
+	// posts 表示博客列表
+	Posts []*Post `json:"posts"`
+}
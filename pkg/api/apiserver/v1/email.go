@@ -0,0 +1,43 @@
+package v1
+
+// VerifyEmailRequest 表示验证邮箱请求
+type VerifyEmailRequest struct {
+	// token 表示验证邮件中链接携带的 token
+	Token string `json:"token"`
+}
+
+// VerifyEmailResponse 表示验证邮箱响应
+type VerifyEmailResponse struct {
+}
+
+// SendVerificationEmailRequest 表示重新发送验证邮件请求
+type SendVerificationEmailRequest struct {
+	// userID 表示要验证邮箱的用户 ID，对应 {userID}
+	UserID string `json:"userID" uri:"userID"`
+}
+
+// SendVerificationEmailResponse 表示重新发送验证邮件响应
+type SendVerificationEmailResponse struct {
+}
+
+// ForgotPasswordRequest 表示忘记密码请求
+type ForgotPasswordRequest struct {
+	// email 表示注册时填写的电子邮箱，重置密码的链接会发送到该邮箱
+	Email string `json:"email"`
+}
+
+// ForgotPasswordResponse 表示忘记密码响应. 无论邮箱是否已注册都返回成功，避免泄露注册信息
+type ForgotPasswordResponse struct {
+}
+
+// ResetPasswordRequest 表示重置密码请求
+type ResetPasswordRequest struct {
+	// token 表示重置密码邮件中链接携带的 token
+	Token string `json:"token"`
+	// newPassword 表示新密码
+	NewPassword string `json:"newPassword"`
+}
+
+// ResetPasswordResponse 表示重置密码响应
+type ResetPasswordResponse struct {
+}
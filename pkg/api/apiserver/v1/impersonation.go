@@ -0,0 +1,73 @@
+package v1
+
+import "time"
+
+// Impersonation 表示一次管理员模拟登录的审计记录
+type Impersonation struct {
+	// impersonationID 表示模拟登录 ID，同时也是模拟登录 token 的 jti
+	ImpersonationID string `json:"impersonationID"`
+	// actorID 表示发起模拟登录的管理员用户 ID
+	ActorID string `json:"actorID"`
+	// userID 表示被模拟的用户 ID
+	UserID string `json:"userID"`
+	// reason 表示模拟登录原因
+	Reason string `json:"reason"`
+	// clientIP 表示发起模拟登录的客户端 IP
+	ClientIP string `json:"clientIP"`
+	// expiresAt 表示模拟登录 token 过期时间
+	ExpiresAt time.Time `json:"expiresAt"`
+	// revokedAt 表示模拟登录被提前结束的时间，未结束时为空
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	// createAt 表示模拟登录开始时间
+	CreateAt time.Time `json:"createAt"`
+}
+
+// CreateImpersonationRequest 表示管理员模拟登录请求
+type CreateImpersonationRequest struct {
+	// userID 表示被模拟的用户 ID
+	UserID string `json:"userID"`
+	// reason 表示模拟登录原因，会记录在审计日志中
+	Reason string `json:"reason"`
+	// expiresIn 表示模拟登录 token 的有效期（秒），为 0 时使用 token 的默认有效期，且不能超过默认有效期
+	ExpiresIn int64 `json:"expiresIn"`
+}
+
+// CreateImpersonationResponse 表示管理员模拟登录响应
+type CreateImpersonationResponse struct {
+	// impersonationID 表示模拟登录 ID
+	ImpersonationID string `json:"impersonationID"`
+	// token 表示模拟登录 token，以被模拟用户的身份访问接口
+	Token string `json:"token"`
+	// expireAt 表示模拟登录 token 过期时间
+	ExpireAt time.Time `json:"expireAt"`
+}
+
+// ListImpersonationRequest 表示获取模拟登录记录列表请求
+type ListImpersonationRequest struct {
+	// offset 表示偏移量
+	Offset int64 `json:"offset" form:"offset"`
+	// limit 表示每页数量
+	Limit int64 `json:"limit" form:"limit"`
+	// actorID 表示按发起模拟登录的管理员过滤，为空时不过滤
+	ActorID string `json:"actorID" form:"actorID"`
+	// userID 表示按被模拟的用户过滤，为空时不过滤
+	UserID string `json:"userID" form:"userID"`
+}
+
+// ListImpersonationResponse 表示获取模拟登录记录列表响应
+type ListImpersonationResponse struct {
+	// totalCount 表示总记录数
+	TotalCount int64 `json:"totalCount"`
+	// impersonations 表示模拟登录记录列表
+	Impersonations []*Impersonation `json:"impersonations"`
+}
+
+// DeleteImpersonationRequest 表示提前结束模拟登录请求
+type DeleteImpersonationRequest struct {
+	// impersonationID 表示要结束的模拟登录 ID，对应 {impersonationID}
+	ImpersonationID string `json:"impersonationID" uri:"impersonationID"`
+}
+
+// DeleteImpersonationResponse 表示提前结束模拟登录响应
+type DeleteImpersonationResponse struct {
+}
@@ -0,0 +1,104 @@
+package v1
+
+import "time"
+
+// OIDCProvider 表示可用于登录的外部 OIDC 提供方
+type OIDCProvider struct {
+	// name 表示提供方名称，用于接口路径
+	Name string `json:"name"`
+	// displayName 表示展示给用户的提供方名称
+	DisplayName string `json:"displayName"`
+}
+
+// ListOIDCProviderRequest 表示获取外部 OIDC 提供方列表请求
+type ListOIDCProviderRequest struct {
+}
+
+// ListOIDCProviderResponse 表示获取外部 OIDC 提供方列表响应
+type ListOIDCProviderResponse struct {
+	// providers 表示提供方列表
+	Providers []*OIDCProvider `json:"providers"`
+}
+
+// AuthorizeOIDCRequest 表示发起外部登录或关联外部账号的请求
+type AuthorizeOIDCRequest struct {
+	// provider 表示提供方名称，对应 {provider}
+	Provider string `json:"provider" uri:"provider"`
+}
+
+// AuthorizeOIDCResponse 表示发起外部登录或关联外部账号的响应.
+// 客户端需要保存 stateToken，然后跳转到 authorizeURL，授权完成后将回调中的 code、state 连同 stateToken 一起提交.
+type AuthorizeOIDCResponse struct {
+	// authorizeURL 表示跳转到提供方的授权地址
+	AuthorizeURL string `json:"authorizeURL"`
+	// stateToken 表示本次流程的状态 token，不能出现在跳转地址中
+	StateToken string `json:"stateToken"`
+	// stateExpireAt 表示 stateToken 的过期时间
+	StateExpireAt time.Time `json:"stateExpireAt"`
+}
+
+// OIDCLoginRequest 表示使用外部账号登录的请求
+type OIDCLoginRequest struct {
+	// provider 表示提供方名称，对应 {provider}
+	Provider string `json:"provider" uri:"provider"`
+	// code 表示提供方回调中的授权码
+	Code string `json:"code"`
+	// state 表示提供方回调中的 state
+	State string `json:"state"`
+	// stateToken 表示发起登录时返回的状态 token
+	StateToken string `json:"stateToken"`
+}
+
+// ExternalIdentity 表示已关联的外部账号信息
+type ExternalIdentity struct {
+	// provider 表示提供方名称
+	Provider string `json:"provider"`
+	// subject 表示外部账号在提供方内的唯一标识
+	Subject string `json:"subject"`
+	// email 表示外部账号的邮箱地址
+	Email string `json:"email"`
+	// createAt 表示关联时间
+	CreateAt time.Time `json:"createAt"`
+	// lastLoginAt 表示最后一次使用该外部账号登录的时间
+	LastLoginAt time.Time `json:"lastLoginAt"`
+}
+
+// ListExternalIdentityRequest 表示获取已关联外部账号列表请求
+type ListExternalIdentityRequest struct {
+}
+
+// ListExternalIdentityResponse 表示获取已关联外部账号列表响应
+type ListExternalIdentityResponse struct {
+	// totalCount 表示已关联的外部账号数量
+	TotalCount int64 `json:"totalCount"`
+	// identities 表示已关联的外部账号列表
+	Identities []*ExternalIdentity `json:"identities"`
+}
+
+// LinkExternalIdentityRequest 表示为当前用户关联外部账号的请求
+type LinkExternalIdentityRequest struct {
+	// provider 表示提供方名称，对应 {provider}
+	Provider string `json:"provider" uri:"provider"`
+	// code 表示提供方回调中的授权码
+	Code string `json:"code"`
+	// state 表示提供方回调中的 state
+	State string `json:"state"`
+	// stateToken 表示发起关联时返回的状态 token
+	StateToken string `json:"stateToken"`
+}
+
+// LinkExternalIdentityResponse 表示为当前用户关联外部账号的响应
+type LinkExternalIdentityResponse struct {
+	// identity 表示新关联的外部账号
+	Identity *ExternalIdentity `json:"identity"`
+}
+
+// UnlinkExternalIdentityRequest 表示解除关联外部账号的请求
+type UnlinkExternalIdentityRequest struct {
+	// provider 表示提供方名称，对应 {provider}
+	Provider string `json:"provider" uri:"provider"`
+}
+
+// UnlinkExternalIdentityResponse 表示解除关联外部账号的响应
+type UnlinkExternalIdentityResponse struct {
+}
@@ -12,6 +12,10 @@ type Post struct {
 	Title string `json:"title"`
 	// content 表示博客内容
 	Content string `json:"content"`
+	// viewCount 表示博客浏览次数
+	ViewCount int64 `json:"viewCount"`
+	// hidden 表示博客是否被管理员隐藏
+	Hidden bool `json:"hidden"`
 	// createAt 表示博客创建时间
 	CreateAt time.Time `json:"createAt"`
 	// updateAt 表示博客最后更新时间
@@ -0,0 +1,113 @@
+package v1
+
+import "time"
+
+// Report 表示举报信息
+type Report struct {
+	// reportID 表示举报 ID
+	ReportID string `json:"reportID"`
+	// reporterID 表示举报人用户 ID，系统自动举报时为空
+	ReporterID string `json:"reporterID"`
+	// targetType 表示被举报对象类型，可选值：post、user
+	TargetType string `json:"targetType"`
+	// targetID 表示被举报对象 ID
+	TargetID string `json:"targetID"`
+	// reason 表示举报原因
+	Reason string `json:"reason"`
+	// source 表示举报来源，可选值：user、filter
+	Source string `json:"source"`
+	// status 表示处理状态，可选值：pending、resolved、dismissed
+	Status string `json:"status"`
+	// createAt 表示举报创建时间
+	CreateAt time.Time `json:"createAt"`
+	// updateAt 表示举报最后更新时间
+	UpdateAt time.Time `json:"updateAt"`
+}
+
+// ModerationAction 表示管理员执行的审核操作记录
+type ModerationAction struct {
+	// reportID 表示关联的举报 ID
+	ReportID string `json:"reportID"`
+	// actorID 表示执行操作的管理员用户 ID
+	ActorID string `json:"actorID"`
+	// action 表示操作类型，可选值：hide_post、suspend_user、dismiss
+	Action string `json:"action"`
+	// targetType 表示操作对象类型，可选值：post、user
+	TargetType string `json:"targetType"`
+	// targetID 表示操作对象 ID
+	TargetID string `json:"targetID"`
+	// note 表示操作备注
+	Note string `json:"note"`
+	// createAt 表示操作时间
+	CreateAt time.Time `json:"createAt"`
+}
+
+// CreateReportRequest 表示提交举报请求
+type CreateReportRequest struct {
+	// targetType 表示被举报对象类型，可选值：post、user
+	TargetType string `json:"targetType"`
+	// targetID 表示被举报对象 ID
+	TargetID string `json:"targetID"`
+	// reason 表示举报原因
+	Reason string `json:"reason"`
+}
+
+// CreateReportResponse 表示提交举报响应
+type CreateReportResponse struct {
+	// reportID 表示新创建的举报 ID
+	ReportID string `json:"reportID"`
+}
+
+// ListReportRequest 表示获取审核队列请求
+type ListReportRequest struct {
+	// offset 表示偏移量
+	Offset int64 `json:"offset" form:"offset"`
+	// limit 表示每页数量
+	Limit int64 `json:"limit" form:"limit"`
+	// status 表示可选的处理状态过滤
+	Status *string `json:"status" form:"status"`
+	// targetType 表示可选的被举报对象类型过滤
+	TargetType *string `json:"targetType" form:"targetType"`
+	// source 表示可选的举报来源过滤
+	Source *string `json:"source" form:"source"`
+}
+
+// ListReportResponse 表示获取审核队列响应
+type ListReportResponse struct {
+	// totalCount 表示举报总数
+	TotalCount int64 `json:"totalCount"`
+	// reports 表示举报列表
+	Reports []*Report `json:"reports"`
+}
+
+// ModerateReportRequest 表示处理举报请求
+type ModerateReportRequest struct {
+	// reportID 表示要处理的举报 ID，对应 {reportID}
+	ReportID string `json:"reportID" uri:"reportID"`
+	// action 表示操作类型，可选值：hide_post、suspend_user、dismiss
+	Action string `json:"action"`
+	// note 表示可选的操作备注
+	Note string `json:"note"`
+}
+
+// ModerateReportResponse 表示处理举报响应
+type ModerateReportResponse struct {
+}
+
+// ListModerationActionRequest 表示获取审核操作记录请求
+type ListModerationActionRequest struct {
+	// offset 表示偏移量
+	Offset int64 `json:"offset" form:"offset"`
+	// limit 表示每页数量
+	Limit int64 `json:"limit" form:"limit"`
+	// reportID 表示可选的举报 ID 过滤
+	ReportID *string `json:"reportID" form:"reportID"`
+}
+
+// ListModerationActionResponse 表示获取审核操作记录响应
+type ListModerationActionResponse struct {
+	// totalCount 表示操作记录总数
+	TotalCount int64 `json:"totalCount"`
+	// actions 表示操作记录列表
+	Actions []*ModerationAction `json:"actions"`
+}
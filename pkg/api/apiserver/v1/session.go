@@ -0,0 +1,47 @@
+package v1
+
+import "time"
+
+// Session 表示登录会话（设备）信息
+type Session struct {
+	// sessionID 表示会话 ID
+	SessionID string `json:"sessionID"`
+	// userAgent 表示登录时的客户端 User-Agent
+	UserAgent string `json:"userAgent"`
+	// clientIP 表示最近一次活动的客户端 IP
+	ClientIP string `json:"clientIP"`
+	// current 表示是否是当前请求所使用的会话
+	Current bool `json:"current"`
+	// lastActiveAt 表示最近一次活动（登录、刷新令牌或访问接口）的时间
+	LastActiveAt time.Time `json:"lastActiveAt"`
+	// expiresAt 表示会话过期时间
+	ExpiresAt time.Time `json:"expiresAt"`
+	// createAt 表示会话创建（登录）时间
+	CreateAt time.Time `json:"createAt"`
+}
+
+// ListSessionRequest 表示获取登录会话列表请求
+type ListSessionRequest struct {
+	// offset 表示偏移量
+	Offset int64 `json:"offset" form:"offset"`
+	// limit 表示每页数量
+	Limit int64 `json:"limit" form:"limit"`
+}
+
+// ListSessionResponse 表示获取登录会话列表响应
+type ListSessionResponse struct {
+	// totalCount 表示总会话数
+	TotalCount int64 `json:"totalCount"`
+	// sessions 表示会话列表
+	Sessions []*Session `json:"sessions"`
+}
+
+// DeleteSessionRequest 表示吊销登录会话请求
+type DeleteSessionRequest struct {
+	// sessionID 表示要吊销的会话 ID，对应 {sessionID}
+	SessionID string `json:"sessionID" uri:"sessionID"`
+}
+
+// DeleteSessionResponse 表示吊销登录会话响应
+type DeleteSessionResponse struct {
+}
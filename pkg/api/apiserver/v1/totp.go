@@ -0,0 +1,61 @@
+package v1
+
+// LoginTOTPRequest 表示两步验证登录请求
+type LoginTOTPRequest struct {
+	// challengeToken 表示 /login 返回的挑战 token
+	ChallengeToken string `json:"challengeToken"`
+	// code 表示 TOTP 验证码或恢复码
+	Code string `json:"code"`
+}
+
+// EnrollTOTPRequest 表示开始设置 TOTP 两步验证请求
+type EnrollTOTPRequest struct {
+}
+
+// EnrollTOTPResponse 表示开始设置 TOTP 两步验证响应
+type EnrollTOTPResponse struct {
+	// secret 表示 TOTP 密钥（Base32 编码），用于手动添加到认证器应用
+	Secret string `json:"secret"`
+	// uri 表示 otpauth:// 格式的密钥 URI
+	URI string `json:"uri"`
+	// qrCode 表示密钥 URI 的二维码，格式为 data:image/png;base64 的 Data URL
+	QRCode string `json:"qrCode"`
+}
+
+// ConfirmTOTPRequest 表示确认开启 TOTP 两步验证请求
+type ConfirmTOTPRequest struct {
+	// code 表示认证器应用生成的 TOTP 验证码
+	Code string `json:"code"`
+}
+
+// ConfirmTOTPResponse 表示确认开启 TOTP 两步验证响应
+type ConfirmTOTPResponse struct {
+	// recoveryCodes 表示恢复码列表，每个恢复码只能使用一次，只在响应中返回一次
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// DisableTOTPRequest 表示关闭 TOTP 两步验证请求
+type DisableTOTPRequest struct {
+	// password 表示当前密码，没有设置过密码的用户（例如通过外部账号自动创建的用户）可以为空
+	Password string `json:"password"`
+	// code 表示 TOTP 验证码或恢复码
+	Code string `json:"code"`
+}
+
+// DisableTOTPResponse 表示关闭 TOTP 两步验证响应
+type DisableTOTPResponse struct {
+}
+
+// RegenerateRecoveryCodesRequest 表示重新生成恢复码请求
+type RegenerateRecoveryCodesRequest struct {
+	// password 表示当前密码，没有设置过密码的用户（例如通过外部账号自动创建的用户）可以为空
+	Password string `json:"password"`
+	// code 表示 TOTP 验证码或恢复码
+	Code string `json:"code"`
+}
+
+// RegenerateRecoveryCodesResponse 表示重新生成恢复码响应
+type RegenerateRecoveryCodesResponse struct {
+	// recoveryCodes 表示新的恢复码列表，旧的恢复码全部失效
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -14,6 +14,10 @@ type User struct {
 	Email string `json:"email"`
 	// phone 表示用户手机号
 	Phone string `json:"phone"`
+	// emailVerified 表示用户的电子邮箱是否已验证
+	EmailVerified bool `json:"emailVerified"`
+	// role 表示用户角色，可选值：user、admin
+	Role string `json:"role"`
 	// postCount 表示用户拥有的博客数量
 	PostCount int64 `json:"postCount"`
 	// createAt 表示用户注册时间
@@ -36,10 +40,22 @@ type LoginResponse struct {
 	Token string `json:"token"`
 	// expireAt 表示该 token 的过期时间
 	ExpireAt time.Time `json:"expireAt"`
+	// refreshToken 表示用于换取新 token 的刷新令牌，只在响应中返回一次
+	RefreshToken string `json:"refreshToken"`
+	// refreshExpireAt 表示该 refreshToken 的过期时间
+	RefreshExpireAt time.Time `json:"refreshExpireAt"`
+	// twoFactorRequired 表示用户开启了两步验证，需要使用 challengeToken 和验证码完成登录，此时不返回 token
+	TwoFactorRequired bool `json:"twoFactorRequired,omitempty"`
+	// challengeToken 表示两步验证登录的挑战 token
+	ChallengeToken string `json:"challengeToken,omitempty"`
+	// challengeExpireAt 表示 challengeToken 的过期时间
+	ChallengeExpireAt *time.Time `json:"challengeExpireAt,omitempty"`
 }
 
 // RefreshTokenRequest 表示刷新令牌的请求
 type RefreshTokenRequest struct {
+	// refreshToken 表示登录或上一次刷新时返回的刷新令牌
+	RefreshToken string `json:"refreshToken"`
 }
 
 // RefreshTokenResponse 表示刷新令牌的响应
@@ -48,11 +64,17 @@ type RefreshTokenResponse struct {
 	Token string `json:"token"`
 	// expireAt 表示该 token 的过期时间
 	ExpireAt time.Time `json:"expireAt"`
+	// refreshToken 表示轮换后的新刷新令牌，旧的刷新令牌随即失效
+	RefreshToken string `json:"refreshToken"`
+	// refreshExpireAt 表示该 refreshToken 的过期时间
+	RefreshExpireAt time.Time `json:"refreshExpireAt"`
 }
 
 // ChangePasswordRequest 表示修改密码请求
 type ChangePasswordRequest struct {
-	// oldPassword 表示当前密码
+	// userID 表示要修改密码的用户 ID，对应 {userID}
+	UserID string `json:"userID" uri:"userID"`
+	// oldPassword 表示当前密码. 管理员重置其他用户的密码时不需要传入
 	OldPassword string `json:"oldPassword"`
 	// newPassword 表示准备修改的新密码
 	NewPassword string `json:"newPassword"`
@@ -84,6 +106,8 @@ type CreateUserResponse struct {
 
 // UpdateUserRequest 表示更新用户请求
 type UpdateUserRequest struct {
+	// userID 表示要更新的用户 ID，对应 {userID}
+	UserID string `json:"userID" uri:"userID"`
 	// username 表示可选的用户名称
 	Username *string `json:"username"`
 	// nickname 表示可选的用户昵称
@@ -100,6 +124,8 @@ type UpdateUserResponse struct {
 
 // DeleteUserRequest 表示删除用户请求
 type DeleteUserRequest struct {
+	// userID 表示要删除的用户 ID，对应 {userID}
+	UserID string `json:"userID" uri:"userID"`
 }
 
 // DeleteUserResponse 表示删除用户响应
@@ -108,6 +134,8 @@ type DeleteUserResponse struct {
 
 // GetUserRequest 表示获取用户请求
 type GetUserRequest struct {
+	// userID 表示要查询的用户 ID，对应 {userID}
+	UserID string `json:"userID" uri:"userID"`
 }
 
 // GetUserResponse 表示获取用户响应
@@ -119,9 +147,9 @@ type GetUserResponse struct {
 // ListUserRequest 表示用户列表请求
 type ListUserRequest struct {
 	// offset 表示偏移量
-	Offset int64 `json:"offset"`
+	Offset int64 `json:"offset" form:"offset"`
 	// limit 表示每页数量
-	Limit int64 `json:"limit"`
+	Limit int64 `json:"limit" form:"limit"`
 }
 
 // ListUserResponse 表示用户列表响应
@@ -131,3 +159,43 @@ type ListUserResponse struct {
 	// users 表示用户列表
 	Users []*User `json:"users"`
 }
+
+// LogoutRequest 表示注销当前登录的请求
+type LogoutRequest struct {
+	// refreshToken 表示可选的刷新令牌，传入时会一并吊销该刷新令牌所在的令牌族
+	RefreshToken string `json:"refreshToken"`
+}
+
+// LogoutResponse 表示注销当前登录的响应
+type LogoutResponse struct {
+}
+
+// LogoutAllRequest 表示注销所有登录（所有设备）的请求
+type LogoutAllRequest struct {
+}
+
+// LogoutAllResponse 表示注销所有登录（所有设备）的响应
+type LogoutAllResponse struct {
+}
+
+// UpdateUserRoleRequest 表示修改用户角色请求
+type UpdateUserRoleRequest struct {
+	// userID 表示要修改角色的用户 ID，对应 {userID}
+	UserID string `json:"userID" uri:"userID"`
+	// role 表示新的用户角色，可选值：user、admin
+	Role string `json:"role"`
+}
+
+// UpdateUserRoleResponse 表示修改用户角色响应
+type UpdateUserRoleResponse struct {
+}
+
+// UnlockUserRequest 表示解除账号锁定请求
+type UnlockUserRequest struct {
+	// userID 表示要解除锁定的用户 ID，对应 {userID}
+	UserID string `json:"userID" uri:"userID"`
+}
+
+// UnlockUserResponse 表示解除账号锁定响应
+type UnlockUserResponse struct {
+}
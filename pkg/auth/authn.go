@@ -1,14 +1,157 @@
 package auth
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 
-// Encrypt 使用 bcrypt 加密纯文本
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 支持的密码哈希算法
+const (
+	// HashBcrypt 使用 bcrypt 哈希密码
+	HashBcrypt = "bcrypt"
+	// HashArgon2id 使用 argon2id 哈希密码
+	HashArgon2id = "argon2id"
+)
+
+const (
+	// argon2SaltLength 是 argon2id 盐值的长度
+	argon2SaltLength = 16
+	// argon2KeyLength 是 argon2id 哈希值的长度
+	argon2KeyLength = 32
+)
+
+// ErrMismatchedPassword 表示密码与哈希值不匹配
+var ErrMismatchedPassword = errors.New("auth: password does not match")
+
+// HashConfig 定义新密码使用的哈希算法及参数
+type HashConfig struct {
+	// Algorithm 是哈希算法，可选值：bcrypt、argon2id
+	Algorithm string
+	// BcryptCost 是 bcrypt 的计算成本
+	BcryptCost int
+	// Argon2Memory 是 argon2id 使用的内存大小（KiB）
+	Argon2Memory uint32
+	// Argon2Time 是 argon2id 的迭代次数
+	Argon2Time uint32
+	// Argon2Threads 是 argon2id 的并行度
+	Argon2Threads uint8
+}
+
+// hashConfig 是包级别的哈希配置，默认使用 OWASP 推荐的 argon2id 参数
+var hashConfig = HashConfig{
+	Algorithm:     HashArgon2id,
+	BcryptCost:    bcrypt.DefaultCost,
+	Argon2Memory:  19 * 1024,
+	Argon2Time:    2,
+	Argon2Threads: 1,
+}
+
+// SetHashConfig 设置新密码使用的哈希算法及参数，应在服务启动时调用.
+// 已有的哈希值仍然可以校验，可以使用 NeedsRehash 判断是否需要按新配置重新哈希.
+func SetHashConfig(cfg HashConfig) {
+	hashConfig = cfg
+}
+
+// Encrypt 使用配置的哈希算法加密纯文本
 func Encrypt(source string) (string, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(source), bcrypt.DefaultCost)
-	return string(hashedBytes), err
+	if hashConfig.Algorithm == HashBcrypt {
+		hashedBytes, err := bcrypt.GenerateFromPassword([]byte(source), hashConfig.BcryptCost)
+		return string(hashedBytes), err
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	p := argon2Params{memory: hashConfig.Argon2Memory, time: hashConfig.Argon2Time, threads: hashConfig.Argon2Threads}
+	key := argon2.IDKey([]byte(source), salt, p.time, p.memory, p.threads, argon2KeyLength)
+	return p.encode(salt, key), nil
 }
 
-// Compare 比较密文和明文是否相同
+// Compare 比较密文和明文是否相同. 根据密文的格式自动识别哈希算法
 func Compare(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	if !strings.HasPrefix(hashedPassword, "$argon2id$") {
+		return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	}
+
+	p, salt, key, err := decodeArgon2(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedPassword
+	}
+
+	return nil
+}
+
+// NeedsRehash 判断密文是否使用了与当前配置不同的算法或参数. 返回 true 时，
+// 调用方应在用户下一次成功登录时使用明文密码重新哈希.
+func NeedsRehash(hashedPassword string) bool {
+	if hashConfig.Algorithm == HashBcrypt {
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost != hashConfig.BcryptCost
+	}
+
+	p, _, key, err := decodeArgon2(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	return p.memory != hashConfig.Argon2Memory || p.time != hashConfig.Argon2Time ||
+		p.threads != hashConfig.Argon2Threads || len(key) != argon2KeyLength
+}
+
+// argon2Params 是 argon2id 的参数
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+// encode 使用 PHC 字符串格式编码 argon2id 哈希值：$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+func (p argon2Params) encode(salt []byte, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.memory, p.time, p.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+// decodeArgon2 解析 PHC 字符串格式的 argon2id 哈希值
+func decodeArgon2(encoded string) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, errors.New("auth: invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errors.New("auth: unsupported argon2id version")
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil || p.time == 0 || p.threads == 0 {
+		return p, nil, nil, errors.New("auth: invalid argon2id parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, errors.New("auth: invalid argon2id salt")
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errors.New("auth: invalid argon2id key")
+	}
+
+	return p, salt, key, nil
 }
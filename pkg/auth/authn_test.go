@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestEncryptArgon2id(t *testing.T) {
+	hash, err := Encrypt("fastgo1234")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"))
+
+	assert.NoError(t, Compare(hash, "fastgo1234"))
+	assert.ErrorIs(t, Compare(hash, "fastgo12345"), ErrMismatchedPassword)
+	assert.False(t, NeedsRehash(hash))
+}
+
+func TestNeedsRehash(t *testing.T) {
+	// 升级前使用 bcrypt 生成的哈希仍然可以校验，但需要重新哈希
+	legacy, err := bcrypt.GenerateFromPassword([]byte("fastgo1234"), bcrypt.MinCost)
+	require.NoError(t, err)
+	assert.NoError(t, Compare(string(legacy), "fastgo1234"))
+	assert.True(t, NeedsRehash(string(legacy)))
+
+	// argon2id 参数变化后也需要重新哈希
+	hash, err := Encrypt("fastgo1234")
+	require.NoError(t, err)
+
+	defer SetHashConfig(hashConfig)
+	cfg := hashConfig
+	cfg.Argon2Time = 3
+	SetHashConfig(cfg)
+	assert.True(t, NeedsRehash(hash))
+	assert.NoError(t, Compare(hash, "fastgo1234"))
+
+	cfg.Algorithm, cfg.BcryptCost = HashBcrypt, bcrypt.MinCost
+	SetHashConfig(cfg)
+	assert.True(t, NeedsRehash(hash))
+	assert.False(t, NeedsRehash(string(legacy)))
+}
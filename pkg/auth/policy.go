@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy 定义新密码需要满足的规则，在注册、修改密码和重置密码时校验
+type PasswordPolicy struct {
+	// MinLength 是密码的最小长度（字符数）
+	MinLength int
+	// MaxLength 是密码的最大长度（字符数）
+	MaxLength int
+	// MinCharClasses 是密码至少需要包含的字符类别数，类别包括小写字母、大写字母、数字和其他符号
+	MinCharClasses int
+	// DisallowUsername 表示密码中不能包含用户名（不区分大小写）
+	DisallowUsername bool
+
+	// breached 保存已泄露密码的 SHA-1 摘要
+	breached map[[sha1.Size]byte]struct{}
+}
+
+// policy 是包级别的密码规则，默认只校验长度
+var policy = &PasswordPolicy{MinLength: 8, MaxLength: 64, MinCharClasses: 1}
+
+// SetPasswordPolicy 设置包级别的密码规则，应在服务启动时调用
+func SetPasswordPolicy(p *PasswordPolicy) {
+	policy = p
+}
+
+// ValidatePassword 使用包级别的密码规则校验密码. username 为空时不校验密码中是否包含用户名
+func ValidatePassword(password string, username string) error {
+	return policy.Validate(password, username)
+}
+
+// LoadBreachedList 从文件中加载已泄露密码列表. 文件每行一个条目，可以是明文密码，
+// 也可以是 40 位十六进制的 SHA-1 摘要（兼容 Have I Been Pwned 导出的 <SHA-1>:<次数> 格式）.
+// 空行和以 # 开头的行会被忽略.
+func (p *PasswordPolicy) LoadBreachedList(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer f.Close()
+
+	breached := make(map[[sha1.Size]byte]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		breached[breachedKey(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	p.breached = breached
+	return nil
+}
+
+// Validate 校验密码是否满足规则，不满足时返回可以直接展示给用户的错误
+func (p *PasswordPolicy) Validate(password string, username string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength || length > p.MaxLength {
+		return fmt.Errorf("password must be between %d and %d characters", p.MinLength, p.MaxLength)
+	}
+
+	if classes := charClasses(password); classes < p.MinCharClasses {
+		return fmt.Errorf("password must contain at least %d of the following: lowercase letters, uppercase letters, digits and symbols", p.MinCharClasses)
+	}
+
+	if p.DisallowUsername && username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return fmt.Errorf("password cannot contain the username")
+	}
+
+	if _, ok := p.breached[sha1.Sum([]byte(password))]; ok {
+		return fmt.Errorf("password has appeared in a data breach, please choose a different password")
+	}
+
+	return nil
+}
+
+// charClasses 返回密码包含的字符类别数
+func charClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}
+
+// breachedKey 返回已泄露密码列表中一个条目对应的 SHA-1 摘要
+func breachedKey(entry string) [sha1.Size]byte {
+	digest, _, _ := strings.Cut(entry, ":")
+	if len(digest) == 2*sha1.Size {
+		var key [sha1.Size]byte
+		if _, err := hex.Decode(key[:], []byte(digest)); err == nil {
+			return key
+		}
+	}
+
+	return sha1.Sum([]byte(entry))
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	content := "# 明文密码和 SHA-1 摘要可以混用\nPassword123\nFF17AD1D19A2AD8EE8DCE21B48254B9FFD4ADEB2:42\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	p := &PasswordPolicy{MinLength: 8, MaxLength: 64, MinCharClasses: 3, DisallowUsername: true}
+	require.NoError(t, p.LoadBreachedList(path))
+
+	assert.NoError(t, p.Validate("Fast-go-2024", "colin"))
+	assert.ErrorContains(t, p.Validate("Fa1!", "colin"), "between 8 and 64")
+	assert.ErrorContains(t, p.Validate("fastgo1234", "colin"), "at least 3")
+	assert.ErrorContains(t, p.Validate("Colin-2024!", "colin"), "username")
+	assert.ErrorContains(t, p.Validate("Password123", "colin"), "breach")
+	assert.ErrorContains(t, p.Validate("Summer-2024", "colin"), "breach")
+
+	// 用户名未知时不校验是否包含用户名
+	assert.NoError(t, p.Validate("Colin-2024!", ""))
+}
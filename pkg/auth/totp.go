@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"image/png"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// totpPeriod 是 TOTP 验证码的有效周期（秒）
+	totpPeriod = 30
+	// totpSkew 是验证时允许的前后时间步偏差，用于容忍客户端与服务端的时钟误差
+	totpSkew = 1
+)
+
+// TOTPKey 是新生成的 TOTP 密钥
+type TOTPKey struct {
+	// Secret 是 Base32 编码的密钥
+	Secret string
+	// URI 是 otpauth:// 格式的密钥 URI，可以直接导入认证器应用
+	URI string
+	// QRCode 是 URI 的二维码（PNG 格式）
+	QRCode []byte
+}
+
+// GenerateTOTP 为账号 account 生成一个新的 TOTP 密钥
+func GenerateTOTP(issuer string, account string) (*TOTPKey, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: account,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := key.Image(256, 256)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return &TOTPKey{Secret: key.Secret(), URI: key.URL(), QRCode: buf.Bytes()}, nil
+}
+
+// ValidateTOTP 校验 TOTP 验证码. 校验通过时返回验证码对应的时间步，
+// 调用方应记录该时间步，并拒绝不大于已记录时间步的验证码，防止验证码被重放.
+func ValidateTOTP(secret string, code string, t time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+	step := t.Unix() / totpPeriod
+	for i := int64(-totpSkew); i <= totpSkew; i++ {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix((step+i)*totpPeriod, 0), opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step + i, true
+		}
+	}
+
+	return 0, false
+}
+
+// GenerateRecoveryCodes 生成 n 个一次性恢复码，格式为 xxxxx-xxxxx
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	codes := make([]string, 0, n)
+	buf := make([]byte, 6)
+	for range n {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(buf))[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode 规范化用户输入的恢复码，忽略大小写、空白和分隔符 "-"，
+// 并还原为 GenerateRecoveryCodes 生成的 xxxxx-xxxxx 格式
+func NormalizeRecoveryCode(code string) string {
+	code = strings.Map(func(r rune) rune {
+		if r == '-' || unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, code)
+	if len(code) != 10 {
+		return code
+	}
+
+	return code[:5] + "-" + code[5:]
+}
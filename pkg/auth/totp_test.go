@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	key, err := GenerateTOTP("fastgo", "colin")
+	require.NoError(t, err)
+	assert.Contains(t, key.URI, "otpauth://totp/fastgo:colin")
+	assert.NotEmpty(t, key.QRCode)
+
+	now := time.Unix(1_700_000_000, 0)
+	code, err := totp.GenerateCode(key.Secret, now)
+	require.NoError(t, err)
+
+	step, ok := ValidateTOTP(key.Secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/30, step)
+
+	// 允许前后一个时间步的时钟误差
+	step, ok = ValidateTOTP(key.Secret, code, now.Add(30*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/30, step)
+
+	_, ok = ValidateTOTP(key.Secret, code, now.Add(2*time.Minute))
+	assert.False(t, ok)
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	assert.Len(t, codes, 10)
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{code: "abcde-fghij", want: "abcde-fghij"},
+		{code: " ABCDE-FGHIJ\n", want: "abcde-fghij"},
+		{code: "abcdefghij", want: "abcde-fghij"},
+		{code: "abcde fghij", want: "abcde-fghij"},
+		{code: "ab cde-fg hij", want: "abcde-fghij"},
+		{code: "abc-de", want: "abcde"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NormalizeRecoveryCode(tt.code), tt.code)
+	}
+}
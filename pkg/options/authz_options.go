@@ -0,0 +1,28 @@
+package options
+
+import "fmt"
+
+// AuthzOptions defines options for role-based authorization.
+type AuthzOptions struct {
+	// Admins 定义服务启动时会被授予管理员角色的用户 ID 列表，用于初始化第一批管理员.
+	// 之后可以由管理员通过接口修改其他用户的角色
+	Admins []string `json:"admins" mapstructure:"admins"`
+}
+
+// NewAuthzOptions 创建带有默认值的 AuthzOptions 实例
+func NewAuthzOptions() *AuthzOptions {
+	return &AuthzOptions{
+		Admins: []string{},
+	}
+}
+
+// Validate verifies flags passed to AuthzOptions.
+func (o *AuthzOptions) Validate() error {
+	for _, userID := range o.Admins {
+		if userID == "" {
+			return fmt.Errorf("authz admin user id cannot be empty")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package options
+
+import (
+	"fmt"
+)
+
+// 支持的压缩算法，取值与 Content-Encoding 相同
+const (
+	// CompressionGzip 表示 gzip 压缩
+	CompressionGzip = "gzip"
+	// CompressionBrotli 表示 brotli 压缩
+	CompressionBrotli = "br"
+	// CompressionZstd 表示 zstd 压缩
+	CompressionZstd = "zstd"
+)
+
+// CompressionOptions defines options for HTTP response compression.
+type CompressionOptions struct {
+	// Encodings 定义启用的压缩算法，可选值：br、zstd、gzip. 客户端同等接受多种算法时，按配置的顺序优先选择. 为空时不压缩响应
+	Encodings []string `json:"encodings" mapstructure:"encodings"`
+	// MinLength 定义需要压缩的最小响应体字节数，较小的响应压缩后收益不大
+	MinLength int `json:"min-length" mapstructure:"min-length"`
+	// ContentTypes 定义需要压缩的响应类型，图片、压缩包等已经压缩过的类型不应配置
+	ContentTypes []string `json:"content-types" mapstructure:"content-types"`
+}
+
+// NewCompressionOptions 创建带有默认值的 CompressionOptions 实例
+func NewCompressionOptions() *CompressionOptions {
+	return &CompressionOptions{
+		Encodings:    []string{CompressionZstd, CompressionBrotli, CompressionGzip},
+		MinLength:    1024,
+		ContentTypes: []string{"application/json", "text/plain", "text/html"},
+	}
+}
+
+// Validate verifies flags passed to CompressionOptions.
+func (o *CompressionOptions) Validate() error {
+	for _, encoding := range o.Encodings {
+		switch encoding {
+		case CompressionGzip, CompressionBrotli, CompressionZstd:
+		default:
+			return fmt.Errorf("unsupported compression encoding %q, must be one of: br, zstd, gzip", encoding)
+		}
+	}
+
+	if o.MinLength < 0 {
+		return fmt.Errorf("compression min length cannot be negative")
+	}
+
+	return nil
+}
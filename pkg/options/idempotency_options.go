@@ -0,0 +1,59 @@
+package options
+
+import (
+	"fmt"
+	"time"
+)
+
+// 支持的幂等记录存储
+const (
+	// IdempotencyBackendMemory 表示幂等记录保存在内存中，只在当前实例内生效
+	IdempotencyBackendMemory = "memory"
+	// IdempotencyBackendRedis 表示幂等记录保存在 Redis 中，多个实例共享
+	IdempotencyBackendRedis = "redis"
+)
+
+// IdempotencyOptions defines options for Idempotency-Key handling.
+type IdempotencyOptions struct {
+	// Backend 定义幂等记录的存储，可选值：memory、redis
+	Backend string `json:"backend" mapstructure:"backend"`
+	// Redis 定义 backend 为 redis 时使用的 Redis 配置
+	Redis *RedisOptions `json:"redis" mapstructure:"redis"`
+	// TTL 定义保存响应的时长，超过后同一个 key 视为新的请求
+	TTL time.Duration `json:"ttl" mapstructure:"ttl"`
+	// LockTimeout 定义第一次请求处理期间锁定 key 的最长时间，避免实例异常退出后 key 一直处于处理中
+	LockTimeout time.Duration `json:"lock-timeout" mapstructure:"lock-timeout"`
+}
+
+// NewIdempotencyOptions 创建带有默认值的 IdempotencyOptions 实例
+func NewIdempotencyOptions() *IdempotencyOptions {
+	return &IdempotencyOptions{
+		Backend:     IdempotencyBackendMemory,
+		Redis:       NewRedisOptions(),
+		TTL:         24 * time.Hour,
+		LockTimeout: time.Minute,
+	}
+}
+
+// Validate verifies flags passed to IdempotencyOptions.
+func (o *IdempotencyOptions) Validate() error {
+	switch o.Backend {
+	case IdempotencyBackendMemory:
+	case IdempotencyBackendRedis:
+		if err := o.Redis.Validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported idempotency backend %q, must be one of: memory, redis", o.Backend)
+	}
+
+	if o.TTL <= 0 {
+		return fmt.Errorf("idempotency ttl must be greater than 0")
+	}
+
+	if o.LockTimeout <= 0 {
+		return fmt.Errorf("idempotency lock timeout must be greater than 0")
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package options
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/TobyIcetea/fastgo/pkg/token"
+)
+
+// JWTKeyOptions defines options for asymmetric JWT signing keys.
+type JWTKeyOptions struct {
+	// Dir 定义存放 JWT 签名密钥的目录，目录中每个 .pem 文件是一个 RSA 或 Ed25519 密钥，文件名即为 kid.
+	// 为空时使用 jwt-key 以 HS256 签发 token
+	Dir string `json:"dir" mapstructure:"dir"`
+	// SigningKeyID 定义用于签发 token 的密钥 ID. 为空时使用 ID 按字典序最大的私钥
+	SigningKeyID string `json:"signing-key-id" mapstructure:"signing-key-id"`
+}
+
+// NewJWTKeyOptions 创建带有默认值的 JWTKeyOptions 实例
+func NewJWTKeyOptions() *JWTKeyOptions {
+	return &JWTKeyOptions{}
+}
+
+// Validate verifies flags passed to JWTKeyOptions.
+func (o *JWTKeyOptions) Validate() error {
+	if o.Dir == "" {
+		if o.SigningKeyID != "" {
+			return fmt.Errorf("jwt signing key id requires jwt key dir to be set")
+		}
+		return nil
+	}
+
+	info, err := os.Stat(o.Dir)
+	if err != nil {
+		return fmt.Errorf("invalid jwt key dir '%s': %w", o.Dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("jwt key dir '%s' is not a directory", o.Dir)
+	}
+
+	return nil
+}
+
+// Enabled 返回是否配置了非对称签名密钥
+func (o *JWTKeyOptions) Enabled() bool {
+	return o.Dir != ""
+}
+
+// NewKeySet 从密钥目录中加载 KeySet
+func (o *JWTKeyOptions) NewKeySet() (*token.KeySet, error) {
+	return token.LoadKeySet(o.Dir, o.SigningKeyID)
+}
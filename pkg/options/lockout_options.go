@@ -0,0 +1,52 @@
+package options
+
+import (
+	"fmt"
+	"time"
+)
+
+// LockoutOptions defines options for login brute-force protection.
+type LockoutOptions struct {
+	// MaxAttempts 定义同一账号连续登录失败多少次后被临时锁定
+	MaxAttempts int `json:"max-attempts" mapstructure:"max-attempts"`
+	// IPMaxAttempts 定义同一客户端 IP 连续登录失败多少次后被临时锁定，用于防止针对多个账号的撞库
+	IPMaxAttempts int `json:"ip-max-attempts" mapstructure:"ip-max-attempts"`
+	// Duration 定义首次锁定的时长，之后每多失败一次，锁定时长翻倍
+	Duration time.Duration `json:"duration" mapstructure:"duration"`
+	// MaxDuration 定义锁定时长的上限
+	MaxDuration time.Duration `json:"max-duration" mapstructure:"max-duration"`
+	// ResetAfter 定义距离最近一次失败多久之后清零失败次数
+	ResetAfter time.Duration `json:"reset-after" mapstructure:"reset-after"`
+}
+
+// NewLockoutOptions 创建带有默认值的 LockoutOptions 实例
+func NewLockoutOptions() *LockoutOptions {
+	return &LockoutOptions{
+		MaxAttempts:   5,
+		IPMaxAttempts: 20,
+		Duration:      time.Minute,
+		MaxDuration:   time.Hour,
+		ResetAfter:    time.Hour,
+	}
+}
+
+// Validate verifies flags passed to LockoutOptions.
+func (o *LockoutOptions) Validate() error {
+	if o.MaxAttempts <= 0 || o.IPMaxAttempts <= 0 {
+		return fmt.Errorf("lockout max attempts must be greater than 0")
+	}
+
+	if o.Duration <= 0 {
+		return fmt.Errorf("lockout duration must be greater than 0")
+	}
+
+	if o.MaxDuration < o.Duration {
+		return fmt.Errorf("lockout max duration cannot be less than lockout duration")
+	}
+
+	if o.ResetAfter <= 0 {
+		return fmt.Errorf("lockout reset after must be greater than 0")
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// 支持的邮件发送方式
+const (
+	// MailDriverSMTP 通过 SMTP 服务器发送邮件
+	MailDriverSMTP = "smtp"
+	// MailDriverFile 将邮件写入目录，用于开发和测试环境
+	MailDriverFile = "file"
+	// MailDriverLog 将邮件打印到日志，用于开发环境
+	MailDriverLog = "log"
+)
+
+// MailOptions defines options for sending verification and password reset emails.
+type MailOptions struct {
+	// Driver 定义邮件发送方式，可选值：smtp、file、log
+	Driver string `json:"driver" mapstructure:"driver"`
+	// From 定义发件人地址
+	From string `json:"from" mapstructure:"from"`
+	// SMTPHost 定义 SMTP 服务器地址
+	SMTPHost string `json:"smtp-host" mapstructure:"smtp-host"`
+	// SMTPPort 定义 SMTP 服务器端口
+	SMTPPort int `json:"smtp-port" mapstructure:"smtp-port"`
+	// SMTPUsername 定义 SMTP 认证用户名，为空时不进行认证
+	SMTPUsername string `json:"smtp-username" mapstructure:"smtp-username"`
+	// SMTPPassword 定义 SMTP 认证密码
+	SMTPPassword string `json:"smtp-password" mapstructure:"smtp-password"`
+	// FileDir 定义 file 方式下邮件的写入目录
+	FileDir string `json:"file-dir" mapstructure:"file-dir"`
+	// TemplateDir 定义自定义邮件模板目录，目录中的模板会覆盖同名的内置模板
+	TemplateDir string `json:"template-dir" mapstructure:"template-dir"`
+	// BaseURL 定义邮件中链接的前缀，通常是前端页面地址
+	BaseURL string `json:"base-url" mapstructure:"base-url"`
+	// VerifyEmailExpiration 定义验证邮箱链接的有效期
+	VerifyEmailExpiration time.Duration `json:"verify-email-expiration" mapstructure:"verify-email-expiration"`
+	// PasswordResetExpiration 定义重置密码链接的有效期
+	PasswordResetExpiration time.Duration `json:"password-reset-expiration" mapstructure:"password-reset-expiration"`
+	// RequireVerifiedEmail 定义是否只允许邮箱已验证的用户发布博文
+	RequireVerifiedEmail bool `json:"require-verified-email" mapstructure:"require-verified-email"`
+}
+
+// NewMailOptions 创建带有默认值的 MailOptions 实例
+func NewMailOptions() *MailOptions {
+	return &MailOptions{
+		Driver:                  MailDriverLog,
+		From:                    "fastgo <noreply@localhost>",
+		SMTPPort:                587,
+		FileDir:                 "_output/mail",
+		BaseURL:                 "http://127.0.0.1:6666",
+		VerifyEmailExpiration:   24 * time.Hour,
+		PasswordResetExpiration: time.Hour,
+	}
+}
+
+// Validate verifies flags passed to MailOptions.
+func (o *MailOptions) Validate() error {
+	switch o.Driver {
+	case MailDriverSMTP:
+		if o.SMTPHost == "" {
+			return fmt.Errorf("mail smtp host cannot be empty when driver is smtp")
+		}
+		if o.SMTPPort <= 0 || o.SMTPPort > 65535 {
+			return fmt.Errorf("mail smtp port must be between 1 and 65535")
+		}
+	case MailDriverFile:
+		if o.FileDir == "" {
+			return fmt.Errorf("mail file dir cannot be empty when driver is file")
+		}
+	case MailDriverLog:
+	default:
+		return fmt.Errorf("unsupported mail driver %q, must be one of: smtp, file, log", o.Driver)
+	}
+
+	if o.From == "" {
+		return fmt.Errorf("mail from address cannot be empty")
+	}
+
+	if _, err := url.ParseRequestURI(o.BaseURL); err != nil {
+		return fmt.Errorf("invalid mail base url: %w", err)
+	}
+
+	if o.VerifyEmailExpiration <= 0 || o.PasswordResetExpiration <= 0 {
+		return fmt.Errorf("mail link expiration must be greater than 0")
+	}
+
+	return nil
+}
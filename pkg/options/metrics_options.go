@@ -0,0 +1,42 @@
+package options
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// MetricsOptions defines options for the Prometheus metrics endpoint.
+type MetricsOptions struct {
+	// Addr 定义暴露 /metrics 的监听地址，与 API 监听地址分开，便于只在内网开放. 为空时不暴露指标
+	Addr string `json:"addr" mapstructure:"addr"`
+}
+
+// NewMetricsOptions 创建带有默认值的 MetricsOptions 实例
+func NewMetricsOptions() *MetricsOptions {
+	return &MetricsOptions{
+		Addr: "0.0.0.0:6667",
+	}
+}
+
+// Enabled 返回是否暴露指标
+func (o *MetricsOptions) Enabled() bool {
+	return o.Addr != ""
+}
+
+// Validate verifies flags passed to MetricsOptions.
+func (o *MetricsOptions) Validate() error {
+	if !o.Enabled() {
+		return nil
+	}
+
+	_, portStr, err := net.SplitHostPort(o.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid metrics address format '%s': '%w'", o.Addr, err)
+	}
+	if port, err := strconv.Atoi(portStr); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid metrics port: %s", portStr)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package options
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ModerationOptions defines options for content moderation.
+type ModerationOptions struct {
+	// Keywords 定义敏感关键词列表，创建或更新博文时命中任意关键词会自动提交举报（不区分大小写）
+	Keywords []string `json:"keywords" mapstructure:"keywords"`
+	// Patterns 定义敏感内容正则表达式列表，创建或更新博文时命中任意正则会自动提交举报
+	Patterns []string `json:"patterns" mapstructure:"patterns"`
+}
+
+// NewModerationOptions 创建带有默认值的 ModerationOptions 实例
+func NewModerationOptions() *ModerationOptions {
+	return &ModerationOptions{
+		Keywords: []string{},
+		Patterns: []string{},
+	}
+}
+
+// Validate verifies flags passed to ModerationOptions.
+func (o *ModerationOptions) Validate() error {
+	for _, pattern := range o.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid moderation pattern '%s': %w", pattern, err)
+		}
+	}
+
+	return nil
+}
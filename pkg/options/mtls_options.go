@@ -0,0 +1,88 @@
+package options
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+)
+
+// MTLSOptions defines options for the mutual TLS listener used by internal service callers.
+type MTLSOptions struct {
+	// Addr 定义 mTLS 监听地址，为空时不开启 mTLS 监听
+	Addr string `json:"addr" mapstructure:"addr"`
+	// CertFile 定义服务端证书文件（PEM 格式）
+	CertFile string `json:"cert-file" mapstructure:"cert-file"`
+	// KeyFile 定义服务端私钥文件（PEM 格式）
+	KeyFile string `json:"key-file" mapstructure:"key-file"`
+	// ClientCAFile 定义用于校验客户端证书的 CA 证书文件（PEM 格式，可以包含多个证书）
+	ClientCAFile string `json:"client-ca-file" mapstructure:"client-ca-file"`
+	// Services 定义客户端证书到服务调用方的映射
+	Services []ServicePrincipalOptions `json:"services" mapstructure:"services"`
+}
+
+// ServicePrincipalOptions defines how client certificates map to a service principal.
+type ServicePrincipalOptions struct {
+	// Name 定义服务名称，会作为调用方的用户名出现在日志中
+	Name string `json:"name" mapstructure:"name"`
+	// Subjects 定义匹配的证书主题，可以是 Common Name，也可以是完整的 Distinguished Name（例如 CN=billing,O=fastgo）
+	Subjects []string `json:"subjects" mapstructure:"subjects"`
+	// SANs 定义匹配的证书主题备用名称，支持 DNS 名称、URI（例如 spiffe://fastgo/billing）、邮箱和 IP
+	SANs []string `json:"sans" mapstructure:"sans"`
+	// Scopes 定义服务调用方拥有的权限范围，可选值：posts:read、posts:write、reports:write
+	Scopes []string `json:"scopes" mapstructure:"scopes"`
+}
+
+// NewMTLSOptions 创建带有默认值的 MTLSOptions 实例
+func NewMTLSOptions() *MTLSOptions {
+	return &MTLSOptions{
+		Services: []ServicePrincipalOptions{},
+	}
+}
+
+// Enabled 返回是否开启了 mTLS 监听
+func (o *MTLSOptions) Enabled() bool {
+	return o.Addr != ""
+}
+
+// Validate verifies flags passed to MTLSOptions.
+func (o *MTLSOptions) Validate() error {
+	if !o.Enabled() {
+		return nil
+	}
+
+	_, portStr, err := net.SplitHostPort(o.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid mtls address format '%s': '%w'", o.Addr, err)
+	}
+	if port, err := strconv.Atoi(portStr); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid mtls port: %s", portStr)
+	}
+
+	if o.CertFile == "" || o.KeyFile == "" {
+		return fmt.Errorf("mtls cert file and key file cannot be empty")
+	}
+	if o.ClientCAFile == "" {
+		return fmt.Errorf("mtls client ca file cannot be empty")
+	}
+
+	names := make(map[string]bool, len(o.Services))
+	for _, s := range o.Services {
+		if s.Name == "" {
+			return fmt.Errorf("mtls service name cannot be empty")
+		}
+		if names[s.Name] {
+			return fmt.Errorf("duplicate mtls service name %q", s.Name)
+		}
+		names[s.Name] = true
+
+		if len(s.Subjects) == 0 && len(s.SANs) == 0 {
+			return fmt.Errorf("mtls service %q must match at least one subject or SAN", s.Name)
+		}
+		if slices.Contains(s.Subjects, "") || slices.Contains(s.SANs, "") {
+			return fmt.Errorf("subjects and SANs of mtls service %q cannot be empty", s.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// providerNameRegexp 定义 OIDC 提供方名称的格式，名称会出现在接口路径中
+var providerNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// OIDCOptions defines options for logging in with external OpenID Connect providers.
+type OIDCOptions struct {
+	// Providers 定义可用于登录的外部 OIDC 提供方，为空时不开启外部登录
+	Providers []OIDCProviderOptions `json:"providers" mapstructure:"providers"`
+	// StateExpiration 定义从发起登录到完成回调的最长时间
+	StateExpiration time.Duration `json:"state-expiration" mapstructure:"state-expiration"`
+}
+
+// OIDCProviderOptions defines options for a single OpenID Connect provider.
+type OIDCProviderOptions struct {
+	// Name 定义提供方名称，用于接口路径和账号关联记录，例如 google、github
+	Name string `json:"name" mapstructure:"name"`
+	// DisplayName 定义展示给用户的提供方名称
+	DisplayName string `json:"display-name" mapstructure:"display-name"`
+	// Issuer 定义提供方的 Issuer 地址，服务通过 <issuer>/.well-known/openid-configuration 发现其配置
+	Issuer string `json:"issuer" mapstructure:"issuer"`
+	// ClientID 定义在提供方注册的客户端 ID
+	ClientID string `json:"client-id" mapstructure:"client-id"`
+	// ClientSecret 定义在提供方注册的客户端密钥
+	ClientSecret string `json:"client-secret" mapstructure:"client-secret"`
+	// RedirectURL 定义提供方授权完成后的回调地址，通常是前端页面地址
+	RedirectURL string `json:"redirect-url" mapstructure:"redirect-url"`
+	// Scopes 定义额外申请的权限范围，openid 会被自动添加
+	Scopes []string `json:"scopes" mapstructure:"scopes"`
+	// AutoProvision 定义外部账号首次登录且未关联本地账号时，是否自动创建本地账号
+	AutoProvision bool `json:"auto-provision" mapstructure:"auto-provision"`
+}
+
+// NewOIDCOptions 创建带有默认值的 OIDCOptions 实例
+func NewOIDCOptions() *OIDCOptions {
+	return &OIDCOptions{
+		Providers:       []OIDCProviderOptions{},
+		StateExpiration: 10 * time.Minute,
+	}
+}
+
+// Validate verifies flags passed to OIDCOptions.
+func (o *OIDCOptions) Validate() error {
+	if o.StateExpiration <= 0 {
+		return fmt.Errorf("oidc state expiration must be greater than 0")
+	}
+
+	names := make(map[string]bool, len(o.Providers))
+	for _, p := range o.Providers {
+		if !providerNameRegexp.MatchString(p.Name) {
+			return fmt.Errorf("invalid oidc provider name %q, must match %s", p.Name, providerNameRegexp)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("duplicate oidc provider name %q", p.Name)
+		}
+		names[p.Name] = true
+
+		if _, err := url.ParseRequestURI(p.Issuer); err != nil {
+			return fmt.Errorf("invalid issuer of oidc provider %q: %w", p.Name, err)
+		}
+		if _, err := url.ParseRequestURI(p.RedirectURL); err != nil {
+			return fmt.Errorf("invalid redirect url of oidc provider %q: %w", p.Name, err)
+		}
+		if p.ClientID == "" {
+			return fmt.Errorf("client id of oidc provider %q cannot be empty", p.Name)
+		}
+	}
+
+	return nil
+}
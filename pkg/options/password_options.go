@@ -0,0 +1,102 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/TobyIcetea/fastgo/pkg/auth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordOptions defines options for password policy and password hashing.
+type PasswordOptions struct {
+	// MinLength 定义密码的最小长度
+	MinLength int `json:"min-length" mapstructure:"min-length"`
+	// MaxLength 定义密码的最大长度
+	MaxLength int `json:"max-length" mapstructure:"max-length"`
+	// MinCharClasses 定义密码至少需要包含的字符类别数（小写字母、大写字母、数字、其他符号），取值 1~4
+	MinCharClasses int `json:"min-char-classes" mapstructure:"min-char-classes"`
+	// DisallowUsername 定义密码中是否不允许包含用户名
+	DisallowUsername bool `json:"disallow-username" mapstructure:"disallow-username"`
+	// BreachedListFile 定义已泄露密码列表文件，为空时不检查
+	BreachedListFile string `json:"breached-list-file" mapstructure:"breached-list-file"`
+	// HashAlgorithm 定义新密码使用的哈希算法，可选值：bcrypt、argon2id. 使用其他算法或参数的旧密码在用户下次登录时自动重新哈希
+	HashAlgorithm string `json:"hash-algorithm" mapstructure:"hash-algorithm"`
+	// BcryptCost 定义 bcrypt 的计算成本
+	BcryptCost int `json:"bcrypt-cost" mapstructure:"bcrypt-cost"`
+	// Argon2Memory 定义 argon2id 使用的内存大小（KiB）
+	Argon2Memory uint32 `json:"argon2-memory" mapstructure:"argon2-memory"`
+	// Argon2Time 定义 argon2id 的迭代次数
+	Argon2Time uint32 `json:"argon2-time" mapstructure:"argon2-time"`
+	// Argon2Threads 定义 argon2id 的并行度
+	Argon2Threads uint8 `json:"argon2-threads" mapstructure:"argon2-threads"`
+}
+
+// NewPasswordOptions 创建带有默认值的 PasswordOptions 实例
+func NewPasswordOptions() *PasswordOptions {
+	return &PasswordOptions{
+		MinLength:        8,
+		MaxLength:        64,
+		MinCharClasses:   2,
+		DisallowUsername: true,
+		HashAlgorithm:    auth.HashArgon2id,
+		BcryptCost:       bcrypt.DefaultCost,
+		Argon2Memory:     19 * 1024,
+		Argon2Time:       2,
+		Argon2Threads:    1,
+	}
+}
+
+// Validate verifies flags passed to PasswordOptions.
+func (o *PasswordOptions) Validate() error {
+	if o.MinLength <= 0 || o.MaxLength < o.MinLength {
+		return fmt.Errorf("password max length must be greater than or equal to min length, and min length must be greater than 0")
+	}
+
+	if o.MinCharClasses < 1 || o.MinCharClasses > 4 {
+		return fmt.Errorf("password min char classes must be between 1 and 4")
+	}
+
+	switch o.HashAlgorithm {
+	case auth.HashBcrypt:
+		if o.BcryptCost < bcrypt.MinCost || o.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf("password bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case auth.HashArgon2id:
+		if o.Argon2Memory < 8*uint32(o.Argon2Threads) || o.Argon2Time == 0 || o.Argon2Threads == 0 {
+			return fmt.Errorf("password argon2 time and threads must be greater than 0, and memory must be at least 8 * threads KiB")
+		}
+	default:
+		return fmt.Errorf("unsupported password hash algorithm %q, must be one of: bcrypt, argon2id", o.HashAlgorithm)
+	}
+
+	return nil
+}
+
+// HashConfig 返回新密码使用的哈希算法及参数
+func (o *PasswordOptions) HashConfig() auth.HashConfig {
+	return auth.HashConfig{
+		Algorithm:     o.HashAlgorithm,
+		BcryptCost:    o.BcryptCost,
+		Argon2Memory:  o.Argon2Memory,
+		Argon2Time:    o.Argon2Time,
+		Argon2Threads: o.Argon2Threads,
+	}
+}
+
+// NewPolicy 根据配置创建密码规则，配置了已泄露密码列表文件时一并加载
+func (o *PasswordOptions) NewPolicy() (*auth.PasswordPolicy, error) {
+	policy := &auth.PasswordPolicy{
+		MinLength:        o.MinLength,
+		MaxLength:        o.MaxLength,
+		MinCharClasses:   o.MinCharClasses,
+		DisallowUsername: o.DisallowUsername,
+	}
+
+	if o.BreachedListFile != "" {
+		if err := policy.LoadBreachedList(o.BreachedListFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,84 @@
+package options
+
+import (
+	"fmt"
+	"time"
+)
+
+// 支持的限流存储
+const (
+	// RateLimitBackendMemory 表示限流状态保存在内存中，只在当前实例内生效
+	RateLimitBackendMemory = "memory"
+	// RateLimitBackendRedis 表示限流状态保存在 Redis 中，多个实例共享
+	RateLimitBackendRedis = "redis"
+)
+
+// 支持的限流维度
+const (
+	// RateLimitKeyIP 表示按客户端 IP 限流
+	RateLimitKeyIP = "ip"
+	// RateLimitKeyUser 表示按用户限流，未认证的请求按客户端 IP 限流
+	RateLimitKeyUser = "user"
+	// RateLimitKeyRoute 表示按路由限流
+	RateLimitKeyRoute = "route"
+)
+
+// RateLimitOptions defines options for request rate limiting.
+type RateLimitOptions struct {
+	// Backend 定义限流状态的存储，可选值：memory、redis
+	Backend string `json:"backend" mapstructure:"backend"`
+	// Redis 定义 backend 为 redis 时使用的 Redis 配置
+	Redis *RedisOptions `json:"redis" mapstructure:"redis"`
+	// Rules 定义各个路由分组的限流规则，键为规则名称，未配置的规则不限流
+	Rules map[string]RateLimitRuleOptions `json:"rules" mapstructure:"rules"`
+}
+
+// RateLimitRuleOptions defines a token bucket limit for a route group.
+type RateLimitRuleOptions struct {
+	// Requests 定义在 Period 内平均允许的请求数
+	Requests int `json:"requests" mapstructure:"requests"`
+	// Period 定义统计请求数的时间窗口
+	Period time.Duration `json:"period" mapstructure:"period"`
+	// Burst 定义允许的最大突发请求数，为 0 时等于 Requests
+	Burst int `json:"burst" mapstructure:"burst"`
+	// Key 定义限流维度，可选值：ip、user、route
+	Key string `json:"key" mapstructure:"key"`
+}
+
+// NewRateLimitOptions 创建带有默认值的 RateLimitOptions 实例
+func NewRateLimitOptions() *RateLimitOptions {
+	return &RateLimitOptions{
+		Backend: RateLimitBackendMemory,
+		Redis:   NewRedisOptions(),
+		Rules:   map[string]RateLimitRuleOptions{},
+	}
+}
+
+// Validate verifies flags passed to RateLimitOptions.
+func (o *RateLimitOptions) Validate() error {
+	switch o.Backend {
+	case RateLimitBackendMemory:
+	case RateLimitBackendRedis:
+		if err := o.Redis.Validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported rate limit backend %q, must be one of: memory, redis", o.Backend)
+	}
+
+	for name, rule := range o.Rules {
+		if rule.Requests <= 0 || rule.Period <= 0 {
+			return fmt.Errorf("requests and period of rate limit rule %q must be greater than 0", name)
+		}
+		if rule.Burst < 0 {
+			return fmt.Errorf("burst of rate limit rule %q cannot be negative", name)
+		}
+		switch rule.Key {
+		case RateLimitKeyIP, RateLimitKeyUser, RateLimitKeyRoute:
+		default:
+			return fmt.Errorf("unsupported key %q of rate limit rule %q, must be one of: ip, user, route", rule.Key, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package options
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisOptions defines options for redis cluster.
+type RedisOptions struct {
+	// Addr 定义 Redis 地址，格式为 host:port
+	Addr string `json:"addr" mapstructure:"addr"`
+	// Username 定义 Redis 用户名（Redis 6 ACL），为空时只使用密码认证
+	Username string `json:"username" mapstructure:"username"`
+	// Password 定义 Redis 密码
+	Password string `json:"-" mapstructure:"password"`
+	// Database 定义使用的数据库编号
+	Database int `json:"database" mapstructure:"database"`
+	// DialTimeout 定义建立连接的超时时间
+	DialTimeout time.Duration `json:"dial-timeout" mapstructure:"dial-timeout"`
+}
+
+// NewRedisOptions 创建带有默认值的 RedisOptions 实例
+func NewRedisOptions() *RedisOptions {
+	return &RedisOptions{
+		Addr:        "127.0.0.1:6379",
+		Database:    0,
+		DialTimeout: 5 * time.Second,
+	}
+}
+
+// Validate verifies flags passed to RedisOptions.
+func (o *RedisOptions) Validate() error {
+	if o.Addr == "" {
+		return fmt.Errorf("redis address cannot be empty")
+	}
+
+	if o.Database < 0 {
+		return fmt.Errorf("redis database cannot be negative")
+	}
+
+	return nil
+}
+
+// NewClient 使用给定的配置创建 Redis 客户端，并检查 Redis 是否可以连接
+func (o *RedisOptions) NewClient() (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:        o.Addr,
+		Username:    o.Username,
+		Password:    o.Password,
+		DB:          o.Database,
+		DialTimeout: o.DialTimeout,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.DialTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to redis %s: %w", o.Addr, err)
+	}
+
+	return client, nil
+}
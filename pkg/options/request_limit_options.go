@@ -0,0 +1,67 @@
+package options
+
+import (
+	"fmt"
+	"time"
+)
+
+// RequestLimitOptions defines options for request body size limits and request deadlines.
+type RequestLimitOptions struct {
+	// MaxBodySize 定义请求体的默认最大字节数，超过时返回 413
+	MaxBodySize int64 `json:"max-body-size" mapstructure:"max-body-size"`
+	// Timeout 定义请求的默认处理时限，超过后取消数据库查询等操作并返回 504
+	Timeout time.Duration `json:"timeout" mapstructure:"timeout"`
+	// Groups 定义各个路由分组的限制，键为分组名称，未配置的分组或为 0 的字段使用默认值
+	Groups map[string]RequestLimitGroupOptions `json:"groups" mapstructure:"groups"`
+}
+
+// RequestLimitGroupOptions defines request limits for a route group.
+type RequestLimitGroupOptions struct {
+	// MaxBodySize 定义该分组请求体的最大字节数，为 0 时使用默认值
+	MaxBodySize int64 `json:"max-body-size" mapstructure:"max-body-size"`
+	// Timeout 定义该分组请求的处理时限，为 0 时使用默认值
+	Timeout time.Duration `json:"timeout" mapstructure:"timeout"`
+}
+
+// NewRequestLimitOptions 创建带有默认值的 RequestLimitOptions 实例
+func NewRequestLimitOptions() *RequestLimitOptions {
+	return &RequestLimitOptions{
+		MaxBodySize: 1 << 20,
+		Timeout:     10 * time.Second,
+		Groups:      map[string]RequestLimitGroupOptions{},
+	}
+}
+
+// Validate verifies flags passed to RequestLimitOptions.
+func (o *RequestLimitOptions) Validate() error {
+	if o.MaxBodySize <= 0 {
+		return fmt.Errorf("request max body size must be greater than 0")
+	}
+
+	if o.Timeout <= 0 {
+		return fmt.Errorf("request timeout must be greater than 0")
+	}
+
+	for name, group := range o.Groups {
+		if group.MaxBodySize < 0 || group.Timeout < 0 {
+			return fmt.Errorf("max body size and timeout of request limit group %q cannot be negative", name)
+		}
+	}
+
+	return nil
+}
+
+// Group 返回名称为 name 的路由分组的请求体最大字节数和处理时限，未配置的值使用默认值
+func (o *RequestLimitOptions) Group(name string) (int64, time.Duration) {
+	maxBodySize, timeout := o.MaxBodySize, o.Timeout
+	if group, ok := o.Groups[name]; ok {
+		if group.MaxBodySize > 0 {
+			maxBodySize = group.MaxBodySize
+		}
+		if group.Timeout > 0 {
+			timeout = group.Timeout
+		}
+	}
+
+	return maxBodySize, timeout
+}
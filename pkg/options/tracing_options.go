@@ -0,0 +1,103 @@
+package options
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+)
+
+// 支持的链路追踪导出方式
+const (
+	// TracingExporterNone 表示不导出 span，只生成并传递 trace ID
+	TracingExporterNone = "none"
+	// TracingExporterStdout 表示将 span 以 JSON 格式输出到标准输出，适合本地调试
+	TracingExporterStdout = "stdout"
+	// TracingExporterOTLP 表示通过 OTLP/HTTP 协议将 span 导出到 OpenTelemetry Collector 或兼容的后端
+	TracingExporterOTLP = "otlp"
+)
+
+// TracingOptions defines options for OpenTelemetry tracing.
+type TracingOptions struct {
+	// Exporter 定义 span 的导出方式，可选值：none、stdout、otlp
+	Exporter string `json:"exporter" mapstructure:"exporter"`
+	// Endpoint 定义 OTLP/HTTP 导出地址，格式为 host:port，exporter 为 otlp 时必填
+	Endpoint string `json:"endpoint" mapstructure:"endpoint"`
+	// Insecure 定义导出 OTLP 时是否使用 HTTP 而不是 HTTPS
+	Insecure bool `json:"insecure" mapstructure:"insecure"`
+	// SampleRatio 定义没有上游采样决策时的采样比例，取值范围 [0, 1]. 请求携带 traceparent 时沿用上游的采样决策
+	SampleRatio float64 `json:"sample-ratio" mapstructure:"sample-ratio"`
+	// ServiceName 定义上报的服务名称
+	ServiceName string `json:"service-name" mapstructure:"service-name"`
+}
+
+// NewTracingOptions 创建带有默认值的 TracingOptions 实例
+func NewTracingOptions() *TracingOptions {
+	return &TracingOptions{
+		Exporter:    TracingExporterNone,
+		SampleRatio: 1,
+		ServiceName: "fg-apiserver",
+	}
+}
+
+// Validate verifies flags passed to TracingOptions.
+func (o *TracingOptions) Validate() error {
+	switch o.Exporter {
+	case TracingExporterNone, TracingExporterStdout:
+	case TracingExporterOTLP:
+		if o.Endpoint == "" {
+			return fmt.Errorf("tracing endpoint cannot be empty when exporter is %s", TracingExporterOTLP)
+		}
+	default:
+		return fmt.Errorf("unsupported tracing exporter: %s", o.Exporter)
+	}
+
+	if o.SampleRatio < 0 || o.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample ratio must be between 0 and 1")
+	}
+
+	if o.ServiceName == "" {
+		return fmt.Errorf("tracing service name cannot be empty")
+	}
+
+	return nil
+}
+
+// NewTracerProvider 使用给定的配置创建 TracerProvider. exporter 为 none 时仍然生成 span，
+// 以便 trace ID 出现在响应和日志中，但不会导出
+func (o *TracingOptions) NewTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(o.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+	}
+
+	switch o.Exporter {
+	case TracingExporterStdout:
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	case TracingExporterOTLP:
+		clientOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(o.Endpoint)}
+		if o.Insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
@@ -0,0 +1,42 @@
+package options
+
+import (
+	"fmt"
+	"time"
+)
+
+// ViewCounterOptions defines options for buffered post view counting.
+type ViewCounterOptions struct {
+	// FlushInterval 定义内存中缓冲的浏览次数写入数据库的周期
+	FlushInterval time.Duration `json:"flush-interval" mapstructure:"flush-interval"`
+	// DedupWindow 定义同一用户（或 IP）重复浏览同一篇博文时，不重复计数的时间窗口
+	DedupWindow time.Duration `json:"dedup-window" mapstructure:"dedup-window"`
+	// DedupSize 定义内存中保存的去重记录数量上限，达到上限后新的浏览者照常计数但不再去重
+	DedupSize int `json:"dedup-size" mapstructure:"dedup-size"`
+}
+
+// NewViewCounterOptions 创建带有默认值的 ViewCounterOptions 实例
+func NewViewCounterOptions() *ViewCounterOptions {
+	return &ViewCounterOptions{
+		FlushInterval: 10 * time.Second,
+		DedupWindow:   30 * time.Minute,
+		DedupSize:     100000,
+	}
+}
+
+// Validate verifies flags passed to ViewCounterOptions.
+func (o *ViewCounterOptions) Validate() error {
+	if o.FlushInterval <= 0 {
+		return fmt.Errorf("view counter flush interval must be greater than 0")
+	}
+
+	if o.DedupWindow < 0 {
+		return fmt.Errorf("view counter dedup window cannot be negative")
+	}
+
+	if o.DedupSize <= 0 {
+		return fmt.Errorf("view counter dedup size must be greater than 0")
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package token
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	// challengeExpiration 是挑战 token 的默认有效期
+	challengeExpiration = 5 * time.Minute
+	// PurposeTwoFactor 表示两步验证登录的挑战 token
+	PurposeTwoFactor = "2fa"
+	// PurposeVerifyEmail 表示验证邮箱的 token
+	PurposeVerifyEmail = "verify-email"
+	// PurposeResetPassword 表示重置密码的 token
+	PurposeResetPassword = "reset-password"
+	// PurposeOIDCLogin 表示使用外部 OIDC 提供方登录的状态 token
+	PurposeOIDCLogin = "oidc-login"
+	// PurposeOIDCLink 表示关联外部 OIDC 账号的状态 token
+	PurposeOIDCLink = "oidc-link"
+)
+
+// SignChallenge 签发一个短期有效的挑战 token，用于多步骤的认证流程（例如两步验证登录、验证邮箱、重置密码）.
+// 挑战 token 中不包含身份验证令牌的身份声明，不能用于访问接口. expiration 为 0 时使用默认有效期.
+// fingerprint 是签发时与用户状态相关的摘要，解析时原样返回，调用方可将其与当前状态比较，
+// 状态变化后 token 即失效，从而实现一次性 token.
+func SignChallenge(identity string, purpose string, expiration time.Duration, fingerprint string) (string, time.Time, error) {
+	if expiration == 0 {
+		expiration = challengeExpiration
+	}
+
+	now := time.Now()
+	expireAt := now.Add(expiration)
+
+	claims := jwt.MapClaims{
+		"sub":     identity,            // 存放用户身份
+		"purpose": purpose,             // 挑战 token 的用途
+		"jti":     uuid.New().String(), // token 唯一标识
+		"iat":     now.Unix(),          // token 签发时间
+		"exp":     expireAt.Unix(),     // token 过期时间
+	}
+	if fingerprint != "" {
+		claims["fp"] = fingerprint
+	}
+
+	tokenString, err := sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expireAt, nil
+}
+
+// ParseChallenge 解析挑战 token，校验其用途后返回用户身份及签发时的 fingerprint
+func ParseChallenge(tokenString string, purpose string) (string, string, error) {
+	mapClaims, err := parse(tokenString, config.key)
+	if err != nil {
+		return "", "", err
+	}
+
+	if p, _ := mapClaims["purpose"].(string); p != purpose {
+		return "", "", jwt.ErrSignatureInvalid
+	}
+
+	identity, _ := mapClaims["sub"].(string)
+	if identity == "" {
+		return "", "", jwt.ErrSignatureInvalid
+	}
+
+	fingerprint, _ := mapClaims["fp"].(string)
+	return identity, fingerprint, nil
+}
@@ -0,0 +1,205 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Key 是一个用于签发或验证 token 的非对称密钥
+type Key struct {
+	// ID 是密钥的唯一标识，签发 token 时写入 header 的 kid 字段
+	ID string
+	// Method 是密钥对应的签名算法（RS256 或 EdDSA）
+	Method jwt.SigningMethod
+	// Private 是私钥，只用于验证的密钥为 nil
+	Private crypto.Signer
+	// Public 是公钥
+	Public crypto.PublicKey
+}
+
+// KeySet 是一组非对称密钥. 同一时间只有一个密钥用于签发，所有密钥都可用于验证，
+// 密钥轮换时，旧密钥在其签发的 token 过期前应保留在 KeySet 中.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// keySet 是当前使用的 KeySet，为 nil 时使用 config.key 以 HS256 签发和验证 token
+var keySet atomic.Pointer[KeySet]
+
+// SetKeySet 替换当前使用的 KeySet，可在运行时调用以重新加载密钥. 传入 nil 时回退到 HS256.
+func SetKeySet(ks *KeySet) {
+	keySet.Store(ks)
+}
+
+// NewKeySet 创建 KeySet. signingKeyID 为空时，选择 ID 按字典序最大、且带有私钥的密钥用于签发.
+func NewKeySet(signingKeyID string, keys ...*Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		if _, ok := ks.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+
+		if key.Private == nil {
+			continue
+		}
+		if (signingKeyID == "" && (ks.signing == nil || key.ID > ks.signing.ID)) || key.ID == signingKeyID {
+			ks.signing = key
+		}
+	}
+
+	if signingKeyID != "" && (ks.signing == nil || ks.signing.ID != signingKeyID) {
+		return nil, fmt.Errorf("signing key %q not found or has no private key", signingKeyID)
+	}
+	if ks.signing == nil {
+		return nil, errors.New("no private key available for signing")
+	}
+
+	return ks, nil
+}
+
+// LoadKeySet 从目录 dir 中加载 KeySet. 目录中每个 .pem 文件是一个密钥，文件名（不含扩展名）即为密钥 ID.
+// 私钥文件既可用于签发也可用于验证，公钥文件只用于验证.
+func LoadKeySet(dir string, signingKeyID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		key, err := LoadKey(strings.TrimSuffix(filepath.Base(path), ".pem"), path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(signingKeyID, keys...)
+}
+
+// LoadKey 从 PEM 文件中加载 RSA 或 Ed25519 密钥（私钥或公钥）
+func LoadKey(id string, path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %q: no PEM data found in %s", id, path)
+	}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %q: unsupported PEM block type %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", id, err)
+	}
+
+	key := &Key{ID: id}
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.Private = signer
+		parsed = signer.Public()
+	}
+
+	switch pub := parsed.(type) {
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, pub
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, pub
+	default:
+		return nil, fmt.Errorf("key %q: unsupported key type %T", id, parsed)
+	}
+
+	return key, nil
+}
+
+// JWK 是 RFC 7517 定义的 JSON Web Key，只包含公钥部分
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA 公钥参数
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519 公钥参数
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS 是 JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicJWKS 返回当前 KeySet 中所有密钥的公钥，供其他服务验证本服务签发的 token.
+// 使用 HS256 时没有可公开的密钥，返回空集合.
+func PublicJWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+
+	ks := keySet.Load()
+	if ks == nil {
+		return jwks
+	}
+
+	for _, key := range ks.keys {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	// 按 kid 排序，保证输出稳定
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].Kid < jwks.Keys[j].Kid })
+
+	return jwks
+}
+
+// verificationKey 根据 token header 中的 kid 查找验证密钥，并确认签名算法与密钥匹配
+func (ks *KeySet) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	return key.Public, nil
+}
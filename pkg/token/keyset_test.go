@@ -0,0 +1,119 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, dir string, name string, key any) {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".pem"), data, 0o600))
+}
+
+func writePublicKey(t *testing.T, dir string, name string, key crypto.PublicKey) {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".pem"), data, 0o600))
+}
+
+func TestKeySetRotation(t *testing.T) {
+	t.Cleanup(func() { SetKeySet(nil) })
+
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writeKey(t, dir, "2026-01", rsaKey)
+
+	ks, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+	SetKeySet(ks)
+
+	oldToken, _, err := Sign(Claims{Identity: "user-000001", Username: "alice", Role: "user"})
+	require.NoError(t, err)
+
+	// 轮换到新的 Ed25519 密钥，旧密钥仍保留用于验证
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writeKey(t, dir, "2026-02", edKey)
+
+	ks, err = LoadKeySet(dir, "")
+	require.NoError(t, err)
+	SetKeySet(ks)
+
+	newToken, _, err := Sign(Claims{Identity: "user-000002", Username: "bob", Role: "user"})
+	require.NoError(t, err)
+
+	claims, err := Parse(oldToken, "")
+	require.NoError(t, err)
+	assert.Equal(t, "user-000001", claims.Identity)
+
+	claims, err = Parse(newToken, "")
+	require.NoError(t, err)
+	assert.Equal(t, "user-000002", claims.Identity)
+
+	jwks := PublicJWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, JWK{Kty: "RSA", Kid: "2026-01", Use: "sig", Alg: "RS256", N: jwks.Keys[0].N, E: "AQAB"}, jwks.Keys[0])
+	assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+	assert.Equal(t, "EdDSA", jwks.Keys[1].Alg)
+
+	// 删除旧密钥后，旧密钥签发的 token 不再有效
+	require.NoError(t, os.Remove(filepath.Join(dir, "2026-01.pem")))
+	ks, err = LoadKeySet(dir, "")
+	require.NoError(t, err)
+	SetKeySet(ks)
+
+	_, err = Parse(oldToken, "")
+	assert.Error(t, err)
+}
+
+func TestKeySetRejectsHMAC(t *testing.T) {
+	t.Cleanup(func() { SetKeySet(nil) })
+
+	hmacToken, _, err := Sign(Claims{Identity: "user-000001", Username: "alice", Role: "user"})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writeKey(t, dir, "k1", edKey)
+
+	ks, err := LoadKeySet(dir, "k1")
+	require.NoError(t, err)
+	SetKeySet(ks)
+
+	_, err = Parse(hmacToken, config.key)
+	assert.Error(t, err)
+}
+
+func TestNewKeySetSigningKey(t *testing.T) {
+	dir := t.TempDir()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePublicKey(t, dir, "public-only", pub)
+
+	// 只有公钥时无法签发
+	_, err = LoadKeySet(dir, "")
+	assert.Error(t, err)
+
+	// 指定的签发密钥必须带有私钥
+	_, err = LoadKeySet(dir, "public-only")
+	assert.Error(t, err)
+}
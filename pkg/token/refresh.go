@@ -0,0 +1,38 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// SignRefresh 签发一个不透明（opaque）的 refresh token.
+// 返回 token 明文（只返回给客户端一次）、用于持久化的 token 哈希值以及过期时间.
+func SignRefresh() (string, string, time.Time, error) {
+	plain, hash, err := GenerateOpaque()
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	return plain, hash, time.Now().Add(config.refreshExpiration), nil
+}
+
+// GenerateOpaque 生成一个随机的不透明 token，返回 token 明文和哈希值.
+// 服务端只应保存哈希值，数据库泄露时也无法还原出可用的 token.
+func GenerateOpaque() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	plain := base64.RawURLEncoding.EncodeToString(buf)
+	return plain, HashOpaque(plain), nil
+}
+
+// HashOpaque 计算不透明 token 的哈希值. token 本身是高熵的随机值，使用 SHA-256 即可.
+func HashOpaque(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,11 +3,13 @@ package token
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 // Config 包括 token 包的配置选项
@@ -18,15 +20,17 @@ type Config struct {
 	identityKey string
 	// expiration 是签发的 token 过期时间
 	expiration time.Duration
+	// refreshExpiration 是签发的 refresh token 过期时间
+	refreshExpiration time.Duration
 }
 
 var (
-	config = Config{"Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5", "identityKey", 2 * time.Hour}
+	config = Config{"Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5", "identityKey", 15 * time.Minute, 30 * 24 * time.Hour}
 	once   sync.Once // 确保配置只被初始化一次
 )
 
 // Init 设置包级别的配置 config，config 会用于本包后面的 token 签发和解析
-func Init(key string, identityKey string, expiration time.Duration) {
+func Init(key string, identityKey string, expiration time.Duration, refreshExpiration time.Duration) {
 	once.Do(func() {
 		if key != "" {
 			config.key = key // 设置密钥
@@ -37,49 +41,75 @@ func Init(key string, identityKey string, expiration time.Duration) {
 		if expiration != 0 {
 			config.expiration = expiration
 		}
+		if refreshExpiration != 0 {
+			config.refreshExpiration = refreshExpiration
+		}
 	})
 }
 
-// Parse 使用指定的密钥 key 解析 token，解析成功返回 token 上下文，否则报错
-func Parse(tokenString string, key string) (string, error) {
-	// 解析 token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// 确保 token 加密算法是预期的加密算法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
-		}
+// Claims 定义 token 中的声明. 解析 token 时返回其中的声明，签发 token 时用于传入需要写入的声明
+type Claims struct {
+	// Identity 是 token 中 identityKey 对应的用户身份
+	Identity string
+	// Username 是 token 签发时用户的用户名
+	Username string
+	// Role 是 token 签发时用户的角色
+	Role string
+	// ID 是 token 的唯一标识（jti），用于吊销单个 token
+	ID string
+	// SessionID 是签发 token 的登录会话 ID（sid），会话被吊销后 token 随即失效
+	SessionID string
+	// Actor 是模拟登录 token 中实际操作者的用户身份（act 声明），普通 token 为空
+	Actor string
+	// IssuedAt 是 token 的签发时间（毫秒精度）
+	IssuedAt time.Time
+	// ExpiresAt 是 token 的过期时间
+	ExpiresAt time.Time
+}
 
-		return []byte(key), nil // 返回密钥
-	})
-	// 解析失败
+// Parse 解析身份验证令牌，解析成功返回 token 中的声明，否则报错.
+func Parse(tokenString string, key string) (*Claims, error) {
+	mapClaims, err := parse(tokenString, key)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var identityKey string
-	// 如果解析成功，从 token 中取出 token 的主题
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if key, exists := claims[config.identityKey]; exists {
-			if identity, valid := key.(string); valid {
-				identityKey = identity // 获取身份键
-			}
-		}
+	// 带有 purpose 的 token 是特定用途的 token（例如两步验证的挑战 token），不能作为身份验证令牌使用
+	if _, ok := mapClaims["purpose"]; ok {
+		return nil, jwt.ErrSignatureInvalid
+	}
 
+	// 从 token 中取出用户身份及其他声明
+	claims := &Claims{}
+	claims.Identity, _ = mapClaims[config.identityKey].(string)
+	claims.Username, _ = mapClaims["username"].(string)
+	claims.Role, _ = mapClaims["role"].(string)
+	claims.ID, _ = mapClaims["jti"].(string)
+	claims.SessionID, _ = mapClaims["sid"].(string)
+	if act, ok := mapClaims["act"].(map[string]any); ok {
+		claims.Actor, _ = act["sub"].(string)
+	}
+	if iat, ok := mapClaims["iat"].(float64); ok {
+		claims.IssuedAt = time.UnixMilli(int64(math.Round(iat * 1000)))
 	}
-	if identityKey == "" {
-		return "", jwt.ErrSignatureInvalid
+	if exp, ok := mapClaims["exp"].(float64); ok {
+		claims.ExpiresAt = time.Unix(int64(exp), 0)
 	}
 
-	return identityKey, nil
+	if claims.Identity == "" {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	return claims, nil
 }
 
 // ParseRequest 从请求头中获取令牌，并将其传递给 Parse 函数以解析令牌.
-func ParseRequest(c *gin.Context) (string, error) {
+func ParseRequest(c *gin.Context) (*Claims, error) {
 	header := c.Request.Header.Get("Authorization")
 
 	if len(header) == 0 {
 		//nolint: err113
-		return "", errors.New("the length of the `Authorization` header is zero") // 返回错误
+		return nil, errors.New("the length of the `Authorization` header is zero") // 返回错误
 	}
 
 	var token string
@@ -89,27 +119,111 @@ func ParseRequest(c *gin.Context) (string, error) {
 	return Parse(token, config.key)
 }
 
-// Sign 使用 jwtSecret 签发 token，token 的 claims 中会存放传入的 subject.
-func Sign(identityKey string) (string, time.Time, error) {
+// Sign 签发 token，token 的 claims 中会存放 claims 中的用户身份 Identity、用户名 Username、角色 Role 及登录会话 ID SessionID，
+// claims 的其他字段会被忽略. 每个 token 都带有唯一的 jti，签发时间 iat 精确到毫秒，以便按时间点吊销用户的所有 token.
+func Sign(claims Claims) (string, time.Time, error) {
+	now := time.Now()
 	// 计算过期时间
-	expireAt := time.Now().Add(config.expiration)
+	expireAt := now.Add(config.expiration)
 
 	// Token 的内容
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		config.identityKey: identityKey,       // 存放用户身份
-		"nbf":              time.Now().Unix(), // token 生效时间
-		"iat":              time.Now().Unix(), // token 签发时间
-		"exp":              expireAt.Unix(),   // token 过期时间
-	})
-	if config.key == "" {
-		return "", time.Time{}, jwt.ErrInvalidKey
+	mapClaims := jwt.MapClaims{
+		config.identityKey: claims.Identity,                 // 存放用户身份
+		"username":         claims.Username,                 // 存放用户名
+		"role":             claims.Role,                     // 存放用户角色
+		"sid":              claims.SessionID,                // 存放登录会话 ID
+		"jti":              uuid.New().String(),             // token 唯一标识
+		"nbf":              now.Unix(),                      // token 生效时间
+		"iat":              float64(now.UnixMilli()) / 1000, // token 签发时间
+		"exp":              expireAt.Unix(),                 // token 过期时间
 	}
 
-	// 签发 token
-	tokenString, err := token.SignedString([]byte(config.key))
+	tokenString, err := sign(mapClaims)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
 	return tokenString, expireAt, nil // 返回 token 字符串、过期时间和错误
 }
+
+// SignImpersonation 签发模拟登录 token. token 以被模拟用户 claims.Identity（用户名 Username）及其角色 Role 的身份访问接口，
+// 同时在 act 声明（RFC 8693）中记录实际操作者 Actor. claims.ID 作为 token 的 jti，用于提前结束模拟登录.
+// 模拟登录 token 不属于任何登录会话，也不会签发 refresh token，claims 的其他字段会被忽略.
+func SignImpersonation(claims Claims, expiration time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expireAt := now.Add(expiration)
+
+	mapClaims := jwt.MapClaims{
+		config.identityKey: claims.Identity,                     // 存放被模拟用户的身份
+		"username":         claims.Username,                     // 存放被模拟用户的用户名
+		"role":             claims.Role,                         // 存放被模拟用户的角色
+		"act":              map[string]any{"sub": claims.Actor}, // 存放实际操作者的身份
+		"jti":              claims.ID,                           // token 唯一标识
+		"nbf":              now.Unix(),                          // token 生效时间
+		"iat":              float64(now.UnixMilli()) / 1000,     // token 签发时间
+		"exp":              expireAt.Unix(),                     // token 过期时间
+	}
+
+	tokenString, err := sign(mapClaims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expireAt, nil
+}
+
+// parse 校验 token 签名及有效期，返回 token 中的所有声明.
+// 配置了 KeySet 时，根据 header 中的 kid 选择公钥验证；否则使用指定的密钥 key 以 HS256 验证.
+func parse(tokenString string, key string) (jwt.MapClaims, error) {
+	ks := keySet.Load()
+
+	// 解析 token
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if ks != nil {
+			return ks.verificationKey(token)
+		}
+
+		// 确保 token 加密算法是预期的加密算法
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+
+		return []byte(key), nil // 返回密钥
+	})
+	// 解析失败
+	if err != nil {
+		return nil, err
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	return mapClaims, nil
+}
+
+// sign 使用当前的签名密钥签发包含 claims 的 token
+func sign(claims jwt.MapClaims) (string, error) {
+	// 配置了 KeySet 时使用当前签发密钥，并在 header 中写入 kid，便于验证方选择公钥
+	if ks := keySet.Load(); ks != nil {
+		token := jwt.NewWithClaims(ks.signing.Method, claims)
+		token.Header["kid"] = ks.signing.ID
+		return token.SignedString(ks.signing.Private)
+	}
+
+	if config.key == "" {
+		return "", jwt.ErrInvalidKey
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.key))
+}
+
+// Expiration 返回签发的 token 的有效期
+func Expiration() time.Duration {
+	return config.expiration
+}
+
+// RefreshExpiration 返回签发的 refresh token 的有效期
+func RefreshExpiration() time.Duration {
+	return config.refreshExpiration
+}
@@ -0,0 +1,29 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignImpersonation(t *testing.T) {
+	tokenString, expireAt, err := SignImpersonation(Claims{Identity: "user-000002", Username: "bob", Role: "user", Actor: "user-000001", ID: "imp-000001"}, 5*time.Minute)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), expireAt, time.Second)
+
+	claims, err := Parse(tokenString, config.key)
+	require.NoError(t, err)
+	assert.Equal(t, "user-000002", claims.Identity)
+	assert.Equal(t, "bob", claims.Username)
+	assert.Equal(t, "user-000001", claims.Actor)
+	assert.Equal(t, "imp-000001", claims.ID)
+	assert.Empty(t, claims.SessionID)
+
+	tokenString, _, err = Sign(Claims{Identity: "user-000001", Username: "admin", Role: "admin"})
+	require.NoError(t, err)
+	claims, err = Parse(tokenString, config.key)
+	require.NoError(t, err)
+	assert.Empty(t, claims.Actor)
+}